@@ -0,0 +1,277 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"errors"
+	"io"
+	"sync/atomic"
+)
+
+// ErrFull is returned by AsyncWriter when its queue is full
+var ErrFull = errors.New("yell: async queue is full")
+
+// ErrClosed is returned by AsyncWriter, ShardedWriter & EncryptWriter after Close
+var ErrClosed = errors.New("yell: writer is closed")
+
+// AsyncWriter decouples logging goroutines from slow writers. Write copies records into a
+// bounded lock-free queue and returns, a background goroutine writes them in order to the
+// underlying writer, merging queued records into single writes. Records that do not fit
+// into the queue are dropped with ErrFull and counted. Flush waits for queued records.
+// Severe records bypass the queue, see SetSyncLevel.
+//
+//	aw := yell.NewAsyncWriter(file, 4096)
+//	lg := yell.New(": mypkg:", aw, yell.Sinfo)
+//	defer lg.Close() // writes queued records, closes file
+type AsyncWriter struct {
+	dropped uint64 // accessed atomically, first for alignment
+	closed  int32  // accessed atomically
+
+	q        *mpsc
+	writer   io.Writer
+	sleeping int32         // consumer waits for wake, accessed atomically
+	wake     chan struct{} // signals new records to consumer
+	flush    chan flushReq
+	stop     chan chan error
+	done     chan struct{} // closed when background goroutine stops
+}
+
+// flushReq asks background goroutine of AsyncWriter to write queued records, then rec
+// if it is not nil, and flush underlying writer
+type flushReq struct {
+	rec   []byte
+	reply chan error
+}
+
+// maxBatch bounds merged writes of AsyncWriter
+const maxBatch = 64 << 10
+
+// syncWriter is implemented by writers that write asynchronously, see SetSyncLevel
+type syncWriter interface {
+	// writeSync writes pending records, then p directly to underlying writer, and flushes it
+	writeSync(p []byte) error
+}
+
+// SetSyncLevel sets minimum severity of records that are written synchronously to
+// AsyncWriter & ShardedWriter: pending records are written first, then the record itself
+// directly to underlying writer, which is then flushed. So the most important records are
+// not lost to a crash. Default is Sfatal, levels above Sfatal are ignored. It should be
+// called before Logger is used.
+func (lg *Logger) SetSyncLevel(level Severity) {
+	if level <= Sfatal {
+		lg.syncLevel = level
+	}
+}
+
+// writeSync writes p synchronously if writer is a syncWriter, otherwise like write
+func (out *output) writeSync(level Severity, p []byte) error {
+	sw, ok := out.writer.(syncWriter)
+	if !ok {
+		return out.write(level, p)
+	}
+	if out.lc != nil {
+		out.lc.Lock()
+		defer out.lc.Unlock()
+	}
+	return sw.writeSync(p)
+}
+
+// NewAsyncWriter creates an AsyncWriter for writer with a queue of size records, rounded
+// up to a power of two (at least 2). Panics if writer is nil or size is not positive.
+func NewAsyncWriter(writer io.Writer, size int) *AsyncWriter {
+	if writer == nil || size <= 0 {
+		panic("yell: invalid arguments to NewAsyncWriter")
+	}
+	aw := &AsyncWriter{q: newMPSC(size), writer: writer, wake: make(chan struct{}, 1),
+		flush: make(chan flushReq), stop: make(chan chan error), done: make(chan struct{})}
+	go aw.run()
+	return aw
+}
+
+// Write queues a copy of p, returns ErrFull if queue is full
+func (aw *AsyncWriter) Write(p []byte) (int, error) {
+	if atomic.LoadInt32(&aw.closed) != 0 {
+		return 0, ErrClosed
+	}
+	if !aw.q.enqueue(append([]byte(nil), p...)) {
+		atomic.AddUint64(&aw.dropped, 1)
+		return 0, ErrFull
+	}
+	if atomic.LoadInt32(&aw.sleeping) != 0 {
+		select {
+		case aw.wake <- struct{}{}:
+		default:
+		}
+	}
+	return len(p), nil
+}
+
+// Dropped returns number of records dropped due to full queue
+func (aw *AsyncWriter) Dropped() uint64 {
+	return atomic.LoadUint64(&aw.dropped)
+}
+
+// Flush waits for queued records to be written and flushes underlying writer. Returns
+// the first write error since previous Flush, if any.
+func (aw *AsyncWriter) Flush() error {
+	return aw.writeSync(nil)
+}
+
+// writeSync writes queued records, then p if it is not nil, and flushes underlying writer
+func (aw *AsyncWriter) writeSync(p []byte) error {
+	req := flushReq{p, make(chan error)}
+	select {
+	case aw.flush <- req:
+		return <-req.reply
+	case <-aw.done:
+		return ErrClosed
+	}
+}
+
+// Close writes queued records, stops background goroutine and closes underlying writer
+// (see Logger.Close). Records written concurrently with Close may be dropped.
+func (aw *AsyncWriter) Close() error {
+	if !atomic.CompareAndSwapInt32(&aw.closed, 0, 1) {
+		return ErrClosed
+	}
+	reply := make(chan error)
+	aw.stop <- reply
+	return <-reply
+}
+
+// run writes queued records until Close
+func (aw *AsyncWriter) run() {
+	var (
+		batch []byte
+		err   error // first write error
+	)
+	drain := func() {
+		for {
+			p, ok := aw.q.dequeue()
+			if ok && len(batch)+len(p) <= maxBatch {
+				batch = append(batch, p...)
+				continue
+			}
+			if len(batch) > 0 {
+				if _, e := aw.writer.Write(batch); err == nil {
+					err = e
+				}
+				batch = batch[:0]
+				if cap(batch) > 2*maxBatch { // after a huge record
+					batch = nil
+				}
+			}
+			if !ok {
+				return
+			}
+			batch = append(batch, p...)
+		}
+	}
+
+	for {
+		drain()
+		atomic.StoreInt32(&aw.sleeping, 1)
+		if !aw.q.empty() { // record arrived before sleeping was set
+			atomic.StoreInt32(&aw.sleeping, 0)
+			continue
+		}
+
+		select {
+		case <-aw.wake:
+		case req := <-aw.flush:
+			drain()
+			if req.rec == nil { // Flush reports write errors of queued records
+				if e := flushWriter(aw.writer); err == nil {
+					err = e
+				}
+				req.reply <- err
+				err = nil
+				break
+			}
+			_, e := aw.writer.Write(req.rec)
+			if f := flushWriter(aw.writer); e == nil {
+				e = f
+			}
+			req.reply <- e
+		case reply := <-aw.stop:
+			drain()
+			if e := closeWriter(aw.writer); err == nil {
+				err = e
+			}
+			close(aw.done)
+			reply <- err
+			return
+		}
+		atomic.StoreInt32(&aw.sleeping, 0)
+	}
+}
+
+// mpsc is a bounded lock-free multi-producer single-consumer queue of records. Each slot
+// has a sequence number telling whether it is free for a position or holds its record.
+type mpsc struct {
+	tail uint64 // next position of producers, accessed atomically
+	_    [56]byte
+	head uint64 // next position of consumer
+	_    [56]byte
+
+	mask  uint64
+	slots []slot
+}
+
+type slot struct {
+	seq uint64 // position + 1 if record is ready, accessed atomically
+	rec []byte
+}
+
+// newMPSC creates a queue of at least size (and 2) slots
+func newMPSC(size int) *mpsc {
+	n := 2 // sequence numbers of a single slot are ambiguous
+	for n < size {
+		n <<= 1
+	}
+	q := &mpsc{mask: uint64(n - 1), slots: make([]slot, n)}
+	for i := range q.slots {
+		q.slots[i].seq = uint64(i)
+	}
+	return q
+}
+
+// enqueue adds p to queue, returns false if queue is full
+func (q *mpsc) enqueue(p []byte) bool {
+	for {
+		pos := atomic.LoadUint64(&q.tail)
+		s := &q.slots[pos&q.mask]
+		switch d := int64(atomic.LoadUint64(&s.seq) - pos); {
+		case d == 0: // free slot, claim it
+			if atomic.CompareAndSwapUint64(&q.tail, pos, pos+1) {
+				s.rec = p
+				atomic.StoreUint64(&s.seq, pos+1)
+				return true
+			}
+		case d < 0: // slot still holds a record of previous lap
+			return false
+		}
+	}
+}
+
+// dequeue removes next record from queue, returns false if it is not ready
+func (q *mpsc) dequeue() ([]byte, bool) {
+	s := &q.slots[q.head&q.mask]
+	if atomic.LoadUint64(&s.seq) != q.head+1 {
+		return nil, false
+	}
+	p := s.rec
+	s.rec = nil
+	atomic.StoreUint64(&s.seq, q.head+q.mask+1) // free for next lap
+	q.head++
+	return p, true
+}
+
+// empty reports whether next record is not ready, called by consumer
+func (q *mpsc) empty() bool {
+	return atomic.LoadUint64(&q.slots[q.head&q.mask].seq) != q.head+1
+}
@@ -0,0 +1,234 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// closeBuffer records Close calls
+type closeBuffer struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (cb *closeBuffer) Close() error {
+	cb.closed = true
+	return nil
+}
+
+func TestAsyncWriter(t *testing.T) {
+	var cb closeBuffer
+	aw := NewAsyncWriter(&cb, 1000)
+	if len(aw.q.slots) != 1024 {
+		t.Fatal("queue size must be a power of two:", len(aw.q.slots))
+	}
+	lg := New(": as:", aw, Sinfo)
+	lg.SetTimeMode(Tnone)
+
+	const workers, n = 8, 100
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				if err := lg.Log(Sinfo, w, i); err != nil {
+					t.Error(err)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	if err := lg.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	// all records, in order per worker
+	next := make([]int, workers)
+	lines := strings.Split(strings.TrimSuffix(cb.String(), "\n"), "\n")
+	if len(lines) != workers*n {
+		t.Fatal("missing records:", len(lines))
+	}
+	for _, l := range lines {
+		var w, i int
+		s := strings.Fields(l)
+		if len(s) < 2 {
+			t.Fatal("bad record:", l)
+		}
+		w, _ = strconv.Atoi(s[len(s)-2])
+		i, _ = strconv.Atoi(s[len(s)-1])
+		if next[w] != i {
+			t.Fatal("out of order:", l)
+		}
+		next[w]++
+	}
+
+	lg.Log(Swarn, "last")
+	if err := lg.Close(); err != nil || !cb.closed || !strings.HasSuffix(cb.String(), " last\n") {
+		t.Fatal("close must write queued records", err)
+	}
+	if _, err := aw.Write([]byte("x\n")); err != ErrClosed {
+		t.Fatal("expected ErrClosed:", err)
+	}
+	if aw.Flush() != ErrClosed || aw.Close() != ErrClosed {
+		t.Fatal("expected ErrClosed")
+	}
+}
+
+// flushBuffer counts Flush calls
+type flushBuffer struct {
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	flushes int
+}
+
+func (fb *flushBuffer) Write(p []byte) (int, error) {
+	fb.mu.Lock()
+	defer fb.mu.Unlock()
+	return fb.buf.Write(p)
+}
+
+func (fb *flushBuffer) Flush() error {
+	fb.mu.Lock()
+	defer fb.mu.Unlock()
+	fb.flushes++
+	return nil
+}
+
+// state returns records & flush count
+func (fb *flushBuffer) state() (string, int) {
+	fb.mu.Lock()
+	defer fb.mu.Unlock()
+	return fb.buf.String(), fb.flushes
+}
+
+func TestSyncLevel(t *testing.T) {
+	for _, sharded := range []bool{false, true} {
+		var (
+			fb flushBuffer
+			w  io.WriteCloser = NewAsyncWriter(&fb, 16)
+		)
+		if sharded {
+			w = NewShardedWriter(&fb, time.Hour)
+		}
+		lg := New(": sy:", w, Sinfo)
+		lg.SetTimeMode(Tnone)
+		if lg.syncLevel != Sfatal {
+			t.Fatal("default sync level must be fatal")
+		}
+		lg.SetSyncLevel(Snolog)
+		lg.SetSyncLevel(Serror)
+
+		lg.Log(Sinfo, "one")
+		lg.Log(Swarn, "two")
+		lg.Log(Serror, "three")
+		if s, n := fb.state(); n != 1 || !strings.Contains(s, " one\n") ||
+			!strings.Contains(s, " two\n") || !strings.HasSuffix(s, " three\n") {
+			t.Fatal("severe record must be written in order & flushed:", sharded, n, s)
+		}
+		lg.Log(Sinfo, "four")
+		lg.Log(Sfatal, "five")
+		if s, n := fb.state(); n != 2 || !strings.HasSuffix(s, " five\n") ||
+			!strings.Contains(s, " four\n") {
+			t.Fatal("fatal record must be written synchronously:", sharded, n, s)
+		}
+		w.Close()
+		if lg.Log(Sfatal, "closed") == nil {
+			t.Fatal("expected ErrClosed")
+		}
+	}
+}
+
+func TestMPSCFull(t *testing.T) {
+	q := newMPSC(3)
+	for i := 0; i < 4; i++ {
+		if !q.enqueue([]byte{byte(i)}) {
+			t.Fatal("enqueue failed:", i)
+		}
+	}
+	if q.enqueue(nil) {
+		t.Fatal("queue must be full")
+	}
+	for lap := 0; lap < 3; lap++ {
+		for i := 0; i < 4; i++ {
+			p, ok := q.dequeue()
+			if !ok || p[0] != byte(i) {
+				t.Fatal("bad dequeue:", lap, i, p)
+			}
+			q.enqueue([]byte{byte(i)})
+		}
+	}
+	q = newMPSC(1)
+	if _, ok := q.dequeue(); ok || !q.empty() || len(q.slots) != 2 {
+		t.Fatal("queue must be empty")
+	}
+
+	// dropped records
+	aw := &AsyncWriter{q: q, writer: ioutil.Discard, wake: make(chan struct{}, 1)}
+	aw.Write([]byte("a"))
+	aw.Write([]byte("a"))
+	if _, err := aw.Write([]byte("b")); err != ErrFull || aw.Dropped() != 1 {
+		t.Fatal("expected ErrFull:", err)
+	}
+}
+
+// benchmarks of queues with many producers & a consumer, producers drop records if
+// queue is full like AsyncWriter
+
+func BenchmarkMPSC(b *testing.B) {
+	q := newMPSC(4096)
+	done := make(chan struct{})
+	go func() {
+		for {
+			if _, ok := q.dequeue(); !ok {
+				select {
+				case <-done:
+					return
+				default:
+					runtime.Gosched()
+				}
+			}
+		}
+	}()
+	p := []byte("record\n")
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			q.enqueue(p)
+		}
+	})
+	close(done)
+}
+
+func BenchmarkChannel(b *testing.B) {
+	ch := make(chan []byte, 4096)
+	done := make(chan struct{})
+	go func() {
+		for range ch {
+		}
+		close(done)
+	}()
+	p := []byte("record\n")
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			select {
+			case ch <- p:
+			default:
+			}
+		}
+	})
+	close(ch)
+	<-done
+}
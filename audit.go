@@ -0,0 +1,193 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// AuditWriter makes logs tamper-evident: every record written through it ends with a
+// SHA-256 hash chained from the previous record's hash, so altering, removing or
+// reordering records breaks the chain, see VerifyAudit. Hash is appended to the last
+// line of a record as " chain=HEX", or as a "chain" key for JSON objects. Each Write must
+// be a complete record, as Logger does. It is safe for concurrent use.
+//
+// Removing records from the end of a log leaves a valid chain, store Checkpoint apart
+// from the log (or at least on Close) to detect that with VerifyCheckpoint.
+type AuditWriter struct {
+	mu      sync.Mutex
+	writer  io.Writer
+	last    [sha256.Size]byte
+	records int
+}
+
+// NewAuditWriter creates an AuditWriter for writer. prev is the last hash of an existing
+// audit log to continue its chain (see Last), or nil to start a new chain.
+func NewAuditWriter(writer io.Writer, prev []byte) *AuditWriter {
+	aw := &AuditWriter{writer: writer}
+	copy(aw.last[:], prev)
+	return aw
+}
+
+// chain returns hash of record chained from prev
+func chain(prev *[sha256.Size]byte, record []byte) (h [sha256.Size]byte) {
+	s := sha256.New()
+	s.Write(prev[:])
+	s.Write(record)
+	s.Sum(h[:0])
+	return
+}
+
+// Write appends chained hash to record p and writes it to underlying writer
+func (aw *AuditWriter) Write(p []byte) (int, error) {
+	if len(p) == 0 || p[len(p)-1] != '\n' {
+		return 0, errors.New("yell: audit record must end with newline")
+	}
+
+	aw.mu.Lock()
+	defer aw.mu.Unlock()
+
+	h := chain(&aw.last, p)
+	rec := appendTag(make([]byte, 0, len(p)+80), p, "chain", h[:])
+
+	if _, err := aw.writer.Write(rec); err != nil {
+		return 0, err
+	}
+	aw.last = h
+	aw.records++
+	return len(p), nil
+}
+
+// Last returns hash of the last record written
+func (aw *AuditWriter) Last() []byte {
+	aw.mu.Lock()
+	defer aw.mu.Unlock()
+	return append([]byte(nil), aw.last[:]...)
+}
+
+// Checkpoint returns number of records written by aw and hash of the last record
+func (aw *AuditWriter) Checkpoint() (records int, last []byte) {
+	aw.mu.Lock()
+	defer aw.mu.Unlock()
+	return aw.records, append([]byte(nil), aw.last[:]...)
+}
+
+// AuditError describes where an audit log fails verification
+type AuditError struct {
+	Line   int    // line number of the offending record end, starting from 1
+	Reason string // what is wrong
+}
+
+func (e *AuditError) Error() string {
+	return fmt.Sprintf("yell: audit log line %d: %s", e.Line, e.Reason)
+}
+
+// hex encoded hash length
+const chainLen = 2 * sha256.Size
+
+// appendTag appends record p (ending with newline) to buf with hex encoded sum added to
+// its last line as " key=HEX", or as a "key" field for JSON records, which are single
+// JSON objects
+func appendTag(buf, p []byte, key string, sum []byte) []byte {
+	obj := p[0] == '{' && json.Valid(p[:len(p)-1])
+	buf = append(buf, p[:len(p)-1]...)
+
+	if obj {
+		buf = append(append(append(buf[:len(buf)-1], `,"`...), key...), `":"`...)
+	} else {
+		buf = append(append(append(buf, ' '), key...), '=')
+	}
+	buf = append(buf, hex.EncodeToString(sum)...)
+	if obj {
+		buf = append(buf, `"}`...)
+	}
+	return append(buf, '\n')
+}
+
+// cutTag removes tag added by appendTag with n hex digits from line. Returns line as it
+// was before appendTag and the hex sum, or nil sum if line has no such tag.
+func cutTag(line []byte, key string, n int) ([]byte, []byte) {
+	if bytes.HasSuffix(line, []byte(`"}`)) {
+		tag := `,"` + key + `":"`
+		if i := len(line) - n - len(tag) - 2; i >= 0 && string(line[i:i+len(tag)]) == tag {
+			return append(line[:i:i], '}'), line[i+len(tag) : len(line)-2]
+		}
+		return line, nil
+	}
+	tag := " " + key + "="
+	if i := len(line) - n - len(tag); i >= 0 && string(line[i:i+len(tag)]) == tag {
+		return line[:i], line[i+len(tag):]
+	}
+	return line, nil
+}
+
+// VerifyAudit reads an audit log written by AuditWriter and checks its hash chain,
+// starting with prev (nil for a new chain). Returns number of verified records and the
+// last hash. Returns *AuditError if the chain is broken, which means the log has been
+// altered after the fact. Records removed from the end are not detected, compare the
+// result with a checkpoint for that, see VerifyCheckpoint.
+func VerifyAudit(r io.Reader, prev []byte) (records int, last []byte, err error) {
+	records, last, _, err = verifyAudit(r, prev)
+	return
+}
+
+// VerifyCheckpoint is like VerifyAudit but also requires the log to end with the records
+// and last hash of a Checkpoint, so records removed from the end are detected as well.
+func VerifyCheckpoint(r io.Reader, prev []byte, records int, last []byte) error {
+	n, h, ln, err := verifyAudit(r, prev)
+	if err == nil && (n != records || !bytes.Equal(h, last)) {
+		reason := "checkpoint mismatch"
+		if n < records {
+			reason = "records missing at end"
+		}
+		err = &AuditError{ln, reason}
+	}
+	return err
+}
+
+// verifyAudit implements VerifyAudit, also returns number of lines read
+func verifyAudit(r io.Reader, prev []byte) (records int, last []byte, ln int, err error) {
+	var h [sha256.Size]byte
+	copy(h[:], prev)
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64<<10), 16<<20)
+	var record []byte // lines of current record
+
+	for sc.Scan() {
+		ln++
+		line, sum := cutTag(sc.Bytes(), "chain", chainLen)
+		record = append(append(record, line...), '\n')
+		if sum == nil {
+			continue // record continues
+		}
+
+		var want [sha256.Size]byte
+		if _, err := hex.Decode(want[:], sum); err != nil {
+			return records, h[:], ln, &AuditError{ln, "invalid hash"}
+		}
+		if chain(&h, record) != want {
+			return records, h[:], ln, &AuditError{ln, "hash mismatch"}
+		}
+		h = want
+		records++
+		record = record[:0]
+	}
+
+	if err = sc.Err(); err == nil && len(record) > 0 {
+		err = &AuditError{ln, "incomplete last record"}
+	}
+	return records, h[:], ln, err
+}
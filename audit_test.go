@@ -0,0 +1,76 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestAudit(t *testing.T) {
+	var buf bytes.Buffer
+	aw := NewAuditWriter(&buf, nil)
+	lg := New(": aud:", aw, Sinfo)
+	lg.SetErrorDetail(true)
+
+	lg.Log(Sinfo, "text", 1)
+	lg.Log(Sinfo, "point", struct{ X, Y int }{1, 2})
+	lg.Log(Serror, "detailed", fmt.Errorf("a: %w", errors.New("b")))
+	lg.SetFormat(Fjson)
+	lg.Log(Swarn, "json", 3)
+
+	log := buf.String()
+	n, last, err := VerifyAudit(strings.NewReader(log), nil)
+	if err != nil || n != 4 || !bytes.Equal(last, aw.Last()) ||
+		!strings.Contains(log, "point {1 2} chain=") {
+		t.Fatal("must verify:", n, err, log)
+	}
+
+	records, sum := aw.Checkpoint()
+	if err = VerifyCheckpoint(strings.NewReader(log), nil, records, sum); err != nil {
+		t.Fatal("must verify checkpoint:", err)
+	}
+	truncated := log[:strings.LastIndex(log[:len(log)-1], "\n")+1]
+	if n, _, err = VerifyAudit(strings.NewReader(truncated), nil); err != nil || n != 3 {
+		t.Fatal("truncated log has valid chain:", n, err)
+	}
+	var ae *AuditError
+	if err = VerifyCheckpoint(strings.NewReader(truncated), nil, records, sum); !errors.As(
+		err, &ae) || ae.Reason != "records missing at end" {
+		t.Fatal("must detect truncation:", err)
+	}
+
+	// continue chain
+	var buf2 bytes.Buffer
+	lg.UpdateWriter(NewAuditWriter(&buf2, last))
+	lg.Log(Swarn, "continued")
+	if n, _, err := VerifyAudit(&buf2, last); err != nil || n != 1 {
+		t.Fatal("must verify continued chain:", n, err)
+	}
+
+	tampered := [...]string{
+		strings.Replace(log, "text 1", "text 2", 1),          // altered
+		log[strings.IndexByte(log, '\n')+1:],                 // removed
+		strings.Replace(log, "chain=", "chain=0", 1),         // invalid
+		log[:len(log)-1] + "\nextra\n",                       // incomplete
+		strings.Replace(log, `"json 3"`, `"json 4"`, 1),      // altered JSON
+		strings.Replace(log, ":warn:", ":info:", 1) + "junk", // nothing altered but junk
+	}
+	for i, s := range tampered {
+		var ae *AuditError
+		if _, _, err := VerifyAudit(strings.NewReader(s), nil); !errors.As(err, &ae) {
+			t.Fatal("must fail to verify:", i, err)
+		}
+	}
+
+	if _, err := aw.Write([]byte("no newline")); err == nil {
+		t.Fatal("must reject partial record")
+	}
+}
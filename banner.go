@@ -0,0 +1,83 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// processStart is the default start time for Shutdown uptimes
+var processStart = time.Now()
+
+// Startup logs a standard startup record with info severity like:
+//
+//	startup pid=4242 service.version=v1.2.0 vcs.revision=4dd6ab2
+//	config_digest=sha256:9f86d081884c7d65
+//
+// (on a single line) with build metadata (see BuildFields) and a digest of config (if
+// not nil), so deployed configurations can be compared without logging secrets. config
+// is hashed as is if it is a string or []byte, otherwise as its JSON encoding. It also
+// starts the uptime of Shutdown. Returns JSON errors of config without logging.
+func (lg *Logger) Startup(config interface{}) error {
+	msg := []interface{}{"startup", Any("pid", os.Getpid())}
+	for _, f := range BuildFields() {
+		msg = append(msg, f)
+	}
+	if config != nil {
+		digest, err := configDigest(config)
+		if err != nil {
+			return err
+		}
+		msg = append(msg, Any("config_digest", digest))
+	}
+
+	atomic.StoreInt64(&lg.stats.started, time.Now().UnixNano())
+	return lg.log(0, 0, nil, Sinfo, msg, nil)
+}
+
+// configDigest returns sha256:hex digest (16 digits) of config
+func configDigest(config interface{}) (string, error) {
+	var b []byte
+	switch c := config.(type) {
+	case []byte:
+		b = c
+	case string:
+		b = []byte(c)
+	default:
+		var err error
+		if b, err = json.Marshal(config); err != nil {
+			return "", err
+		}
+	}
+	sum := sha256.Sum256(b)
+	return "sha256:" + hex.EncodeToString(sum[:8]), nil
+}
+
+// Shutdown logs a standard shutdown record with info severity like:
+//
+//	shutdown uptime=26h3m0.5s debug=0 info=1520 warn=12 error=3 fatal=0 failed=0
+//
+// with uptime since Startup (or process start) and record counts of Logger by severity
+// (see Count & Failed) until Shutdown, typically before closing Logger.
+func (lg *Logger) Shutdown() error {
+	start := processStart
+	if ns := atomic.LoadInt64(&lg.stats.started); ns != 0 {
+		start = time.Unix(0, ns)
+	}
+	msg := []interface{}{"shutdown", Any("uptime", time.Since(start))}
+	for l := Sdebug; l < Snolog; l++ {
+		msg = append(msg, Any(levelName(l), lg.Count(l)))
+	}
+	msg = append(msg, Any("failed", lg.Failed()))
+
+	return lg.log(0, 0, nil, Sinfo, msg, nil)
+}
@@ -0,0 +1,50 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestStartupShutdown(t *testing.T) {
+	var recs []Record
+	lg := New(": ban:", ioutil.Discard, Sinfo)
+	lg.AddObserver(func(r Record) { recs = append(recs, r) })
+
+	if lg.Startup(make(chan int)) == nil || len(recs) != 0 {
+		t.Fatal("bad config accepted")
+	}
+	if err := lg.Startup(map[string]int{"b": 2, "a": 1}); err != nil {
+		t.Fatal(err)
+	}
+	lg.Log(Swarn, "w")
+	if err := lg.Shutdown(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(recs) != 3 {
+		t.Fatal("expected three records:", recs)
+	}
+	st, sh := recs[0], recs[2]
+	last := st.Fields[len(st.Fields)-1]
+	if st.Msg != "startup" || st.File != "banner_test.go" || st.Fields[0] != Any("pid", os.Getpid()) ||
+		last.Key != "config_digest" {
+		t.Fatal("bad startup record:", st)
+	}
+	if d, _ := configDigest(`{"a":1,"b":2}`); last.Value != d {
+		t.Fatal("config digest differs from its JSON digest", last, d)
+	}
+
+	if sh.Msg != "shutdown" || sh.File != "banner_test.go" || len(sh.Fields) != 7 ||
+		sh.Fields[0].Value.(time.Duration) <= 0 || sh.Fields[2] != Any("info", uint64(1)) ||
+		sh.Fields[3] != Any("warn", uint64(1)) || sh.Fields[6] != Any("failed", uint64(0)) {
+		t.Fatal("bad shutdown record:", sh)
+	}
+}
@@ -0,0 +1,122 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned by BreakerWriter for dropped records
+var ErrCircuitOpen = errors.New("yell: circuit open")
+
+// BreakerState is the circuit state of a BreakerWriter
+type BreakerState uint32
+
+// circuit states
+const (
+	BreakerClosed   BreakerState = iota // writes go to writer
+	BreakerOpen                         // writes are dropped or diverted
+	BreakerHalfOpen                     // a trial write is in progress
+)
+
+var breakerNames = [...]string{"closed", "open", "half-open"}
+
+func (s BreakerState) String() string {
+	if s > BreakerHalfOpen {
+		return "invalid"
+	}
+	return breakerNames[s]
+}
+
+// BreakerWriter is a circuit breaker around a failing writer (sink). After threshold
+// consecutive failures, the circuit opens for a cooldown period during which records are
+// diverted to an optional writer or dropped, instead of hammering the sink. After the
+// cooldown, a trial write decides whether the circuit closes or stays open for another
+// cooldown, records are diverted or dropped during the trial. It is safe for concurrent
+// use.
+type BreakerWriter struct {
+	mu        sync.Mutex // guards state, not held while writing to writer
+	writeMu   sync.Mutex // serializes writes to writer
+	writer    io.Writer
+	divert    io.Writer
+	threshold int
+	cooldown  time.Duration
+
+	state    BreakerState
+	fails    int       // consecutive failures
+	openedAt time.Time // start of cooldown
+	dropped  uint64    // number of dropped records
+}
+
+// NewBreakerWriter creates a BreakerWriter for writer with failure threshold, cooldown
+// and optional divert writer. Panics if writer is nil, threshold or cooldown is not
+// positive.
+func NewBreakerWriter(writer io.Writer, threshold int, cooldown time.Duration,
+	divert io.Writer) *BreakerWriter {
+
+	if writer == nil || threshold <= 0 || cooldown <= 0 {
+		panic("yell: invalid arguments to NewBreakerWriter")
+	}
+	return &BreakerWriter{writer: writer, divert: divert, threshold: threshold,
+		cooldown: cooldown}
+}
+
+// Write writes p to underlying writer if circuit is closed or cooldown is over.
+// Otherwise p is written to divert writer, or dropped with ErrCircuitOpen.
+func (bw *BreakerWriter) Write(p []byte) (int, error) {
+	bw.mu.Lock()
+	switch bw.state {
+	case BreakerOpen:
+		if time.Since(bw.openedAt) >= bw.cooldown {
+			bw.state = BreakerHalfOpen // this is the trial write
+			break
+		}
+		fallthrough
+	case BreakerHalfOpen:
+		defer bw.mu.Unlock()
+		if bw.divert != nil {
+			return bw.divert.Write(p)
+		}
+		bw.dropped++
+		return 0, ErrCircuitOpen
+	}
+	bw.mu.Unlock()
+
+	// State is not blocked by a slow sink
+	bw.writeMu.Lock()
+	n, err := bw.writer.Write(p)
+	bw.writeMu.Unlock()
+
+	bw.mu.Lock()
+	defer bw.mu.Unlock()
+	if err == nil {
+		bw.state, bw.fails = BreakerClosed, 0
+		return n, nil
+	}
+
+	if bw.fails++; bw.state == BreakerHalfOpen || bw.fails >= bw.threshold {
+		bw.state, bw.openedAt = BreakerOpen, time.Now()
+	}
+	return n, err
+}
+
+// State returns current circuit state
+func (bw *BreakerWriter) State() BreakerState {
+	bw.mu.Lock()
+	defer bw.mu.Unlock()
+	return bw.state
+}
+
+// Dropped returns number of dropped records
+func (bw *BreakerWriter) Dropped() uint64 {
+	bw.mu.Lock()
+	defer bw.mu.Unlock()
+	return bw.dropped
+}
@@ -0,0 +1,87 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBreakerWriter(t *testing.T) {
+	f := &flakyWriter{fails: 100}
+	bw := NewBreakerWriter(f, 2, 20*time.Millisecond, nil)
+	lg := New(": brk:", bw, Sinfo)
+
+	for i := 0; i < 2; i++ {
+		if err := lg.Log(Sinfo, "fail"); !errors.Is(err, errWrite) {
+			t.Fatal("must fail", err)
+		}
+	}
+	if bw.State() != BreakerOpen || bw.State().String() != "open" {
+		t.Fatal("must be open")
+	}
+	if err := lg.Log(Sinfo, "drop"); !errors.Is(err, ErrCircuitOpen) || bw.Dropped() != 1 {
+		t.Fatal("must drop", err)
+	}
+
+	// failed trial keeps it open
+	time.Sleep(25 * time.Millisecond)
+	if err := lg.Log(Sinfo, "trial"); !errors.Is(err, errWrite) || bw.State() != BreakerOpen {
+		t.Fatal("must stay open", err)
+	}
+
+	var div strings.Builder
+	bw.divert = &div
+	if err := lg.Log(Sinfo, "divert"); err != nil || !strings.HasSuffix(div.String(), " divert\n") {
+		t.Fatal("must divert", err)
+	}
+
+	// successful trial closes it
+	f.fails = 0
+	time.Sleep(25 * time.Millisecond)
+	if err := lg.Log(Sinfo, "ok"); err != nil || bw.State() != BreakerClosed {
+		t.Fatal("must close", err)
+	}
+	if BreakerState(9).String() != "invalid" {
+		t.Fatal("must be invalid")
+	}
+}
+
+// gateWriter blocks writes until gate is closed
+type gateWriter struct{ entered, gate chan struct{} }
+
+func (w gateWriter) Write(p []byte) (int, error) {
+	w.entered <- struct{}{}
+	<-w.gate
+	return len(p), nil
+}
+
+func TestBreakerHalfOpen(t *testing.T) {
+	w := gateWriter{make(chan struct{}), make(chan struct{})}
+	bw := NewBreakerWriter(w, 1, time.Millisecond, nil)
+	bw.state, bw.openedAt = BreakerOpen, time.Now().Add(-time.Second)
+
+	done := make(chan error)
+	go func() {
+		_, err := bw.Write([]byte("trial\n"))
+		done <- err
+	}()
+	<-w.entered // trial write is in progress
+	if bw.State() != BreakerHalfOpen {
+		t.Fatal("must be half-open during trial:", bw.State())
+	}
+	if _, err := bw.Write([]byte("x\n")); err != ErrCircuitOpen || bw.Dropped() != 1 {
+		t.Fatal("must drop during trial:", err)
+	}
+
+	close(w.gate)
+	if err := <-done; err != nil || bw.State() != BreakerClosed {
+		t.Fatal("successful trial must close:", err)
+	}
+}
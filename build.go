@@ -0,0 +1,32 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import "runtime/debug"
+
+// BuildFields returns build metadata of the running binary (see debug.ReadBuildInfo) as
+// service.version (main module version) and (with Go 1.18+) vcs.revision, vcs.time &
+// vcs.modified fields, so records can be tied to the exact build. Unknown metadata are
+// omitted, vcs.modified only appears if the working tree was modified.
+func BuildFields() []Field {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return nil
+	}
+	var fields []Field
+	if v := info.Main.Version; v != "" && v != "(devel)" {
+		fields = append(fields, Any("service.version", v))
+	}
+	return append(fields, vcsFields(info)...)
+}
+
+// Build returns an Enricher of BuildFields, which are computed once:
+//
+//	lg.AddEnricher(yell.Build())
+func Build() Enricher {
+	return StaticEnricher(BuildFields()...)
+}
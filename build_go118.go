@@ -0,0 +1,27 @@
+//go:build go1.18
+// +build go1.18
+
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import "runtime/debug"
+
+// vcsFields returns version control fields of info
+func vcsFields(info *debug.BuildInfo) (fields []Field) {
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision", "vcs.time":
+			fields = append(fields, Any(s.Key, s.Value))
+		case "vcs.modified":
+			if s.Value == "true" {
+				fields = append(fields, Any(s.Key, true))
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,29 @@
+//go:build go1.18
+// +build go1.18
+
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"runtime/debug"
+	"testing"
+)
+
+func TestVCSFields(t *testing.T) {
+	info := &debug.BuildInfo{Settings: []debug.BuildSetting{{Key: "-compiler", Value: "gc"},
+		{Key: "vcs.revision", Value: "4dd6ab2"}, {Key: "vcs.time", Value: "2021-03-28T21:48:53Z"},
+		{Key: "vcs.modified", Value: "true"}}}
+	f := vcsFields(info)
+	if len(f) != 3 || f[0] != Any("vcs.revision", "4dd6ab2") || f[2] != Any("vcs.modified", true) {
+		t.Fatal("bad fields", f)
+	}
+	info.Settings[3].Value = "false"
+	if f = vcsFields(info); len(f) != 2 {
+		t.Fatal("bad fields", f)
+	}
+}
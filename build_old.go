@@ -0,0 +1,17 @@
+//go:build !go1.18
+// +build !go1.18
+
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import "runtime/debug"
+
+// vcsFields needs Go 1.18, it returns nil
+func vcsFields(*debug.BuildInfo) []Field {
+	return nil
+}
@@ -0,0 +1,27 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import "testing"
+
+func TestBuild(t *testing.T) {
+	fields := Build()()
+	for _, f := range fields {
+		switch f.Key {
+		case "service.version", "vcs.revision", "vcs.time":
+			if s, _ := f.Value.(string); s == "" {
+				t.Fatal("bad field", f)
+			}
+		case "vcs.modified":
+			if f.Value != true {
+				t.Fatal("bad field", f)
+			}
+		default:
+			t.Fatal("unexpected field", f)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// WithCallerSkip returns a copy of Logger that skips n more stack frames to discover
+// request locations, for wrapping layers deeper than the designed
+// use case in Logger doc:
+//
+//	var logger = yell.New(": mypkg:", os.Stdout, yell.Swarn).WithCallerSkip(1)
+//
+//	// logf is called by Info, Warn etc.
+//	func logf(level yell.Severity, format string, args ...interface{}) error {
+//		return logger.Log(level, fmt.Sprintf(format, args...))
+//	}
+//
+// Negative n reduces skips of a previous WithCallerSkip. Per-call Caller values are added
+// to n. The copy has its own writer, level & settings,
+// and shares statistics & sequence counter with Logger.
+func (lg *Logger) WithCallerSkip(n int) Logger {
+	c := lg.derive()
+	if c.skip += n; c.skip < 0 {
+		c.skip = 0
+	}
+	return c
+}
+
+// SetAutoCaller enables or disables automatic wrapper-frame detection. When enabled,
+// request location is the first stack frame that belongs neither to yell nor to wrappers
+// (package import paths like "example.com/mylog"), so wrapping layers of any depth need
+// no caller skips. Per-call Caller values skip that many more frames after it, skips of
+// WithCallerSkip are ignored. Automatic detection is slower than fixed caller depths. It
+// should be called before Logger is used.
+func (lg *Logger) SetAutoCaller(on bool, wrappers ...string) {
+	if on {
+		lg.wrappers = append([]string{yellPkg}, wrappers...)
+	} else {
+		lg.wrappers = nil
+	}
+}
+
+// LogFrom is like Log for adapters of other logging libraries (like yellzap): request
+// location is the first stack frame that belongs neither to yell nor to pkgs (package
+// import paths of the library & adapter), whatever the depth. Caller values, skips of
+// WithCallerSkip & SetAutoCaller do not apply, other settings of Logger do.
+func (lg *Logger) LogFrom(pkgs []string, level Severity, msg ...interface{}) error {
+	return lg.log(outsideFrames(pkgs), mExact, nil, level, msg, nil)
+}
+
+// outsideFrames returns the number of frames above the caller of LogFrom that belong to
+// yell or pkgs, noCaller if all do
+func outsideFrames(pkgs []string) int {
+	var pcs [32]uintptr
+	n := runtime.Callers(3, pcs[:]) // skip Callers, outsideFrames & LogFrom
+
+	for i, pc := range pcs[:n] {
+		if p := callSite(pc).pkg; p != yellPkg && !contains(pkgs, p) {
+			return i
+		}
+	}
+	return noCaller
+}
+
+// contains checks if pkgs has pkg
+func contains(pkgs []string, pkg string) bool {
+	for _, p := range pkgs {
+		if p == pkg {
+			return true
+		}
+	}
+	return false
+}
+
+// yellPkg is the import path of this package
+var yellPkg = funcPackage(runtime.FuncForPC(reflect.ValueOf(New).Pointer()).Name())
+
+// funcPackage returns import path of the package of function fn like
+// example.com/pkg.(*T).Method
+func funcPackage(fn string) string {
+	i := strings.LastIndexByte(fn, '/') + 1
+	if k := strings.IndexByte(fn[i:], '.'); k >= 0 {
+		return fn[:i+k]
+	}
+	return fn
+}
+
+// site is a resolved program counter
+type site struct {
+	file string // base name
+	line int
+	pkg  string // import path, see funcPackage
+}
+
+// maxSites bounds the number of cached sites
+const maxSites = 4096
+
+var (
+	sites     sync.Map // uintptr to site
+	siteCount int32    // approximate size of sites, accessed atomically
+)
+
+// callSite resolves pc from runtime.Callers, sites are cached up to maxSites
+func callSite(pc uintptr) site {
+	if s, ok := sites.Load(pc); ok {
+		return s.(site)
+	}
+	f, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	s := site{line: f.Line, pkg: funcPackage(f.Function)}
+	if f.File != "" {
+		s.file = filepath.Base(f.File) // full path to file name
+	}
+	if atomic.LoadInt32(&siteCount) < maxSites {
+		if _, loaded := sites.LoadOrStore(pc, s); !loaded {
+			atomic.AddInt32(&siteCount, 1)
+		}
+	}
+	return s
+}
+
+// caller returns location of the frame skip levels above log, like runtime.Caller in log
+func caller(skip int) (file string, line int, ok bool) {
+	var pc [1]uintptr
+	if runtime.Callers(skip+2, pc[:]) == 0 { // skip Callers & caller
+		return
+	}
+	s := callSite(pc[0])
+	return s.file, s.line, s.file != ""
+}
+
+// autoCaller returns location of the first frame outside wrappers (and log package if
+// stdlib), skipping skip more
+func (lg *Logger) autoCaller(skip int, stdlib bool) (file string, line int, ok bool) {
+	var pcs [32]uintptr
+	n := runtime.Callers(3, pcs[:]) // skip Callers, autoCaller & log
+
+	wrapped := true
+	for _, pc := range pcs[:n] {
+		s := callSite(pc)
+		if wrapped && (lg.isWrapper(s.pkg) || stdlib && s.pkg == "log") {
+			continue
+		}
+		wrapped = false
+		if skip--; skip < 0 {
+			return s.file, s.line, true
+		}
+	}
+	return
+}
+
+// isWrapper checks if pkg is yell or a wrapper package
+func (lg *Logger) isWrapper(pkg string) bool {
+	return contains(lg.wrappers, pkg)
+}
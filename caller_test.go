@@ -0,0 +1,123 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
+	"testing"
+)
+
+// wrapLog is like Info, Warn etc. of the designed use case
+func wrapLog(lg *Logger, msg string) {
+	lg.Log(Swarn, msg)
+}
+
+// deepLog is a deeper wrapping layer
+func deepLog(lg *Logger, msg string) {
+	wrapLog(lg, msg)
+}
+
+// line returns line number of its caller
+func line() int {
+	_, _, l, _ := runtime.Caller(1)
+	return l
+}
+
+func TestWithCallerSkip(t *testing.T) {
+	var lines []int
+	lg := New(": cs:", ioutil.Discard, Sinfo)
+	lg.AddObserver(func(r Record) { lines = append(lines, r.Line) })
+	ws := lg.WithCallerSkip(1)
+
+	wrapLog(&lg, "designed use")
+	l1 := line() - 1
+	deepLog(&ws, "deeper wrapper")
+	l2 := line() - 1
+	back := ws.WithCallerSkip(-5)
+	wrapLog(&back, "designed use again")
+	l3 := line() - 1
+
+	if len(lines) != 3 || lines[0] != l1 || lines[1] != l2 || lines[2] != l3 {
+		t.Fatal("unexpected locations:", lines, l1, l2, l3)
+	}
+}
+
+func TestAutoCaller(t *testing.T) {
+	var files []string
+	lg := New(": ac:", ioutil.Discard, Sinfo)
+	lg.AddObserver(func(r Record) { files = append(files, r.File) })
+
+	// test functions belong to yell, so first outside frame is in testing
+	lg.SetAutoCaller(true)
+	wrapLog(&lg, "designed use")
+	deepLog(&lg, "deeper wrapper")
+	lg.Log(Swarn, Caller(1), "one more")
+	lg.SetAutoCaller(true, "testing")
+	lg.Log(Swarn, "skip testing")
+	lg.SetAutoCaller(false)
+	wrapLog(&lg, "fixed depth")
+	lg.LogFrom(nil, Swarn, "adapter")
+
+	want := [...]string{"testing.go", "testing.go", "asm_amd64.s", "asm_amd64.s",
+		"caller_test.go", "testing.go"}
+	if len(files) != len(want) {
+		t.Fatal("missing records:", files)
+	}
+	for i, f := range files {
+		if f != want[i] && !(runtime.GOARCH != "amd64" && i > 1 && i < 4) {
+			t.Fatal("unexpected location:", i, files)
+		}
+	}
+
+	pkgs := [...]struct{ fn, pkg string }{
+		{"github.com/jfcg/yell/v2.(*Logger).Log", "github.com/jfcg/yell/v2"},
+		{"github.com/jfcg/yell/v2.New", yellPkg},
+		{"main.main.func1", "main"},
+		{"example.com/a.b/c.F", "example.com/a.b/c"},
+		{"noDot", "noDot"},
+	}
+	for _, p := range pkgs {
+		if funcPackage(p.fn) != p.pkg {
+			t.Fatal("wrong package:", p.fn, funcPackage(p.fn))
+		}
+	}
+}
+
+func TestCallSite(t *testing.T) {
+	var pc [1]uintptr
+	runtime.Callers(1, pc[:])
+	_, file, line, _ := runtime.Caller(0)
+	line-- // of Callers
+
+	for i := 0; i < 2; i++ { // resolved, then cached
+		s := callSite(pc[0])
+		if s.file != filepath.Base(file) || s.line != line || s.pkg != yellPkg {
+			t.Fatal("wrong site:", s)
+		}
+	}
+	if _, ok := sites.Load(pc[0]); !ok {
+		t.Fatal("site must be cached")
+	}
+
+	// bounded cache
+	n := atomic.LoadInt32(&siteCount)
+	atomic.StoreInt32(&siteCount, maxSites)
+	sites.Delete(pc[0])
+	callSite(pc[0])
+	if _, ok := sites.Load(pc[0]); ok || atomic.LoadInt32(&siteCount) != maxSites {
+		t.Fatal("full cache must not grow")
+	}
+	atomic.StoreInt32(&siteCount, n-1)
+	callSite(pc[0])
+
+	if _, _, ok := caller(1 << 20); ok {
+		t.Fatal("caller beyond stack must fail")
+	}
+}
@@ -0,0 +1,101 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"bytes"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// maximum length of buffered subprocess output lines, longer ones are split
+const maxCaptureLine = 64 << 10
+
+// Capture sets Stdout & Stderr of cmd (before it is started) so that its output lines are
+// logged at info and error severity respectively, with a "cmd" field of the command name.
+// Empty lines are ignored. The returned flush must be called after cmd.Wait (or Run) to
+// log last lines without newline:
+//
+//	flush := lg.Capture(cmd)
+//	err := cmd.Run()
+//	flush()
+//
+// Request location of the records is not meaningful.
+func (lg *Logger) Capture(cmd *exec.Cmd) (flush func()) {
+	name := []interface{}{Any("cmd", filepath.Base(cmd.Path))}
+	out := &captureWriter{lg: lg, level: Sinfo, extra: name}
+	errs := &captureWriter{lg: lg, level: Serror, extra: name}
+	cmd.Stdout, cmd.Stderr = out, errs
+
+	return func() {
+		out.Flush()
+		errs.Flush()
+	}
+}
+
+// RunCmd runs cmd with its output logged, see Capture
+func (lg *Logger) RunCmd(cmd *exec.Cmd) error {
+	flush := lg.Capture(cmd)
+	err := cmd.Run()
+	flush()
+	return err
+}
+
+// captureWriter logs complete lines of a byte stream, it must not be used concurrently
+type captureWriter struct {
+	lg    *Logger
+	level Severity      // default level of lines
+	sniff bool          // detect levels of lines, see SniffSeverity
+	extra []interface{} // appended to each line, like fields
+	buf   []byte        // incomplete last line
+}
+
+// Write logs complete lines of buffered output & p
+func (w *captureWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			if len(w.buf) < maxCaptureLine {
+				break
+			}
+			i = maxCaptureLine
+		}
+		w.line(w.buf[:i])
+		if i < len(w.buf) && w.buf[i] == '\n' {
+			i++
+		}
+		w.buf = w.buf[i:]
+	}
+	if len(w.buf) == 0 {
+		w.buf = nil // release long lines
+	}
+	return len(p), nil
+}
+
+// line logs non-empty line
+func (w *captureWriter) line(b []byte) {
+	s := strings.TrimSuffix(string(b), "\r")
+	if s == "" {
+		return
+	}
+	level := w.level
+	if w.sniff {
+		if l, ok := SniffSeverity(s); ok {
+			level = l
+		}
+	}
+	w.lg.Log(level, append([]interface{}{s}, w.extra...)...)
+}
+
+// Flush logs incomplete last line
+func (w *captureWriter) Flush() error {
+	w.line(w.buf)
+	w.buf = nil
+	return nil
+}
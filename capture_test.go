@@ -0,0 +1,63 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"io/ioutil"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestCapture(t *testing.T) {
+	var recs []Record
+	lg := New(": cap:", ioutil.Discard, Sinfo)
+	lg.AddObserver(func(r Record) { recs = append(recs, r) })
+
+	cmd := &exec.Cmd{Path: "/bin/sh"}
+	flush := lg.Capture(cmd)
+	w := cmd.Stdout
+	w.Write([]byte("one\r\n\ntw"))
+	w.Write([]byte("o\nthree"))
+	cmd.Stderr.Write([]byte(strings.Repeat("x", maxCaptureLine+1)))
+	flush()
+
+	if len(recs) != 5 || recs[0].Msg != "one" || recs[1].Msg != "two" ||
+		len(recs[2].Msg) != maxCaptureLine || recs[2].Level != Serror ||
+		recs[3].Msg != "three" || recs[3].Level != Sinfo || recs[4].Msg != "x" ||
+		len(recs[0].Fields) != 1 || recs[0].Fields[0].Value != "sh" {
+		t.Fatal("unexpected records:", len(recs))
+	}
+}
+
+func TestRunCmd(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("no shell")
+	}
+	var (
+		mu   sync.Mutex // stdout & stderr are copied concurrently
+		recs []Record
+	)
+	lg := New(": cap:", ioutil.Discard, Sinfo)
+	lg.AddObserver(func(r Record) {
+		mu.Lock()
+		recs = append(recs, r)
+		mu.Unlock()
+	})
+
+	err = lg.RunCmd(exec.Command(sh, "-c", "echo out; echo err >&2; printf last; exit 3"))
+	if err == nil || len(recs) != 3 {
+		t.Fatal("unexpected result:", err, recs)
+	}
+	for _, r := range recs {
+		if r.Msg == "err" && r.Level != Serror || r.Msg != "err" && r.Level != Sinfo {
+			t.Fatal("unexpected record:", r)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+// Command yell filters & pretty-prints yell logs (text or JSON records) read from files
+// or standard input:
+//
+//	yell [flags] [file ...]
+//
+// Flags:
+//
+//	-level   minimum severity to show (debug, info, warn, error, fatal), default debug
+//	-name    logger name glob pattern (see path.Match) to show, default all
+//	-since   show records at or after time (RFC 3339, yell.TimeFormat or Unix seconds)
+//	-until   show records before time
+//	-color   colorize output: auto (terminals), always or never, default auto
+//	-json    print matching records as they are instead of text
+//
+// Records are printed as yell text records, with their error details (tab-indented
+// continuation lines). Records without a parsable time are skipped if a time range is
+// given. Lines that are not yell records are skipped.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/jfcg/yell/v2"
+	"github.com/jfcg/yell/v2/yellparse"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "yell:", err)
+		os.Exit(2)
+	}
+}
+
+// filter of records
+type filter struct {
+	level        yell.Severity
+	name         string
+	since, until time.Time
+	color, json  bool
+}
+
+// run processes files in args (or stdin) according to flags in args
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	f := filter{level: yell.Sdebug}
+	var since, until, color string
+
+	fs := flag.NewFlagSet("yell", flag.ContinueOnError)
+	fs.Var(&f.level, "level", "minimum severity to show")
+	fs.StringVar(&f.name, "name", "*", "logger name glob pattern to show")
+	fs.StringVar(&since, "since", "", "show records at or after time")
+	fs.StringVar(&until, "until", "", "show records before time")
+	fs.StringVar(&color, "color", "auto", "colorize output: auto, always or never")
+	fs.BoolVar(&f.json, "json", false, "print matching records as they are")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if _, err := path.Match(f.name, ""); err != nil {
+		return fmt.Errorf("invalid -name: %v", err)
+	}
+	var ok bool
+	if since != "" {
+		if f.since, ok = yellparse.ParseTime(since); !ok {
+			return fmt.Errorf("invalid -since: %q", since)
+		}
+	}
+	if until != "" {
+		if f.until, ok = yellparse.ParseTime(until); !ok {
+			return fmt.Errorf("invalid -until: %q", until)
+		}
+	}
+	switch color {
+	case "auto":
+		f.color = isTerminal(stdout)
+	case "always":
+		f.color = true
+	case "never":
+	default:
+		return fmt.Errorf("invalid -color: %q", color)
+	}
+
+	out := bufio.NewWriter(stdout)
+	if fs.NArg() == 0 {
+		if err := f.process(stdin, out); err != nil {
+			return err
+		}
+	}
+	for _, name := range fs.Args() {
+		file, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		err = f.process(file, out)
+		file.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return out.Flush()
+}
+
+// process writes matching records of r to w
+func (f *filter) process(r io.Reader, w *bufio.Writer) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64<<10), 16<<20)
+	show := false // show continuation lines of last record
+
+	for sc.Scan() {
+		line := sc.Text()
+		if strings.HasPrefix(line, "\t") {
+			if show {
+				w.WriteString(line)
+				w.WriteByte('\n')
+			}
+			continue
+		}
+
+		rec, ok := yellparse.Parse(line)
+		if show = ok && f.match(&rec); !show {
+			continue
+		}
+		if f.json {
+			w.WriteString(line)
+			w.WriteByte('\n')
+			continue
+		}
+		f.print(w, &rec)
+	}
+	return sc.Err()
+}
+
+// match checks if rec passes the filter
+func (f *filter) match(rec *yellparse.Record) bool {
+	if rec.Level < f.level {
+		return false
+	}
+	if ok, _ := path.Match(f.name, rec.Name); !ok {
+		return false
+	}
+	if f.since.IsZero() && f.until.IsZero() {
+		return true
+	}
+	t, ok := rec.Timestamp()
+	return ok && (f.since.IsZero() || !t.Before(f.since)) &&
+		(f.until.IsZero() || t.Before(f.until))
+}
+
+// print writes rec as a text record
+func (f *filter) print(w *bufio.Writer, rec *yellparse.Record) {
+	if rec.Time != "" {
+		w.WriteString(rec.Time)
+		w.WriteString(": ")
+	}
+	if f.color {
+		w.WriteString(yell.NameColor + rec.Name + colorReset + ":")
+		w.WriteString(yell.Scolor[rec.Level] + yell.Sname[rec.Level] + colorReset)
+	} else {
+		w.WriteString(rec.Name + ":" + yell.Sname[rec.Level])
+	}
+	if rec.Caller != "" {
+		w.WriteString(" " + rec.Caller + ":")
+	}
+	if rec.Message != "" {
+		w.WriteString(" " + rec.Message)
+	}
+	w.WriteByte('\n')
+
+	// details of JSON records, text records have continuation lines
+	if rec.Detail != "" {
+		for _, d := range strings.Split(rec.Detail, "\n") {
+			w.WriteString("\t" + d + "\n")
+		}
+	}
+}
+
+// resets ANSI colors
+const colorReset = "\x1b[0m"
+
+// isTerminal reports whether w is a character device
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,61 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const input = `2021-03-28 21:48:53.591948: net.http:info: a.go:1: request
+2021-03-28 21:48:54.000000: net.http:error: a.go:2: failed
+	caused by: timeout
+not a record
+{"time":"2021-03-28T21:49:00Z","name":"db","level":"warn","caller":"b.go:3","msg":"slow","detail":"d1\nd2"}
+2021-03-28 21:50:00.000000: db:debug: c.go:4: query
+`
+
+func TestRun(t *testing.T) {
+	local := time.Local
+	time.Local = time.UTC // for text record times
+	t.Cleanup(func() { time.Local = local })
+	cases := [...]struct {
+		args []string
+		want string
+	}{
+		{[]string{"-level=warn"}, "2021-03-28 21:48:54.000000: net.http:error: a.go:2: failed\n" +
+			"\tcaused by: timeout\n" +
+			"2021-03-28T21:49:00Z: db:warn: b.go:3: slow\n\td1\n\td2\n"},
+		{[]string{"-name", "net.*", "-json"},
+			"2021-03-28 21:48:53.591948: net.http:info: a.go:1: request\n" +
+				"2021-03-28 21:48:54.000000: net.http:error: a.go:2: failed\n" +
+				"\tcaused by: timeout\n"},
+		{[]string{"-since", "2021-03-28 21:48:54.000000", "-until", "2021-03-28T21:50:00Z",
+			"-level", "warn", "-json"}, "2021-03-28 21:48:54.000000: net.http:error: a.go:2: failed\n" +
+			"\tcaused by: timeout\n" + strings.Split(input, "\n")[4] + "\n"},
+		{[]string{"-color=always", "-level=fatal"}, ""},
+		{[]string{"-color=always", "-name=db", "-level=debug", "-since=1616968200"},
+			"2021-03-28 21:50:00.000000: \x1b[1mdb\x1b[0m:\x1b[36mdebug:\x1b[0m c.go:4: query\n"},
+	}
+	for _, c := range cases {
+		var sb strings.Builder
+		if err := run(c.args, strings.NewReader(input), &sb); err != nil {
+			t.Fatal(c.args, err)
+		}
+		if sb.String() != c.want {
+			t.Fatalf("%v: unexpected output:\n%q\n%q", c.args, sb.String(), c.want)
+		}
+	}
+
+	for _, args := range [...][]string{{"-level=loud"}, {"-name=["}, {"-since=yesterday"},
+		{"-color=sometimes"}, {"no/such/file"}} {
+		if run(args, strings.NewReader(input), &strings.Builder{}) == nil {
+			t.Fatal("must fail:", args)
+		}
+	}
+}
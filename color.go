@@ -0,0 +1,74 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"io"
+	"os"
+)
+
+// ColorMode decides when severity names (and optionally logger names) are tinted
+type ColorMode uint32
+
+// color modes
+const (
+	Cnever  ColorMode = iota // plain text, default
+	Cauto                    // colorize if writer is a terminal
+	Calways                  // always colorize
+)
+
+// Scolor is the list of ANSI color sequences (in increasing severity) for severity names
+var Scolor = [...]string{"\x1b[36m", "\x1b[32m", "\x1b[33m", "\x1b[31m", "\x1b[1;35m"}
+
+// NameColor is the ANSI color sequence for logger names
+var NameColor = "\x1b[1m"
+
+// resets ANSI colors
+const colorReset = "\x1b[0m"
+
+// SetColor sets colorizing mode for Logger's TextEncoder. Severity names are tinted with
+// Scolor. If name is true, logger name is also tinted with NameColor. With Cauto mode,
+// colors are used only if Logger's writer is a terminal, which is checked again by
+// UpdateWriter. On Windows, virtual terminal processing of console writers is enabled for
+// colors, which falls back to plain text if that fails (before Windows 10). SetColor has
+// no effect on other encoders.
+func (lg *Logger) SetColor(mode ColorMode, name bool) {
+	if mode > Calways {
+		mode = Cnever
+	}
+	lg.colorMode = mode
+	lg.colorName = name
+
+	out := lg.output()
+	lg.setOutput(out.writer, lg.colorize(out.writer, out.enc))
+}
+
+// colorize returns enc adjusted to color, label & indent settings for writer, if enc is a
+// TextEncoder
+func (lg *Logger) colorize(writer io.Writer, enc Encoder) Encoder {
+	if _, ok := enc.(TextEncoder); !ok {
+		return enc
+	}
+	color := lg.colorMode == Calways || lg.colorMode == Cauto && isTerminal(writer)
+	if color && !enableColor(writer) {
+		color = false // old Windows console
+	}
+	return TextEncoder{color, color && lg.colorName, lg.labels, lg.indent}
+}
+
+// isTerminal reports whether writer is a terminal, that is a character device like
+// os.Stdout of an interactive session
+func isTerminal(writer io.Writer) bool {
+	f, ok := writer.(interface {
+		Stat() (os.FileInfo, error)
+	})
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,18 @@
+//go:build !windows
+// +build !windows
+
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import "io"
+
+// enableColor reports whether writer can show ANSI colors, terminals other than Windows
+// consoles are assumed to
+func enableColor(io.Writer) bool {
+	return true
+}
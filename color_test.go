@@ -0,0 +1,54 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestColor(t *testing.T) {
+	var sb strings.Builder
+	lg := New(": col:", &sb, Sinfo)
+
+	lg.SetColor(Cauto, true) // not a terminal
+	if lg.output().enc != (TextEncoder{}) {
+		t.Fatal("must not colorize a non-terminal")
+	}
+	if err := lg.Log(Swarn, "plain"); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(sb.String(), "\x1b[") || !strings.Contains(sb.String(), ": col:warn: ") {
+		t.Fatal("unexpected plain record:", sb.String())
+	}
+	sb.Reset()
+
+	lg.SetColor(Calways, true)
+	if err := lg.Log(Serror, "tinted"); err != nil {
+		t.Fatal(err)
+	}
+	want := ": " + NameColor + "col" + colorReset + ":" + Scolor[Serror] + Sname[Serror] + colorReset
+	if !strings.Contains(sb.String(), want) {
+		t.Fatal("unexpected colored record:", sb.String())
+	}
+
+	f, err := os.Create(filepath.Join(t.TempDir(), "log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	lg.SetColor(Cauto, false)
+	if !lg.UpdateWriter(f) || lg.output().enc != (TextEncoder{}) {
+		t.Fatal("must not colorize a regular file")
+	}
+	if !enableColor(f) || !enableColor(&sb) {
+		t.Fatal("only consoles can lack colors")
+	}
+}
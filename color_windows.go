@@ -0,0 +1,39 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"io"
+	"syscall"
+)
+
+// ENABLE_VIRTUAL_TERMINAL_PROCESSING console mode, interprets ANSI sequences
+const enableVTProcessing = 0x4
+
+var setConsoleMode = syscall.NewLazyDLL("kernel32.dll").NewProc("SetConsoleMode")
+
+// enableColor enables virtual terminal processing (Windows 10+) if writer is a console,
+// and reports whether writer can show ANSI colors. Other writers are not checked.
+func enableColor(writer io.Writer) bool {
+	f, ok := writer.(interface{ Fd() uintptr })
+	if !ok {
+		return true
+	}
+	h := syscall.Handle(f.Fd())
+	var mode uint32
+	if syscall.GetConsoleMode(h, &mode) != nil {
+		return true // not a console
+	}
+	if mode&enableVTProcessing != 0 {
+		return true
+	}
+	if setConsoleMode.Find() != nil {
+		return false
+	}
+	r, _, _ := setConsoleMode.Call(uintptr(h), uintptr(mode|enableVTProcessing))
+	return r != 0
+}
@@ -0,0 +1,39 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+// LogIf is like Log but returns immediately if cond is false, to tidy up guard clauses:
+//
+//	lg.LogIf(retries > 3, yell.Swarn, "retrying", retries)
+//
+// Arguments are still evaluated by the caller, so use Lazy for expensive ones.
+func (lg *Logger) LogIf(cond bool, level Severity, msg ...interface{}) error {
+	if !cond {
+		return nil
+	}
+	return lg.log(1, 0, nil, level, msg, nil)
+}
+
+// DebugIf tries to log message list with debug severity to Default logger if cond is true
+func DebugIf(cond bool, msg ...interface{}) error {
+	return GetDefault().LogIf(cond, Sdebug, msg...)
+}
+
+// InfoIf tries to log message list with info severity to Default logger if cond is true
+func InfoIf(cond bool, msg ...interface{}) error {
+	return GetDefault().LogIf(cond, Sinfo, msg...)
+}
+
+// WarnIf tries to log message list with warn severity to Default logger if cond is true
+func WarnIf(cond bool, msg ...interface{}) error {
+	return GetDefault().LogIf(cond, Swarn, msg...)
+}
+
+// ErrorIf tries to log message list with error severity to Default logger if cond is true
+func ErrorIf(cond bool, msg ...interface{}) error {
+	return GetDefault().LogIf(cond, Serror, msg...)
+}
@@ -0,0 +1,40 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLogIf(t *testing.T) {
+	var sb strings.Builder
+	lg := New(": cond:", &sb, Sinfo)
+
+	lg.LogIf(false, Swarn, "skipped")
+	if sb.Len() != 0 || lg.Count(Swarn) != 0 {
+		t.Fatal("false condition must not log")
+	}
+	lg.LogIf(true, Swarn, "logged")
+	if !strings.Contains(sb.String(), "cond:warn: testing.go:") {
+		t.Fatal("true condition must log:", sb.String())
+	}
+
+	def := Default
+	defer func() { Default = def }()
+	sb.Reset()
+	Default = lg
+
+	DebugIf(true, "below level")
+	InfoIf(false, "no")
+	InfoIf(true, "info")
+	WarnIf(true, "warn")
+	ErrorIf(true, "error")
+	if s := sb.String(); strings.Count(s, "\n") != 3 || strings.Count(s, " cond_test.go:") != 3 {
+		t.Fatal("unexpected records:", s)
+	}
+}
@@ -0,0 +1,95 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+)
+
+// ErrCrashOutput is returned by SetCrashOutput if Logger's writer is not a file or the
+// Go version is older than 1.23
+var ErrCrashOutput = errors.New("yell: crash output needs a file writer & Go 1.23")
+
+// crashFile returns Logger's writer as a file if crash output can go to it
+func (lg *Logger) crashFile() (*os.File, error) {
+	f, ok := lg.output().writer.(*os.File)
+	if !ok {
+		return nil, ErrCrashOutput
+	}
+	return f, nil
+}
+
+// PanicError is a panic recovered by Recover, with stack trace of the panicking goroutine
+type PanicError struct {
+	Value interface{} // argument of panic
+	Stack []byte      // formatted stack trace, see runtime/debug.Stack
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprint("panic: ", e.Value)
+}
+
+// Unwrap returns Value if it is an error
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// StackTrace returns Stack, it makes error details include the stack, see SetErrorDetail
+func (e *PanicError) StackTrace() []byte {
+	return e.Stack
+}
+
+// Format writes Error() and Stack on following lines for %+v, Error() otherwise
+func (e *PanicError) Format(s fmt.State, verb rune) {
+	fmt.Fprint(s, e.Error())
+	if verb == 'v' && s.Flag('+') {
+		fmt.Fprintf(s, "\n%s", e.Stack)
+	}
+}
+
+// runtimeFrames returns number of runtime frames (like panic) calling Recover, so the
+// next frame is the panic location
+func runtimeFrames() (n int) {
+	var pcs [16]uintptr
+	// skip Callers, runtimeFrames & Recover
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs[:])])
+	for more := true; more; n++ {
+		var f runtime.Frame
+		if f, more = frames.Next(); funcPackage(f.Function) != "runtime" {
+			break
+		}
+	}
+	return
+}
+
+// Recover must be deferred directly. It recovers a panic, logs it as a fatal *PanicError
+// record with the stack trace as detail (regardless of SetErrorDetail) and location of
+// the panic, flushes Logger's writer (see Flush) and panics again with the same value,
+// so unhandled panics are captured into Logger's sink before the program crashes:
+//
+//	func main() {
+//		defer lg.Recover()
+//		...
+//	}
+//
+// It is a fallback for writers that cannot be crash outputs (see SetCrashOutput), and
+// covers only panics of the goroutine that defers it.
+func (lg *Logger) Recover() {
+	v := recover()
+	if v == nil {
+		return
+	}
+	lg.log(runtimeFrames(), mDetail|mExact, nil, Sfatal,
+		[]interface{}{&PanicError{v, debug.Stack()}}, nil)
+	lg.Flush()
+	panic(v)
+}
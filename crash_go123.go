@@ -0,0 +1,26 @@
+//go:build go1.23
+// +build go1.23
+
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import "runtime/debug"
+
+// SetCrashOutput makes the Go runtime write unhandled crash reports (fatal panics with
+// goroutine stack dumps, fatal errors) to Logger's writer in addition to standard error,
+// see runtime/debug.SetCrashOutput. It captures crashes of all goroutines, including
+// those that cannot be recovered, but writer must be a file (like os.Stdout or a log
+// file) and reports are raw text, not records. Returns ErrCrashOutput otherwise, see
+// Recover for other writers.
+func (lg *Logger) SetCrashOutput() error {
+	f, err := lg.crashFile()
+	if err != nil {
+		return err
+	}
+	return debug.SetCrashOutput(f, debug.CrashOptions{})
+}
@@ -0,0 +1,37 @@
+//go:build go1.23
+// +build go1.23
+
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"testing"
+)
+
+func TestSetCrashOutput(t *testing.T) {
+	var sb strings.Builder
+	lg := New(": crash:", &sb, Swarn)
+	if lg.SetCrashOutput() != ErrCrashOutput {
+		t.Fatal("non-file writer accepted")
+	}
+
+	f, err := os.Create(filepath.Join(t.TempDir(), "crash.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	lg.UpdateWriter(f)
+	if err = lg.SetCrashOutput(); err != nil {
+		t.Fatal(err)
+	}
+	debug.SetCrashOutput(nil, debug.CrashOptions{}) // reset
+}
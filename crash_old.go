@@ -0,0 +1,15 @@
+//go:build !go1.23
+// +build !go1.23
+
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+// SetCrashOutput needs Go 1.23, it returns ErrCrashOutput. See Recover.
+func (lg *Logger) SetCrashOutput() error {
+	return ErrCrashOutput
+}
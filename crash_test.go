@@ -0,0 +1,50 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// crash panics after deferring lg.Recover
+func crash(lg *Logger, v interface{}) {
+	defer lg.Recover()
+	panic(v) // crash line
+}
+
+func TestRecover(t *testing.T) {
+	var sb strings.Builder
+	lg := New(": crash:", &sb, Swarn)
+
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Fatal("panic not propagated", r)
+			}
+		}()
+		crash(&lg, "boom")
+	}()
+
+	out := sb.String()
+	if !strings.Contains(out, "crash:fatal: crash_test.go:19: panic: boom\n") ||
+		!strings.Contains(out, "\tgoroutine ") || !strings.Contains(out, "crash_test.go") {
+		t.Fatal("bad crash record", out)
+	}
+
+	// no panic
+	func() { defer lg.Recover() }()
+
+	cause := errors.New("cause")
+	pe := &PanicError{cause, []byte("stack")}
+	if !errors.Is(pe, cause) || fmt.Sprintf("%+v", pe) != "panic: cause\nstack" ||
+		fmt.Sprint(pe) != "panic: cause" {
+		t.Fatal("bad PanicError")
+	}
+}
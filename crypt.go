@@ -0,0 +1,235 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"sync"
+)
+
+// encrypted log layout: cryptMagic, nonce prefix, then frames of 4-byte big-endian
+// ciphertext length & ciphertext. Frame nonces are prefix + 4-byte frame counter, so
+// reordered or removed frames fail decryption. A closed log ends with an empty frame
+// sealed with endMark as additional data, so removed frames at the end are detected.
+const (
+	cryptMagic    = "yellenc1"
+	prefixLen     = 8
+	maxFrame      = 16 << 20
+	cryptHeadSize = len(cryptMagic) + prefixLen
+)
+
+// endMark is additional data of the closing frame
+var endMark = []byte("end")
+
+// errFrames is returned by EncryptWriter after 2^32 frames, whose nonces would repeat
+var errFrames = errors.New("yell: too many frames to encrypt")
+
+// ErrDecrypt is returned by DecryptReader for corrupt, altered or wrong key logs
+var ErrDecrypt = errors.New("yell: cannot decrypt log")
+
+// ErrTruncated is returned by DecryptReader at the end of a log without its closing
+// frame, which was truncated, or not closed (crashed or still being written)
+var ErrTruncated = errors.New("yell: encrypted log is truncated or not closed")
+
+// EncryptWriter encrypts logs at rest with AES-GCM. Each Write (a record, as Logger
+// does) is sealed as a separate frame, so a log remains readable up to a crash. Close
+// writes a closing frame. Use DecryptReader to read it back. A failed write leaves a
+// partial frame or a missing one, so EncryptWriter fails all later writes with the same
+// error. It is safe for concurrent use.
+type EncryptWriter struct {
+	mu     sync.Mutex
+	writer io.Writer
+	aead   cipher.AEAD
+	nonce  [12]byte // prefix + frame counter
+	count  uint64   // of sealed frames
+	err    error    // of a failed write, or ErrClosed
+	head   bool     // header is written
+}
+
+// newAEAD creates AES-GCM with 16, 24 or 32 bytes key
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// NewEncryptWriter creates an EncryptWriter for a new log in writer with AES key, which
+// must be 16, 24 or 32 bytes long.
+func NewEncryptWriter(writer io.Writer, key []byte) (*EncryptWriter, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	ew := &EncryptWriter{writer: writer, aead: aead}
+	if _, err = rand.Read(ew.nonce[:prefixLen]); err != nil {
+		return nil, err
+	}
+	return ew, nil
+}
+
+// Write encrypts p as a frame and writes it to underlying writer, header included for
+// the first frame
+func (ew *EncryptWriter) Write(p []byte) (int, error) {
+	if len(p) > maxFrame-ew.aead.Overhead() {
+		return 0, errors.New("yell: record too long to encrypt")
+	}
+
+	ew.mu.Lock()
+	defer ew.mu.Unlock()
+	if ew.err != nil {
+		return 0, ew.err
+	}
+	if err := ew.seal(p, nil); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// seal writes p as the next frame with additional data ad, ew.mu must be held
+func (ew *EncryptWriter) seal(p, ad []byte) error {
+	if ew.count > math.MaxUint32 {
+		return errFrames
+	}
+
+	buf := make([]byte, 0, cryptHeadSize+4+len(p)+ew.aead.Overhead())
+	if !ew.head {
+		buf = append(append(buf, cryptMagic...), ew.nonce[:prefixLen]...)
+	}
+	binary.BigEndian.PutUint32(ew.nonce[prefixLen:], uint32(ew.count))
+	ew.count++ // a nonce is never reused
+
+	n := len(buf)
+	buf = append(buf, 0, 0, 0, 0)
+	buf = ew.aead.Seal(buf, ew.nonce[:], p, ad)
+	binary.BigEndian.PutUint32(buf[n:], uint32(len(buf)-n-4))
+
+	if _, err := ew.writer.Write(buf); err != nil {
+		ew.err = err
+		return err
+	}
+	ew.head = true
+	return nil
+}
+
+// end writes the closing frame unless ew has failed or is closed, ew.mu must be held
+func (ew *EncryptWriter) end() error {
+	if ew.err != nil {
+		return nil // failure is already reported
+	}
+	err := ew.seal(nil, endMark)
+	if err == nil {
+		ew.err = ErrClosed
+	}
+	return err
+}
+
+// decryptReader reads plaintext from an encrypted log
+type decryptReader struct {
+	reader io.Reader
+	aead   cipher.AEAD
+	nonce  [12]byte
+	count  uint32
+	head   bool
+	end    bool   // closing frame is read
+	buf    []byte // frame buffer
+	plain  []byte // unread plaintext
+}
+
+// DecryptReader returns a reader of plaintext from an encrypted log written by
+// EncryptWriter with AES key. Read returns ErrDecrypt if the log is corrupt, altered or
+// the key is wrong, and ErrTruncated after all frames if the closing frame is missing.
+func DecryptReader(r io.Reader, key []byte) (io.Reader, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	return &decryptReader{reader: r, aead: aead}, nil
+}
+
+// Read plaintext, decrypting next frame as necessary
+func (dr *decryptReader) Read(p []byte) (int, error) {
+	for len(dr.plain) == 0 {
+		if err := dr.next(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, dr.plain)
+	dr.plain = dr.plain[n:]
+	return n, nil
+}
+
+// next decrypts next frame into dr.plain
+func (dr *decryptReader) next() (err error) {
+	if !dr.head {
+		var h [cryptHeadSize]byte
+		if _, err = io.ReadFull(dr.reader, h[:]); err != nil {
+			if err == io.EOF {
+				return // empty log
+			}
+			return corrupt(err)
+		}
+		if string(h[:len(cryptMagic)]) != cryptMagic {
+			return ErrDecrypt
+		}
+		copy(dr.nonce[:], h[len(cryptMagic):])
+		dr.head = true
+	}
+
+	var l [4]byte
+	if _, err = io.ReadFull(dr.reader, l[:]); err != nil {
+		if err != io.EOF {
+			return corrupt(err)
+		}
+		if !dr.end {
+			return ErrTruncated
+		}
+		return // end of log
+	}
+	if dr.end {
+		return ErrDecrypt // frame after closing frame
+	}
+	n := binary.BigEndian.Uint32(l[:])
+	if n < uint32(dr.aead.Overhead()) || n > maxFrame {
+		return ErrDecrypt
+	}
+
+	if cap(dr.buf) < int(n) {
+		dr.buf = make([]byte, n)
+	}
+	dr.buf = dr.buf[:n]
+	if _, err = io.ReadFull(dr.reader, dr.buf); err != nil {
+		return corrupt(err)
+	}
+
+	binary.BigEndian.PutUint32(dr.nonce[prefixLen:], dr.count)
+	dr.plain, err = dr.aead.Open(dr.buf[:0], dr.nonce[:], dr.buf, nil)
+	if err != nil && len(dr.buf) == dr.aead.Overhead() {
+		_, err = dr.aead.Open(nil, dr.nonce[:], dr.buf, endMark)
+		dr.end = err == nil
+	}
+	if err != nil {
+		return ErrDecrypt
+	}
+	dr.count++
+	return
+}
+
+// corrupt converts truncation errors to ErrDecrypt
+func corrupt(err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return ErrDecrypt
+	}
+	return err
+}
@@ -0,0 +1,98 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math"
+	"testing"
+)
+
+func decrypt(log, key []byte) ([]byte, error) {
+	dr, err := DecryptReader(bytes.NewReader(log), key)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(dr)
+}
+
+func TestEncrypt(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	if _, err := NewEncryptWriter(ioutil.Discard, key[:5]); err == nil {
+		t.Fatal("must reject invalid key")
+	}
+
+	var plain, buf bytes.Buffer
+	ew, err := NewEncryptWriter(&buf, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p, err := decrypt(nil, key); err != nil || len(p) != 0 {
+		t.Fatal("empty log must decrypt to nothing:", err)
+	}
+
+	lg := New(": enc:", ew, Sinfo)
+	lg.Log(Sinfo, "secret", 1)
+	lg.Log(Swarn, "secret", 2)
+	open := append([]byte(nil), buf.Bytes()...)
+	if err = ew.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ew.Write([]byte("x")); err != ErrClosed || ew.Close() != nil {
+		t.Fatal("closed writer must fail:", err)
+	}
+	lg.UpdateWriter(&plain)
+	lg.Log(Sinfo, "secret", 1)
+	lg.Log(Swarn, "secret", 2)
+
+	log := buf.Bytes()
+	if bytes.Contains(log, []byte("secret")) {
+		t.Fatal("log must be encrypted")
+	}
+	p, err := decrypt(log, key)
+	if err != nil || len(p) != plain.Len() ||
+		!bytes.Equal(p[len(p)-25:], plain.Bytes()[plain.Len()-25:]) {
+		t.Fatal("must decrypt:", err, string(p))
+	}
+
+	second := cryptHeadSize + 4 + int(log[cryptHeadSize+3]) // 2nd frame offset
+	altered := append([]byte(nil), log...)
+	altered[len(altered)-1] ^= 1
+
+	bad := [...][]byte{
+		altered,
+		log[:len(log)-1], // truncated
+		append(log[:cryptHeadSize:cryptHeadSize], log[second:]...), // removed frame
+		log[1:], // invalid magic
+		append(log[:len(log):len(log)], log[cryptHeadSize:second]...), // frame after end
+	}
+	for i, b := range bad {
+		if _, err := decrypt(b, key); err != ErrDecrypt {
+			t.Fatal("must fail to decrypt:", i, err)
+		}
+	}
+	if _, err := decrypt(log, []byte("fedcba9876543210")); err != ErrDecrypt {
+		t.Fatal("must fail with wrong key:", err)
+	}
+	if p, err := decrypt(open, key); err != ErrTruncated || !bytes.HasSuffix(p, []byte("secret 2\n")) {
+		t.Fatal("must detect missing closing frame:", err, string(p))
+	}
+
+	fw, _ := NewEncryptWriter(failWriter{}, key)
+	if _, err := fw.Write([]byte("x")); err != errWrite || fw.count != 1 {
+		t.Fatal("failed write must consume its nonce:", err, fw.count)
+	}
+	if _, err := fw.Write([]byte("y")); err != errWrite || fw.count != 1 {
+		t.Fatal("writer must fail after a failed write:", err, fw.count)
+	}
+	fw.err = nil
+	fw.count = math.MaxUint32 + 1
+	if _, err := fw.Write([]byte("x")); err != errFrames {
+		t.Fatal("must not reuse nonces:", err)
+	}
+}
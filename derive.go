@@ -0,0 +1,69 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"io"
+	"strings"
+)
+
+// derive returns a copy of Logger whose settings can change without affecting Logger.
+// Writer & levels are loaded atomically, so it is safe while they change. Slices are
+// clipped, so appending to them in the copy reallocates.
+func (lg *Logger) derive() (c Logger) {
+	c.settings = lg.settings
+	c.out.Store(lg.output())
+	c.minLevel, c.maxLevel = lg.GetLevel(), lg.GetMaxLevel()
+	c.observers = c.observers[:len(c.observers):len(c.observers)]
+	c.enrichers = c.enrichers[:len(c.enrichers):len(c.enrichers)]
+	c.wrappers = c.wrappers[:len(c.wrappers):len(c.wrappers)]
+	return c
+}
+
+// WithLevel returns a copy of Logger with minimum severity level, for example a verbose
+// Logger for a subsystem:
+//
+//	var dbLog = logger.WithLevel(yell.Sdebug)
+//
+// Derived copies (see also WithWriter, WithName, WithFields & WithCallerSkip) have their
+// own writer, level & settings, and share statistics & sequence counter with Logger.
+func (lg *Logger) WithLevel(level Severity) Logger {
+	c := lg.derive()
+	c.SetLevel(level)
+	return c
+}
+
+// WithWriter returns a copy of Logger with writer, which can also implement sync.Locker.
+// nil writer means Logger's writer.
+func (lg *Logger) WithWriter(writer io.Writer) Logger {
+	c := lg.derive()
+	c.UpdateWriter(writer)
+	return c
+}
+
+// WithName returns a copy of Logger named like mypkg.suffix, empty suffix keeps the name.
+// Panics if suffix has non-printable characters.
+func (lg *Logger) WithName(suffix string) Logger {
+	if strings.IndexFunc(suffix, notPrint) >= 0 {
+		panic("yell: logger name suffix must be printable")
+	}
+	c := lg.derive()
+	if suffix != "" {
+		c.name = lg.name + "." + suffix
+	}
+	return c
+}
+
+// WithFields returns a copy of Logger that attaches fields (which are copied) to its
+// records, after fields of enrichers (see AddEnricher).
+func (lg *Logger) WithFields(fields ...Field) Logger {
+	c := lg.derive()
+	if len(fields) > 0 {
+		c.AddEnricher(StaticEnricher(fields...))
+	}
+	return c
+}
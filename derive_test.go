@@ -0,0 +1,85 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestDerive(t *testing.T) {
+	var base, other strings.Builder
+	lg := New(": dv:", &base, Sinfo)
+	lg.SetTimeMode(Tnone)
+	for i := 0; i < 3; i++ { // spare capacity
+		lg.AddEnricher(StaticEnricher())
+	}
+
+	verbose := lg.WithLevel(Sdebug)
+	verbose.Log(Sdebug, "verbose")
+	lg.Log(Sdebug, "hidden")
+
+	named := lg.WithName("db")
+	db := named.WithFields(Any("a", 1))
+	db.Log(Sinfo, "query")
+	named = lg.WithName("")
+	cache := named.WithFields(Any("b", 2))
+	cache.Log(Sinfo, "hit")
+	lg.Log(Sinfo, "base")
+
+	w := lg.WithWriter(&other)
+	w.Log(Swarn, "elsewhere")
+	w = lg.WithWriter(nil)
+	w.Log(Swarn, "same")
+
+	want := []string{"dv:debug: ", " verbose", "dv.db:info: ", " query a=1",
+		"dv:info: ", " hit b=2", " base", "dv:warn: ", " same"}
+	s := base.String()
+	for _, w := range want {
+		if !strings.Contains(s, w) {
+			t.Fatal("missing", w, "in", s)
+		}
+	}
+	if strings.Contains(s, "hidden") || strings.Contains(s, "elsewhere") ||
+		strings.Contains(s, " base a=1") || strings.Contains(s, " query a=1 b=2") ||
+		!strings.HasSuffix(other.String(), " elsewhere\n") {
+		t.Fatal("derived Loggers must not affect each other:", s, other.String())
+	}
+	if len(lg.enrichers) != 3 || lg.GetLevel() != Sinfo || lg.Name() != "dv:" {
+		t.Fatal("base Logger must not change")
+	}
+	if lg.Count(Sinfo) != 3 || db.Count(Sdebug) != 1 {
+		t.Fatal("derived Loggers must share statistics")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("non-printable suffix must panic")
+		}
+	}()
+	lg.WithName("a\tb")
+}
+
+func TestDeriveRace(t *testing.T) {
+	lg := New(": dr:", ioutil.Discard, Sinfo)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			lg.SetLevel(Severity(i % 3))
+			lg.UpdateWriter(ioutil.Discard)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		c := lg.WithCallerSkip(1)
+		if c.GetLevel() > Swarn {
+			t.Fatal("bad level")
+		}
+	}
+	<-done
+}
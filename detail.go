@@ -0,0 +1,87 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// SetErrorDetail enables or disables error details for error & fatal records. When
+// enabled, error members of message lists are inspected: stack traces of errors with a
+// StackTrace() method (like github.com/pkg/errors) are rendered with %+v, otherwise
+// wrapped error chains (see errors.Unwrap) are rendered one cause per line. Details are
+// available as Record.Detail. It should be called before Logger is used.
+func (lg *Logger) SetErrorDetail(on bool) {
+	lg.detail = on
+}
+
+// errorDetail returns details of error members of msg, or empty string
+func errorDetail(msg []interface{}) string {
+	var sb strings.Builder
+	for _, m := range msg {
+		err, ok := m.(error)
+		if !ok || err == nil {
+			continue
+		}
+
+		d := ""
+		if hasStackTrace(err) {
+			d = fmt.Sprintf("%+v", err)
+		} else if errors.Unwrap(err) != nil {
+			d = errorChain(err)
+		}
+		if d == "" {
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteByte('\n')
+		}
+		sb.WriteString(strings.TrimRight(d, "\n"))
+	}
+	return sb.String()
+}
+
+// hasStackTrace checks if err or any error it wraps has a StackTrace() method
+func hasStackTrace(err error) bool {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if reflect.ValueOf(err).MethodByName("StackTrace").IsValid() {
+			return true
+		}
+	}
+	return false
+}
+
+// errorChain renders err and its causes one per line
+func errorChain(err error) string {
+	var sb strings.Builder
+	sb.WriteString(err.Error())
+	for err = errors.Unwrap(err); err != nil; err = errors.Unwrap(err) {
+		sb.WriteString("\ncaused by: ")
+		sb.WriteString(err.Error())
+	}
+	return sb.String()
+}
+
+// appendDetail appends detail lines of r, each prefixed with a tab
+func appendDetail(buf []byte, r *Record) []byte {
+	d := r.Detail
+	for d != "" {
+		line := d
+		if i := strings.IndexByte(d, '\n'); i >= 0 {
+			line, d = d[:i], d[i+1:]
+		} else {
+			d = ""
+		}
+		buf = append(buf, '\t')
+		buf = append(buf, line...)
+		buf = append(buf, '\n')
+	}
+	return buf
+}
@@ -0,0 +1,52 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// stackError mimics github.com/pkg/errors
+type stackError struct{ msg string }
+
+func (e *stackError) Error() string { return e.msg }
+
+func (e *stackError) StackTrace() []uintptr { return nil }
+
+func (e *stackError) Format(s fmt.State, verb rune) {
+	fmt.Fprint(s, e.msg)
+	if s.Flag('+') {
+		fmt.Fprint(s, "\nmain.f\n\tmain.go:12\n")
+	}
+}
+
+func TestErrorDetail(t *testing.T) {
+	var sb strings.Builder
+	lg := New(": det:", &sb, Sinfo)
+	lg.SetErrorDetail(true)
+
+	base := errors.New("base")
+	chain := fmt.Errorf("outer: %w", fmt.Errorf("inner: %w", base))
+
+	if err := lg.Log(Swarn, "no details below error", chain); err != nil {
+		t.Fatal(err)
+	}
+	if err := lg.Log(Serror, "failed", chain, base, &stackError{"stk"}); err != nil {
+		t.Fatal(err)
+	}
+	want := ": det:error: testing.go:"
+	lines := strings.Split(sb.String(), "\n")
+	if len(lines) != 9 || !strings.Contains(lines[1], want) || lines[8] != "" ||
+		lines[2] != "\touter: inner: base" || lines[3] != "\tcaused by: inner: base" ||
+		lines[4] != "\tcaused by: base" || lines[5] != "\tstk" || lines[6] != "\tmain.f" ||
+		lines[7] != "\t\tmain.go:12" {
+		t.Fatalf("unexpected records: %q", lines)
+	}
+}
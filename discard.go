@@ -0,0 +1,30 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"io"
+	"io/ioutil"
+)
+
+// Discarder is implemented by writers that may discard all records, like a sink disabled
+// by configuration. Loggers with ioutil.Discard or a writer whose Discards returns true
+// (when it is set, see UpdateWriter) skip caller lookup, message assembly & encoding of
+// records entirely, unless they have observers, rings or error rate monitoring (see
+// SetErrorRate). Skipped records are still sampled & counted, see Count.
+type Discarder interface {
+	Discards() bool
+}
+
+// discards reports whether w discards all records
+func discards(w io.Writer) bool {
+	if w == ioutil.Discard {
+		return true
+	}
+	d, ok := w.(Discarder)
+	return ok && d.Discards()
+}
@@ -0,0 +1,53 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+// switchWriter discards records when off
+type switchWriter struct {
+	strings.Builder
+	off bool
+}
+
+func (sw *switchWriter) Discards() bool {
+	return sw.off
+}
+
+func TestDiscard(t *testing.T) {
+	lg := New(": dis:", ioutil.Discard, Sinfo)
+	msg := []interface{}{"free", Any("k", 1)}
+	if a := testing.AllocsPerRun(100, func() { lg.Log(Sinfo, msg...) }); a != 0 {
+		t.Fatal("discarded records must not allocate:", a)
+	}
+	if lg.Count(Sinfo) != 101 {
+		t.Fatal("discarded records must be counted:", lg.Count(Sinfo))
+	}
+
+	var observed int
+	lg.AddObserver(func(Record) { observed++ })
+	lg.Log(Sinfo, "observed")
+	if observed != 1 {
+		t.Fatal("observers must see records")
+	}
+
+	sw := &switchWriter{off: true}
+	lg = New(": dis:", sw, Sinfo)
+	lg.Log(Swarn, "skipped")
+	sw.off = false
+	lg.Log(Swarn, "still skipped") // until writer is set again
+	lg.UpdateWriter(sw)
+	lg.Log(Swarn, "written")
+	if s := sw.String(); strings.Contains(s, "skipped") || !strings.HasSuffix(s, " written\n") ||
+		lg.Count(Swarn) != 3 {
+		t.Fatal("unexpected records:", s)
+	}
+}
@@ -0,0 +1,41 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SetDevelopment enables or disables development mode, in which DPanic panics. See
+// NewDevelopment. It should be called before Logger is used.
+func (lg *Logger) SetDevelopment(on bool) {
+	lg.development = on
+}
+
+// DPanic is for conditions that should never happen. It is not a Severity: it logs message
+// list at Serror, then panics with Logger name & message list in development mode (see
+// SetDevelopment), so such conditions are caught early without crashing production
+// services.
+func (lg *Logger) DPanic(msg ...interface{}) error {
+	return lg.dpanic(msg)
+}
+
+// DPanic logs message list at Serror to Default logger, then panics in development mode,
+// see Logger.DPanic
+func DPanic(msg ...interface{}) error {
+	return GetDefault().dpanic(msg)
+}
+
+// dpanic implements DPanic, it must be called directly by them for correct caller depth
+func (lg *Logger) dpanic(msg []interface{}) error {
+	err := lg.log(1, 0, nil, Serror, msg, nil)
+	if lg.development {
+		panic(lg.Name() + " dpanic: " + strings.TrimSuffix(fmt.Sprintln(msg...), "\n"))
+	}
+	return err
+}
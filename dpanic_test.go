@@ -0,0 +1,37 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDPanic(t *testing.T) {
+	var sb strings.Builder
+	lg := New(": dp:", &sb, Sinfo)
+
+	if err := lg.DPanic("impossible", 1); err != nil ||
+		!strings.Contains(sb.String(), "dp:error: dpanic_test.go:") ||
+		!strings.HasSuffix(sb.String(), " impossible 1\n") {
+		t.Fatal("production DPanic must log error:", err, sb.String())
+	}
+
+	sb.Reset()
+	lg.SetDevelopment(true)
+	func() {
+		defer func() {
+			if p := recover(); p != "dp: dpanic: impossible 2" {
+				t.Fatal("development DPanic must panic:", p)
+			}
+		}()
+		lg.DPanic("impossible", 2)
+	}()
+	if !strings.HasSuffix(sb.String(), " impossible 2\n") {
+		t.Fatal("record must be logged before panic:", sb.String())
+	}
+}
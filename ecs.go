@@ -0,0 +1,89 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"strconv"
+	"time"
+)
+
+// ECSVersion is the Elastic Common Schema version of ECSEncoder records
+const ECSVersion = "8.11.0"
+
+// ECSEncoder writes JSON records with Elastic Common Schema field names, so Elastic
+// ingestion (like Filebeat) needs no pipeline processors:
+//
+//	{"@timestamp":"2021-03-28T21:48:53.591948Z","log.level":"warn","log.logger":"mypkg",
+//	 "ecs.version":"8.11.0","log.origin.file.name":"myApp.go","log.origin.file.line":15,
+//	 "message":"some warning","key":"value"}
+//
+// Time is always in UTC. Error members of message list are error.message & error.type
+// (arrays with an entry per error if there are multiple errors), error details are
+// error.stack_trace. Sequence number & identifier (if enabled) are event.sequence &
+// event.id. Fields named "trace" & "span" go to trace.id & span.id, other fields are
+// top-level keys, which should follow ECS naming for custom fields.
+type ECSEncoder struct{}
+
+// Encode appends ECS record to buf
+func (ECSEncoder) Encode(buf []byte, r *Record) ([]byte, error) {
+	buf = append(buf, `{"@timestamp":"`...)
+	buf = r.tcache.append(buf, r.Time.UTC(), time.RFC3339Nano)
+	buf = append(buf, `","log.level":`...)
+	buf = appendJSONString(buf, levelName(r.Level))
+	buf = append(buf, `,"log.logger":`...)
+	buf = appendJSONString(buf, r.Name)
+	buf = append(buf, `,"ecs.version":"`+ECSVersion+`"`...)
+
+	if r.Seq != 0 {
+		buf = append(buf, `,"event.sequence":`...)
+		buf = strconv.AppendUint(buf, r.Seq, 10)
+	}
+	if r.ID != "" {
+		buf = append(buf, `,"event.id":"`...)
+		buf = append(buf, r.ID...)
+		buf = append(buf, '"')
+	}
+
+	if r.File != "" {
+		buf = append(buf, `,"log.origin.file.name":`...)
+		buf = appendJSONString(buf, r.File)
+		buf = append(buf, `,"log.origin.file.line":`...)
+		buf = strconv.AppendInt(buf, int64(r.Line), 10)
+	}
+
+	buf = append(buf, `,"message":`...)
+	buf = appendJSONString(buf, r.Msg)
+
+	if len(r.Errs) > 0 {
+		buf = append(buf, `,"error.message":`...)
+		buf = appendErrorList(buf, r.Errs, errorMsg)
+		buf = append(buf, `,"error.type":`...)
+		buf = appendErrorList(buf, r.Errs, errorType)
+	}
+	if r.Detail != "" {
+		buf = append(buf, `,"error.stack_trace":`...)
+		buf = appendJSONString(buf, r.Detail)
+	}
+
+	var err error
+	for _, f := range r.Fields {
+		switch f.Key {
+		case "trace":
+			buf = append(buf, `,"trace.id":`...)
+		case "span":
+			buf = append(buf, `,"span.id":`...)
+		default:
+			buf = append(buf, ',')
+			buf = appendJSONString(buf, f.Key)
+			buf = append(buf, ':')
+		}
+		if buf, err = appendJSONField(buf, f); err != nil {
+			return buf, err
+		}
+	}
+	return append(buf, "}\n"...), nil
+}
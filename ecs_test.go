@@ -0,0 +1,53 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestECSEncoder(t *testing.T) {
+	loc := time.FixedZone("X", 3*3600)
+	r := Record{Time: time.Date(2021, 3, 29, 0, 48, 53, 591948000, loc),
+		Level: Swarn, Name: "mypkg", File: "a.go", Line: 15, Msg: "slow", Seq: 4,
+		Fields: []Field{Any("trace", "abc"), Any("span", "12"), Any("n", 3)}}
+
+	b, err := ECSEncoder{}.Encode(nil, &r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"@timestamp":"2021-03-28T21:48:53.591948Z","log.level":"warn",` +
+		`"log.logger":"mypkg","ecs.version":"` + ECSVersion + `","event.sequence":4,` +
+		`"log.origin.file.name":"a.go","log.origin.file.line":15,"message":"slow",` +
+		`"trace.id":"abc","span.id":"12","n":3}` + "\n"
+	if string(b) != want {
+		t.Fatalf("unexpected record:\n%s%s", b, want)
+	}
+
+	r = Record{Level: Serror, Msg: "failed", Errs: []error{errors.New("boom")},
+		Detail: "stack", ID: "01F"}
+	b, _ = ECSEncoder{}.Encode(nil, &r)
+	var m map[string]interface{}
+	if err = json.Unmarshal(b, &m); err != nil || m["log.level"] != "error" ||
+		m["error.message"] != "boom" || m["error.type"] != "*errors.errorString" ||
+		m["error.stack_trace"] != "stack" || m["event.id"] != "01F" || m["message"] != "failed" {
+		t.Fatal("unexpected record:", string(b), err)
+	}
+
+	r.Errs = append(r.Errs, fmt.Errorf("open: %w", r.Errs[0]))
+	b, _ = ECSEncoder{}.Encode(nil, &r)
+	want = `"error.message":["boom","open: boom"],` +
+		`"error.type":["*errors.errorString","*fmt.wrapError"]`
+	if !strings.Contains(string(b), want) {
+		t.Fatal("unexpected record:", string(b))
+	}
+}
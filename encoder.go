@@ -0,0 +1,160 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Encoder converts records to their wire format
+type Encoder interface {
+	// Encode appends encoded r (ending with a newline for line-oriented formats) to buf
+	// and returns extended buffer, or an error if r cannot be encoded.
+	Encode(buf []byte, r *Record) ([]byte, error)
+}
+
+// SetEncoder sets Encoder of Logger, nil means TextEncoder{}. See also SetFormat.
+func (lg *Logger) SetEncoder(enc Encoder) {
+	if enc == nil {
+		enc = TextEncoder{}
+	}
+	lg.setOutput(lg.output().writer, enc)
+}
+
+// TextEncoder is the default human-friendly Encoder:
+//
+//	2021-03-28 21:48:53.591948: mypkg:info: myApp.go:15: some info: 1 more key=value
+//
+// It utilizes TimeFormat (see SetTimeMode) & Sname (or other labels, see SetLabels).
+// Severity names are tinted with Scolor if Color is true, logger names are tinted with
+// NameColor if ColorName is true. Field keys & values are quoted if necessary. Sequence
+// number & identifier (if enabled) follow fields as seq=N id=ULID. Error details (if any)
+// follow the record, one tab-indented line each.
+type TextEncoder struct {
+	Color, ColorName bool
+	Labels           LabelStyle
+	Indent           bool // indent continuation lines of messages, see SetIndent
+}
+
+// Encode appends text record to buf
+func (e TextEncoder) Encode(buf []byte, r *Record) ([]byte, error) {
+	buf = e.appendHead(buf, r)
+	if m := r.Text(); m != "" {
+		buf = append(buf, ' ')
+		if e.Indent {
+			buf = appendIndented(buf, m)
+		} else {
+			buf = append(buf, m...)
+		}
+	}
+	return e.appendTail(buf, r), nil
+}
+
+// appendHead appends time, name, label & location of record to buf
+func (e TextEncoder) appendHead(buf []byte, r *Record) []byte {
+	delims := &nameDelims
+	if r.delims != nil {
+		delims = r.delims
+	}
+	if r.tmode != Tnone {
+		buf = r.AppendTime(buf, TimeFormat)
+		buf = appendDelim(buf, delims[0])
+	}
+
+	if e.ColorName {
+		buf = append(buf, NameColor...)
+		buf = append(buf, r.Name...)
+		buf = append(buf, colorReset...)
+	} else {
+		buf = append(buf, r.Name...)
+	}
+	buf = appendDelim(buf, delims[1])
+
+	if e.Color {
+		buf = append(buf, Scolor[r.Level]...)
+		buf = append(buf, label(e.Labels, r.Level)...)
+		buf = append(buf, colorReset...)
+	} else {
+		buf = append(buf, label(e.Labels, r.Level)...)
+	}
+
+	if r.File != "" {
+		buf = append(buf, ' ')
+		buf = append(buf, r.File...)
+		buf = append(buf, ':')
+		buf = strconv.AppendInt(buf, int64(r.Line), 10)
+		buf = append(buf, ':')
+	}
+	return buf
+}
+
+// appendDelim appends name delimiter d to buf, a space if d is empty
+func appendDelim(buf []byte, d string) []byte {
+	if d == "" {
+		return append(buf, ' ')
+	}
+	return append(buf, d...)
+}
+
+// appendTail appends fields, sequence number, identifier & details of record to buf
+func (e TextEncoder) appendTail(buf []byte, r *Record) []byte {
+	for _, f := range r.Fields {
+		buf = append(buf, ' ')
+		buf = appendText(buf, f.Key)
+		buf = append(buf, '=')
+		buf = appendFieldValue(buf, f)
+	}
+
+	if r.Seq != 0 {
+		buf = append(buf, " seq="...)
+		buf = strconv.AppendUint(buf, r.Seq, 10)
+	}
+	if r.ID != "" {
+		buf = append(buf, " id="...)
+		buf = append(buf, r.ID...)
+	}
+	return appendDetail(append(buf, '\n'), r)
+}
+
+// appendValue appends v formatted with %v, quoted if empty or has spaces, quotes, equal
+// signs or non-printable characters
+func appendValue(buf []byte, v interface{}) []byte {
+	s, ok := v.(string)
+	if !ok {
+		s = fmt.Sprint(v)
+	}
+	return appendText(buf, s)
+}
+
+// appendText appends s, quoted if necessary like appendValue
+func appendText(buf []byte, s string) []byte {
+	if s == "" || strings.IndexFunc(s, needsQuote) >= 0 {
+		return strconv.AppendQuote(buf, s)
+	}
+	return append(buf, s...)
+}
+
+func needsQuote(r rune) bool {
+	return r <= ' ' || r == '"' || r == '=' || r == '\\' || !unicode.IsPrint(r)
+}
+
+// pool of record buffers
+var bufPool = sync.Pool{New: func() interface{} {
+	b := make([]byte, 0, 256)
+	return &b
+}}
+
+// putBuf returns bp to bufPool unless it grew too large
+func putBuf(bp *[]byte) {
+	if cap(*bp) <= 64<<10 {
+		bufPool.Put(bp)
+	}
+}
@@ -0,0 +1,69 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var errEncode = errors.New("cannot encode")
+
+// upperEncoder is a custom encoder
+type upperEncoder struct{}
+
+func (upperEncoder) Encode(buf []byte, r *Record) ([]byte, error) {
+	if r.Msg == "fail" {
+		return buf, errEncode
+	}
+	return append(buf, strings.ToUpper(r.Msg)+"\n"...), nil
+}
+
+func TestEncoders(t *testing.T) {
+	r := Record{Time: time.Date(2021, 3, 28, 21, 48, 53, 0, time.UTC), Level: Swarn,
+		Name: "enc", File: "a.go", Line: 7, Msg: "some msg",
+		Fields: []Field{Any("k", 1), Any("s", "two words"), Any("e", "")}}
+
+	tests := [...]struct {
+		enc Encoder
+		out string
+	}{
+		{TextEncoder{}, `2021-03-28 21:48:53.000000: enc:warn: a.go:7: some msg k=1 s="two words" e=""` + "\n"},
+		{LogfmtEncoder{}, `time=2021-03-28T21:48:53Z level=warn name=enc caller=a.go:7 msg="some msg" k=1 s="two words" e=""` + "\n"},
+		{JSONEncoder{}, `{"time":"2021-03-28T21:48:53Z","name":"enc","level":"warn","caller":"a.go:7","msg":"some msg","k":1,"s":"two words","e":""}` + "\n"},
+	}
+	for _, tc := range tests {
+		b, err := tc.enc.Encode(nil, &r)
+		if err != nil || string(b) != tc.out {
+			t.Fatalf("%T: %q %v", tc.enc, b, err)
+		}
+	}
+
+	// unsupported JSON value
+	r.Fields = []Field{Any("c", make(chan int))}
+	if _, err := (JSONEncoder{}).Encode(nil, &r); err == nil {
+		t.Fatal("must fail to encode")
+	}
+
+	var sb strings.Builder
+	lg := New(": enc:", &sb, Sinfo)
+	lg.SetEncoder(upperEncoder{})
+
+	if err := lg.Log(Sinfo, "up", Any("ignored", 1), 2); err != nil || sb.String() != "UP 2\n" {
+		t.Fatal("unexpected record:", sb.String(), err)
+	}
+	if err := lg.Log(Sinfo, "fail"); !errors.Is(err, errEncode) {
+		t.Fatal("must fail to encode")
+	}
+
+	msg, fields := splitFields([]interface{}{Any("a", 1), "x", 2, Any("b", 3)})
+	if msg != "x 2" || len(fields) != 2 || fields[1].Key != "b" {
+		t.Fatal("unexpected split:", msg, fields)
+	}
+}
@@ -0,0 +1,41 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+// Enricher returns fields attached to every record of a Logger, like metadata of the
+// environment. It is called for each record, so it should be quick, for example return
+// fields computed once.
+type Enricher func() []Field
+
+// AddEnricher adds e to Logger's enrichers. Their fields precede other fields of records
+// (like bound fields, see SetSortFields) in the order enrichers were added. It should be
+// called before Logger is used.
+func (lg *Logger) AddEnricher(e Enricher) {
+	if e != nil {
+		lg.enrichers = append(lg.enrichers, e)
+	}
+}
+
+// enrich returns msg with fields of enrichers prepended, and the new number of bound
+// fields
+func (lg *Logger) enrich(msg []interface{}, bound int) ([]interface{}, int) {
+	out := make([]interface{}, 0, len(msg)+8)
+	for _, e := range lg.enrichers {
+		for _, f := range e() {
+			out = append(out, f)
+		}
+	}
+	return append(out, msg...), bound + len(out)
+}
+
+// StaticEnricher returns an Enricher of fields, which are copied
+func StaticEnricher(fields ...Field) Enricher {
+	fields = append([]Field(nil), fields...)
+	return func() []Field {
+		return fields
+	}
+}
@@ -0,0 +1,32 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestEnricher(t *testing.T) {
+	var sb strings.Builder
+	lg := New(": enr:", &sb, Sinfo)
+	lg.AddEnricher(StaticEnricher(Any("region", "eu"), Any("app", "x")))
+	lg.AddEnricher(nil)
+	ctx := WithScope(context.Background(), Any("user", 1))
+
+	lg.Log(Sinfo, "a", Any("n", 2), ctx)
+	lg.SetSortFields(true)
+	lg.Log(Sinfo, "b", Any("n", 2), ctx)
+	lg.Log(Sinfo, context.Background()) // empty message, enrichers alone are not logged
+
+	lines := strings.Split(sb.String(), "\n")
+	if len(lines) != 3 || !strings.HasSuffix(lines[0], ": a region=eu app=x user=1 n=2") ||
+		!strings.HasSuffix(lines[1], ": b app=x region=eu user=1 n=2") {
+		t.Fatal("bad enriched records", sb.String())
+	}
+}
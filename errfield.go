@@ -0,0 +1,153 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Text returns message list of record as text encoders print it: Msg followed by Errs.
+func (r *Record) Text() string {
+	if r.text != "" {
+		return r.text
+	}
+	if len(r.Errs) == 0 {
+		return r.Msg
+	}
+	return joinErrors(r.Msg, r.Errs)
+}
+
+// joinErrors appends errors to msg like fmt.Sprintln without newline
+func joinErrors(msg string, errs []error) string {
+	for _, err := range errs {
+		if msg != "" {
+			msg += " "
+		}
+		msg += fmt.Sprint(err)
+	}
+	return msg
+}
+
+// messageErrors returns non-nil error members of msg
+func messageErrors(msg []interface{}) (errs []error) {
+	for _, m := range msg {
+		if err, ok := m.(error); ok && err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return
+}
+
+// withoutErrors returns a copy of msg without its n non-nil error members
+func withoutErrors(msg []interface{}, n int) []interface{} {
+	out := make([]interface{}, 0, len(msg)-n)
+	for _, m := range msg {
+		if err, ok := m.(error); !ok || err == nil {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// causes returns messages of errors wrapped by err
+func causes(err error) (list []string) {
+	for err = errors.Unwrap(err); err != nil; err = errors.Unwrap(err) {
+		list = append(list, err.Error())
+	}
+	return
+}
+
+// appendJSONError appends err as a JSON object with its message, type & causes
+func appendJSONError(buf []byte, err error) []byte {
+	buf = append(buf, `{"msg":`...)
+	buf = appendJSONString(buf, err.Error())
+	buf = append(buf, `,"type":`...)
+	buf = appendJSONString(buf, fmt.Sprintf("%T", err))
+
+	if cs := causes(err); len(cs) > 0 {
+		buf = append(buf, `,"causes":[`...)
+		for i, c := range cs {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = appendJSONString(buf, c)
+		}
+		buf = append(buf, ']')
+	}
+	return append(buf, '}')
+}
+
+// appendJSONErrors appends errs as "error" object, or "errors" array of objects if there
+// are multiple errors
+func appendJSONErrors(buf []byte, errs []error) []byte {
+	switch len(errs) {
+	case 0:
+		return buf
+	case 1:
+		buf = append(buf, `,"error":`...)
+		return appendJSONError(buf, errs[0])
+	}
+
+	buf = append(buf, `,"errors":[`...)
+	for i, err := range errs {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = appendJSONError(buf, err)
+	}
+	return append(buf, ']')
+}
+
+// errorMsg & errorType return message & type of err
+func errorMsg(err error) string  { return err.Error() }
+func errorType(err error) string { return fmt.Sprintf("%T", err) }
+
+// appendErrorList appends a JSON string of each error with f as is, or as an array if
+// there are multiple errors, so entries of errors in separate lists match
+func appendErrorList(buf []byte, errs []error, f func(error) string) []byte {
+	if len(errs) == 1 {
+		return appendJSONString(buf, f(errs[0]))
+	}
+	buf = append(buf, '[')
+	for i, err := range errs {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = appendJSONString(buf, f(err))
+	}
+	return append(buf, ']')
+}
+
+// appendLogfmtErrors appends errs as error=msg error.type=T error.causes="cause1; cause2"
+// pairs, numbering keys if there are multiple errors
+func appendLogfmtErrors(buf []byte, errs []error) []byte {
+	for i, err := range errs {
+		key := " error"
+		if len(errs) > 1 {
+			key += strconv.Itoa(i + 1)
+		}
+		buf = append(buf, key...)
+		buf = append(buf, '=')
+		buf = appendValue(buf, err.Error())
+		buf = append(buf, key...)
+		buf = append(buf, ".type="...)
+		buf = appendValue(buf, fmt.Sprintf("%T", err))
+
+		if cs := causes(err); len(cs) > 0 {
+			c := cs[0]
+			for _, s := range cs[1:] {
+				c += "; " + s
+			}
+			buf = append(buf, key...)
+			buf = append(buf, ".causes="...)
+			buf = appendValue(buf, c)
+		}
+	}
+	return buf
+}
@@ -0,0 +1,74 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestErrorField(t *testing.T) {
+	var sb strings.Builder
+	lg := New(": ef:", &sb, Sinfo)
+
+	var recs []Record
+	lg.AddObserver(func(r Record) {
+		recs = append(recs, r)
+	})
+
+	chain := fmt.Errorf("outer: %w", fmt.Errorf("inner: %w", errors.New("base")))
+	want := [...]string{
+		"request failed outer: inner: base\n",
+		`"msg":"request failed","error":{"msg":"outer: inner: base","type":"*fmt.wrapError",` +
+			`"causes":["inner: base","base"]}}` + "\n",
+		`msg="request failed" error="outer: inner: base" error.type=*fmt.wrapError ` +
+			`error.causes="inner: base; base"` + "\n",
+	}
+	for i, enc := range [...]Encoder{TextEncoder{}, JSONEncoder{}, LogfmtEncoder{}} {
+		sb.Reset()
+		lg.SetEncoder(enc)
+		if err := lg.Log(Serror, "request", "failed", chain); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasSuffix(sb.String(), want[i]) {
+			t.Fatalf("%T: unexpected record: %s", enc, sb.String())
+		}
+	}
+
+	r := recs[0]
+	if r.Msg != "request failed" || len(r.Errs) != 1 || r.Errs[0] != chain ||
+		r.Text() != "request failed outer: inner: base" {
+		t.Fatal("unexpected record:", r)
+	}
+
+	// only error
+	r = Record{Errs: []error{chain, chain}}
+	if r.Text() != "outer: inner: base outer: inner: base" {
+		t.Fatal("unexpected text:", r.Text())
+	}
+	b := appendJSONErrors(nil, r.Errs)
+	if !strings.HasPrefix(string(b), `,"errors":[{`) {
+		t.Fatal("unexpected errors:", string(b))
+	}
+
+	// all errors are tagged, text keeps them in place
+	sb.Reset()
+	recs = nil
+	lg.SetEncoder(JSONEncoder{})
+	base := errors.New("base")
+	if err := lg.Log(Serror, chain, "x", base, Any("k", 1)); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(sb.String(), `"msg":"x","errors":[{"msg":"outer: inner: base",`+
+		`"type":"*fmt.wrapError","causes":["inner: base","base"]},`+
+		`{"msg":"base","type":"*errors.errorString"}],"k":1}`+"\n") ||
+		recs[0].Text() != "outer: inner: base x base" {
+		t.Fatal("unexpected record:", sb.String())
+	}
+}
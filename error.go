@@ -0,0 +1,51 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import "sync/atomic"
+
+// Log operations that can fail
+const (
+	OpEncode = "encode" // Encoder failed
+	OpWrite  = "write"  // writer failed
+)
+
+// LogError is returned by Log for failed records, so callers can distinguish encoding
+// failures from write failures. Underlying error is available via errors.Is/As.
+type LogError struct {
+	Op    string   // OpEncode or OpWrite
+	Name  string   // logger name like mypkg
+	Level Severity // severity of record
+	Err   error    // underlying error
+}
+
+func (e *LogError) Error() string {
+	return "yell: " + e.Name + ":" + Sname[e.Level] + " " + e.Op + ": " + e.Err.Error()
+}
+
+// Unwrap returns underlying error
+func (e *LogError) Unwrap() error {
+	return e.Err
+}
+
+// SetErrorHandler sets a function that is called with *LogError for every failed record,
+// so log loss is observable even if callers ignore errors returned by Log. Handler must
+// not log to the same Logger. nil disables the handler. It should be called before
+// Logger is used.
+func (lg *Logger) SetErrorHandler(handler func(error)) {
+	lg.onError = handler
+}
+
+// fail creates a *LogError for r and reports it to error handler
+func (lg *Logger) fail(op string, r *Record, err error) error {
+	atomic.AddUint64(&lg.stats.failed, 1)
+	err = &LogError{op, r.Name, r.Level, err}
+	if lg.onError != nil {
+		lg.onError(err)
+	}
+	return err
+}
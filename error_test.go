@@ -0,0 +1,50 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"errors"
+	"testing"
+)
+
+var errWrite = errors.New("disk full")
+
+type failWriter struct{}
+
+func (failWriter) Write([]byte) (int, error) {
+	return 0, errWrite
+}
+
+func TestLogError(t *testing.T) {
+	lg := New(": lerr:", failWriter{}, Sinfo)
+	var handled []error
+	lg.SetErrorHandler(func(err error) {
+		handled = append(handled, err)
+	})
+
+	err := lg.Log(Swarn, "msg")
+	var le *LogError
+	if !errors.As(err, &le) || le.Op != OpWrite || le.Name != "lerr" || le.Level != Swarn ||
+		!errors.Is(err, errWrite) || err.Error() != "yell: lerr:warn: write: disk full" {
+		t.Fatal("unexpected error:", err)
+	}
+
+	lg.SetEncoder(upperEncoder{})
+	err = lg.Log(Serror, "fail")
+	if !errors.As(err, &le) || le.Op != OpEncode || le.Level != Serror ||
+		!errors.Is(err, errEncode) {
+		t.Fatal("unexpected error:", err)
+	}
+	if len(handled) != 2 || !errors.Is(handled[0], errWrite) || handled[1] != err {
+		t.Fatal("must report errors to handler:", handled)
+	}
+
+	lg.SetErrorHandler(nil)
+	if lg.Log(Swarn, "msg") == nil || len(handled) != 2 {
+		t.Fatal("must not call handler")
+	}
+}
@@ -0,0 +1,62 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// rateMonitor counts error & fatal records in each window, shared by copies of Logger
+type rateMonitor struct {
+	threshold uint64
+	width     int64 // of window in nanoseconds
+	alert     func(count int)
+
+	window int64  // start of current window in Unix nanoseconds, accessed atomically
+	count  uint64 // records in current window, accessed atomically
+}
+
+// SetErrorRate enables in-process alerting: alert is called (once per window) when error
+// & fatal records logged in a window reach threshold, with their count. It is called
+// synchronously after the record reaching threshold, so it should be quick. nil alert
+// logs a warn record "yell: error rate exceeded" with errors & window fields instead.
+// Non-positive threshold or window disables it, which is the default. It should be called
+// before Logger is used.
+func (lg *Logger) SetErrorRate(threshold int, window time.Duration, alert func(count int)) {
+	if threshold <= 0 || window <= 0 {
+		lg.rate = nil
+		return
+	}
+	lg.rate = &rateMonitor{threshold: uint64(threshold), width: int64(window), alert: alert}
+}
+
+// exceeded counts a record at time now, reports whether it reaches threshold
+func (m *rateMonitor) exceeded(now time.Time) bool {
+	ns := now.UnixNano()
+	if w := atomic.LoadInt64(&m.window); ns-w >= m.width &&
+		atomic.CompareAndSwapInt64(&m.window, w, ns) {
+		atomic.StoreUint64(&m.count, 0) // new window
+	}
+	return atomic.AddUint64(&m.count, 1) == m.threshold
+}
+
+// rateAlert calls alert of rate monitor or logs a warn record. It is deferred by log with
+// its skip, mode & caller depth, so the record gets location of the record reaching
+// threshold.
+func (lg *Logger) rateAlert(skip int, mode logMode, depth int) {
+	m := lg.rate
+	if m.alert != nil {
+		m.alert(int(m.threshold))
+		return
+	}
+	if skip != noCaller {
+		skip += depth + 2 // rateAlert & log frames
+	}
+	lg.log(skip, mode, nil, Swarn, []interface{}{"yell: error rate exceeded",
+		Int("errors", int(m.threshold)), Dur("window", time.Duration(m.width))}, nil)
+}
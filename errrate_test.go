@@ -0,0 +1,61 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestErrorRate(t *testing.T) {
+	var sb strings.Builder
+	lg := New(": er:", &sb, Sinfo)
+	lg.SetTimeMode(Tnone)
+
+	var alerts []int
+	lg.SetErrorRate(3, time.Hour, func(n int) { alerts = append(alerts, n) })
+	for i := 0; i < 5; i++ {
+		lg.Log(Serror, "e")
+		lg.Log(Swarn, "w")
+	}
+	if len(alerts) != 1 || alerts[0] != 3 {
+		t.Fatal("alert must be called once per window:", alerts)
+	}
+	lg.rate.window -= int64(time.Hour) // new window
+	lg.Log(Sfatal, "f")
+	lg.Log(Serror, "e")
+	lg.Log(Serror, "e")
+	if len(alerts) != 2 {
+		t.Fatal("alert must be called in new window:", alerts)
+	}
+
+	// warn record with location of the third error
+	sb.Reset()
+	lg.SetErrorRate(2, time.Minute, nil)
+	wrapLog(&lg, "w")
+	wrapErr(&lg)
+	wrapErr(&lg)
+	lines := strings.Split(sb.String(), "\n")
+	if len(lines) != 5 || !strings.HasSuffix(lines[3],
+		": yell: error rate exceeded errors=2 window=1m0s") ||
+		!strings.HasPrefix(lines[3], "er:warn: errrate_test.go:") ||
+		lines[3][:strings.Index(lines[3], " yell:")] != strings.Replace(
+			lines[2][:strings.Index(lines[2], " failed")], "error", "warn", 1) {
+		t.Fatal("unexpected records:", sb.String())
+	}
+
+	lg.SetErrorRate(0, time.Minute, nil)
+	if lg.rate != nil {
+		t.Fatal("error rate must be disabled")
+	}
+}
+
+// wrapErr is like Error of the designed use case
+func wrapErr(lg *Logger) {
+	lg.Log(Serror, "failed")
+}
@@ -0,0 +1,71 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SetEscape enables or disables escaping of newlines & other control characters in
+// message lists. With escaping enabled, user-supplied strings cannot forge extra records
+// or inject terminal sequences into logs. Escaping is disabled by default.
+func (lg *Logger) SetEscape(on bool) {
+	lg.escape = on
+}
+
+// mustEscape reports whether r must be written as an escape sequence
+func mustEscape(r rune) bool {
+	return unicode.IsControl(r) || r == '\u2028' || r == '\u2029' || r == utf8.RuneError
+}
+
+const hexDigits = "0123456789abcdef"
+
+// escape replaces control characters in s with Go-style escape sequences
+func escape(s string) string {
+	i := strings.IndexFunc(s, mustEscape)
+	if i < 0 {
+		return s // nothing to escape
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(s) + 8)
+	sb.WriteString(s[:i])
+
+	for i < len(s) {
+		r, n := utf8.DecodeRuneInString(s[i:])
+		if !mustEscape(r) {
+			sb.WriteString(s[i : i+n])
+			i += n
+			continue
+		}
+
+		switch {
+		case r == '\n':
+			sb.WriteString(`\n`)
+		case r == '\r':
+			sb.WriteString(`\r`)
+		case r == '\t':
+			sb.WriteString(`\t`)
+		case r == utf8.RuneError && n == 1: // invalid byte
+			fallthrough
+		case r < utf8.RuneSelf:
+			b := s[i]
+			sb.WriteString(`\x`)
+			sb.WriteByte(hexDigits[b>>4])
+			sb.WriteByte(hexDigits[b&15])
+		default:
+			sb.WriteString(`\u`)
+			for sh := 12; sh >= 0; sh -= 4 {
+				sb.WriteByte(hexDigits[r>>uint(sh)&15])
+			}
+		}
+		i += n
+	}
+	return sb.String()
+}
@@ -0,0 +1,53 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEscape(t *testing.T) {
+	tests := [...]struct{ in, out string }{
+		{"", ""},
+		{"plain text", "plain text"},
+		{"a\nb\r\tc", `a\nb\r\tc`},
+		{"\x1b[31mred\x00", `\x1b[31mred\x00`},
+		{"bad\xffutf8", `bad\xffutf8`},
+		{"ş\u2028\u0085", `ş\u2028\u0085`},
+	}
+	for _, tc := range tests {
+		if s := escape(tc.in); s != tc.out {
+			t.Fatalf("escape(%q) = %q, want %q", tc.in, s, tc.out)
+		}
+	}
+
+	var sb strings.Builder
+	lg := New(": esc:", &sb, Sinfo)
+	lg.SetEscape(true)
+
+	if err := lg.Log(Sinfo, Caller(1), "user", "x\n2021-03-28: forged:fatal: y", 3); err != nil {
+		t.Fatal(err)
+	}
+	out := sb.String()
+	if strings.Count(out, "\n") != 1 ||
+		!strings.HasSuffix(out, `user x\n2021-03-28: forged:fatal: y 3`+"\n") {
+		t.Fatal("must escape newline:", out)
+	}
+
+	// field keys are quoted like values
+	for _, enc := range [...]Encoder{TextEncoder{}, LogfmtEncoder{}} {
+		sb.Reset()
+		lg.SetEncoder(enc)
+		lg.Log(Sinfo, "user", Any("k\n2021-03-28: forged:fatal: y", 1), Any("a=b", "c"))
+		out = sb.String()
+		if strings.Count(out, "\n") != 1 ||
+			!strings.HasSuffix(out, ` "k\n2021-03-28: forged:fatal: y"=1 "a=b"=c`+"\n") {
+			t.Fatal("must quote field keys:", out)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import "sync"
+
+// at-exit hooks
+var exitHooks struct {
+	sync.Mutex
+	fns []func()
+}
+
+// AtExit registers fn to be called by FatalExit & CloseOnSignal before they close
+// Loggers and exit, for example to release locks or report a crash. Hooks are called once,
+// in reverse order of registration. Their panics are recovered.
+func AtExit(fn func()) {
+	if fn == nil {
+		return
+	}
+	exitHooks.Lock()
+	exitHooks.fns = append(exitHooks.fns, fn)
+	exitHooks.Unlock()
+}
+
+// runExitHooks calls & removes registered hooks
+func runExitHooks() {
+	exitHooks.Lock()
+	fns := exitHooks.fns
+	exitHooks.fns = nil
+	exitHooks.Unlock()
+
+	for i := len(fns) - 1; i >= 0; i-- {
+		func() {
+			defer func() { recover() }()
+			fns[i]()
+		}()
+	}
+}
+
+// FatalExit logs message list with fatal severity, calls at-exit hooks (see AtExit),
+// flushes Logger, closes registered Loggers (see CloseAll) and exits with status code.
+func (lg *Logger) FatalExit(code int, msg ...interface{}) {
+	lg.fatalExit(code, msg)
+}
+
+// FatalExit logs message list with fatal severity to Default logger and exits with status
+// code, see Logger.FatalExit
+func FatalExit(code int, msg ...interface{}) {
+	GetDefault().fatalExit(code, msg)
+}
+
+// fatalExit implements FatalExit, it must be called directly by them for correct caller
+// depth
+func (lg *Logger) fatalExit(code int, msg []interface{}) {
+	lg.log(1, 0, nil, Sfatal, msg, nil)
+	runExitHooks()
+	lg.Flush()
+	CloseAll()
+	exit(code)
+}
@@ -0,0 +1,69 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestFatalExit(t *testing.T) {
+	registry.Lock()
+	saved := registry.loggers
+	registry.loggers = nil
+	registry.Unlock()
+	defer func() {
+		registry.Lock()
+		registry.loggers = saved
+		registry.Unlock()
+	}()
+
+	var rec, other closeRecorder
+	aw := NewAsyncWriter(&rec, 16)
+	lg := New(": fe:", aw, Sinfo)
+	lg2 := New(": fe2:", &other, Sinfo)
+	Register(&lg2)
+
+	var code int
+	exit = func(c int) { code = c }
+	defer func() { exit = os.Exit }()
+
+	var order []int
+	AtExit(func() { order = append(order, 1) })
+	AtExit(func() { panic("hook") })
+	AtExit(func() {
+		order = append(order, 3)
+		lg.Log(Sinfo, "from hook")
+	})
+	AtExit(nil)
+
+	lg.Log(Sinfo, "queued")
+	lg.FatalExit(3, "cannot continue")
+
+	if code != 3 {
+		t.Fatal("unexpected exit code:", code)
+	}
+	if len(order) != 2 || order[0] != 3 || order[1] != 1 {
+		t.Fatal("hooks must run in reverse order:", order)
+	}
+	s := rec.String()
+	if !strings.Contains(s, "fe:fatal: fatal_test.go:") ||
+		strings.Index(s, " queued\n") > strings.Index(s, " cannot continue\n") ||
+		!strings.HasSuffix(s, " from hook\n") {
+		t.Fatal("records must be written in order:", s)
+	}
+	if other.closes != 1 {
+		t.Fatal("registered Loggers must be closed")
+	}
+
+	// hooks run once
+	FatalExit(4)
+	if code != 4 || len(order) != 2 {
+		t.Fatal("hooks must run once:", code, order)
+	}
+}
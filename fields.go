@@ -0,0 +1,154 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"math"
+	"strconv"
+	"time"
+)
+
+// fieldKind is the type of a typed Field value, stored without interface boxing
+type fieldKind uint8
+
+// field kinds
+const (
+	kAny fieldKind = iota // value in Field.Value
+	kString
+	kInt64
+	kUint64
+	kFloat64
+	kBool
+	kDuration
+)
+
+// String creates a typed Field with a string value, see LogFields
+func String(key, value string) Field {
+	return Field{Key: key, kind: kString, str: value}
+}
+
+// Int creates a typed Field with an int value, see LogFields
+func Int(key string, value int) Field {
+	return Field{Key: key, kind: kInt64, num: int64(value)}
+}
+
+// Int64 creates a typed Field with an int64 value, see LogFields
+func Int64(key string, value int64) Field {
+	return Field{Key: key, kind: kInt64, num: value}
+}
+
+// Uint64 creates a typed Field with a uint64 value, see LogFields
+func Uint64(key string, value uint64) Field {
+	return Field{Key: key, kind: kUint64, num: int64(value)}
+}
+
+// Float64 creates a typed Field with a float64 value, see LogFields
+func Float64(key string, value float64) Field {
+	return Field{Key: key, kind: kFloat64, num: int64(math.Float64bits(value))}
+}
+
+// Bool creates a typed Field with a bool value, see LogFields
+func Bool(key string, value bool) Field {
+	f := Field{Key: key, kind: kBool}
+	if value {
+		f.num = 1
+	}
+	return f
+}
+
+// Dur creates a typed Field with a time.Duration value, see LogFields
+func Dur(key string, value time.Duration) Field {
+	return Field{Key: key, kind: kDuration, num: int64(value)}
+}
+
+// Err creates a Field with key "error" and value err, see LogFields
+func Err(err error) Field {
+	return Field{Key: "error", Value: err}
+}
+
+// Interface returns value of f, for typed fields too
+func (f Field) Interface() interface{} {
+	switch f.kind {
+	case kString:
+		return f.str
+	case kInt64:
+		return f.num
+	case kUint64:
+		return uint64(f.num)
+	case kFloat64:
+		return math.Float64frombits(uint64(f.num))
+	case kBool:
+		return f.num != 0
+	case kDuration:
+		return time.Duration(f.num)
+	}
+	return f.Value
+}
+
+// LogFields is like Log with a message and fields, but avoids interface boxing of fields
+// and their values (if created with typed constructors like String & Int64), so it
+// allocates less:
+//
+//	lg.LogFields(yell.Sinfo, "served", yell.String("path", p), yell.Dur("took", d))
+//
+// Observers see values of typed fields in Field.Value.
+func (lg *Logger) LogFields(level Severity, msg string, fields ...Field) error {
+	return lg.log(1, 0, nil, level, []interface{}{msg}, fields)
+}
+
+// boxFields returns fields with typed values moved to Value, fields are copied if
+// necessary
+func boxFields(fields []Field) []Field {
+	copied := false
+	for i, f := range fields {
+		if f.kind == kAny {
+			continue
+		}
+		if !copied {
+			fields = append([]Field(nil), fields...)
+			copied = true
+		}
+		fields[i] = Field{Key: f.Key, Value: f.Interface()}
+	}
+	return fields
+}
+
+// appendFieldValue appends value of f like appendValue
+func appendFieldValue(buf []byte, f Field) []byte {
+	switch f.kind {
+	case kString:
+		return appendText(buf, f.str)
+	case kInt64:
+		return strconv.AppendInt(buf, f.num, 10)
+	case kUint64:
+		return strconv.AppendUint(buf, uint64(f.num), 10)
+	case kFloat64:
+		return appendText(buf, strconv.FormatFloat(math.Float64frombits(uint64(f.num)), 'g', -1, 64))
+	case kBool:
+		return strconv.AppendBool(buf, f.num != 0)
+	case kDuration:
+		return append(buf, time.Duration(f.num).String()...)
+	}
+	return appendValue(buf, f.Value)
+}
+
+// appendJSONField appends value of f like appendJSONValue, durations are nanoseconds
+func appendJSONField(buf []byte, f Field) ([]byte, error) {
+	switch f.kind {
+	case kString:
+		return appendJSONString(buf, f.str), nil
+	case kInt64, kDuration:
+		return strconv.AppendInt(buf, f.num, 10), nil
+	case kUint64:
+		return strconv.AppendUint(buf, uint64(f.num), 10), nil
+	case kFloat64:
+		return appendJSONFloat(buf, math.Float64frombits(uint64(f.num))), nil
+	case kBool:
+		return strconv.AppendBool(buf, f.num != 0), nil
+	}
+	return appendJSONValue(buf, f.Value)
+}
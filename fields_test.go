@@ -0,0 +1,72 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"errors"
+	"io/ioutil"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTypedFields(t *testing.T) {
+	fields := []Field{String("s", "a b"), Int("i", -3), Int64("i64", 1<<40),
+		Uint64("u", math.MaxUint64), Float64("f", 1.5), Bool("b", true), Dur("d", time.Second),
+		Err(errors.New("e")), Float64("nan", math.NaN())}
+	want := [...]string{
+		` s="a b" i=-3 i64=1099511627776 u=18446744073709551615 f=1.5 b=true d=1s error=e nan=NaN` + "\n",
+		`"s":"a b","i":-3,"i64":1099511627776,"u":18446744073709551615,"f":1.5,"b":true,` +
+			`"d":1000000000,"error":"e","nan":"NaN"}` + "\n",
+		` s="a b" i=-3 i64=1099511627776 u=18446744073709551615 f=1.5 b=true d=1s error=e nan=NaN` + "\n",
+	}
+
+	var (
+		sb   strings.Builder
+		recs []Record
+	)
+	lg := New(": tf:", &sb, Sinfo)
+	lg.AddObserver(func(r Record) { recs = append(recs, r) })
+	for i, enc := range [...]Encoder{TextEncoder{}, JSONEncoder{}, LogfmtEncoder{}} {
+		sb.Reset()
+		lg.SetEncoder(enc)
+		if err := lg.LogFields(Sinfo, "msg", fields...); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasSuffix(sb.String(), want[i]) {
+			t.Fatalf("%T: unexpected record: %s", enc, sb.String())
+		}
+	}
+
+	// observers see boxed values, fields are not modified
+	r := recs[0]
+	if r.Msg != "msg" || r.Fields[0] != Any("s", "a b") ||
+		r.Fields[3] != Any("u", uint64(math.MaxUint64)) || r.Fields[6] != Any("d", time.Second) ||
+		fields[0].Value != nil || fields[5].Interface() != true {
+		t.Fatal("unexpected record:", r)
+	}
+
+	lg.SetSortFields(true)
+	lg.Log(Sinfo, "m", Any("z", 1), Int("a", 2))
+	if r = recs[len(recs)-1]; r.Fields[0] != Any("a", int64(2)) {
+		t.Fatal("unexpected record:", r)
+	}
+}
+
+func TestLogFieldsAllocs(t *testing.T) {
+	lg := New(": tf:", ioutil.Discard, Sinfo)
+	boxed := testing.AllocsPerRun(100, func() {
+		lg.Log(Sinfo, "msg", Any("path", "/x"), Any("took", time.Millisecond), Any("n", 1000))
+	})
+	typed := testing.AllocsPerRun(100, func() {
+		lg.LogFields(Sinfo, "msg", String("path", "/x"), Dur("took", time.Millisecond), Int("n", 1000))
+	})
+	if typed >= boxed {
+		t.Fatal("typed fields must allocate less:", typed, boxed)
+	}
+}
@@ -0,0 +1,38 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+// SetFlightRecorder makes Logger keep records below trigger severity in rg instead of
+// writing them. When a record with trigger or higher severity is logged, buffered records
+// are written retroactively (oldest first, each with a separate Write) before it, which
+// gives full context around failures without constant verbosity:
+//
+//	lg.SetLevel(yell.Sdebug)
+//	lg.SetFlightRecorder(yell.NewRing(500), yell.Serror)
+//
+// Only the last records fitting in rg are kept, and records buffered at Close are never
+// written. Minimum severity (see SetLevel) still applies. Records of LogTo bypass it, so
+// they are neither buffered nor trigger writing buffered records. nil rg or trigger below
+// info disables it, which is the default. It should be called before Logger is used.
+func (lg *Logger) SetFlightRecorder(rg *Ring, trigger Severity) {
+	if rg == nil || trigger <= Sdebug || trigger > Sfatal {
+		lg.flight, lg.trigger = nil, 0
+		return
+	}
+	lg.flight, lg.trigger = rg, trigger
+}
+
+// replay writes records buffered by flight recorder to out, returns first error. Their
+// levels are not kept, so they are written with Write.
+func (lg *Logger) replay(out *output) error {
+	for _, p := range lg.flight.drain() {
+		if err := out.write(Snolog, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
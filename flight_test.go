@@ -0,0 +1,87 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// writeRecorder records each Write separately
+type writeRecorder struct {
+	writes []string
+	fail   bool
+}
+
+func (w *writeRecorder) Write(p []byte) (int, error) {
+	if w.fail {
+		return 0, errors.New("write failed")
+	}
+	w.writes = append(w.writes, string(p))
+	return len(p), nil
+}
+
+func TestFlightRecorder(t *testing.T) {
+	var w writeRecorder
+	lg := New(": fr:", &w, Sdebug)
+	lg.SetFlightRecorder(NewRing(2), Serror)
+
+	lg.Log(Sdebug, "d1")
+	lg.Log(Sinfo, "i1")
+	lg.Log(Swarn, "w1")
+	if len(w.writes) != 0 || lg.Count(Sinfo) != 1 {
+		t.Fatal("records written before trigger", w.writes)
+	}
+
+	lg.Log(Serror, "e1")
+	if len(w.writes) != 3 || !strings.HasSuffix(w.writes[0], "i1\n") ||
+		!strings.HasSuffix(w.writes[1], "w1\n") || !strings.HasSuffix(w.writes[2], "e1\n") {
+		t.Fatal("bad replay", w.writes)
+	}
+
+	// buffer is emptied by replay
+	lg.Log(Sinfo, "i2")
+	lg.Log(Sfatal, "f1")
+	if len(w.writes) != 5 || !strings.HasSuffix(w.writes[3], "i2\n") {
+		t.Fatal("bad second replay", w.writes)
+	}
+
+	w.fail = true
+	lg.Log(Sinfo, "i3")
+	if err := lg.Log(Serror, "e2"); err == nil {
+		t.Fatal("expected replay error")
+	}
+
+	// disabled
+	w.fail = false
+	lg.SetFlightRecorder(NewRing(2), Sdebug)
+	lg.Log(Sdebug, "d2")
+	if lg.flight != nil || len(w.writes) != 6 {
+		t.Fatal("flight recorder not disabled")
+	}
+}
+
+func TestFlightRecorderLogTo(t *testing.T) {
+	var w, audit writeRecorder
+	lg := New(": fr:", &w, Sdebug)
+	lg.SetFlightRecorder(NewRing(4), Serror)
+
+	lg.Log(Sdebug, "secret debug")
+	lg.LogTo(&audit, Sinfo, "audit info")
+	lg.LogTo(&audit, Serror, "audit error")
+	if len(w.writes) != 0 || len(audit.writes) != 2 ||
+		!strings.HasSuffix(audit.writes[0], "audit info\n") {
+		t.Fatal("LogTo must bypass flight recorder:", w.writes, audit.writes)
+	}
+
+	lg.Log(Serror, "e1")
+	if len(w.writes) != 2 || !strings.HasSuffix(w.writes[0], "secret debug\n") ||
+		len(audit.writes) != 2 {
+		t.Fatal("bad replay", w.writes, audit.writes)
+	}
+}
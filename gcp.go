@@ -0,0 +1,93 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cloud Logging severities of yell severities
+var gcpSeverity = [...]string{"DEBUG", "INFO", "WARNING", "ERROR", "CRITICAL"}
+
+// GCPEncoder writes JSON records in Google Cloud Logging structured format, so workloads
+// on GKE, Cloud Run etc. get correct severities & source locations from the logging agent:
+//
+//	{"severity":"WARNING","time":"2021-03-28T21:48:53.591948Z","logger":"mypkg",
+//	 "logging.googleapis.com/sourceLocation":{"file":"myApp.go","line":"15"},
+//	 "message":"some warning","key":"value"}
+//
+// Error details follow message on separate lines, so Error Reporting can pick up stack
+// traces. Record identifier (if enabled) is the insertId. Fields named "trace" & "span"
+// go to trace & spanId keys, traces are prefixed with projects/ProjectID/traces/ if
+// ProjectID is set.
+type GCPEncoder struct {
+	ProjectID string
+}
+
+// Encode appends Cloud Logging record to buf
+func (e GCPEncoder) Encode(buf []byte, r *Record) ([]byte, error) {
+	buf = append(buf, `{"severity":"`...)
+	buf = append(buf, gcpSeverity[r.Level]...)
+	buf = append(buf, `","time":"`...)
+	buf = r.tcache.append(buf, r.Time, time.RFC3339Nano)
+	buf = append(buf, `","logger":`...)
+	buf = appendJSONString(buf, r.Name)
+
+	if r.ID != "" {
+		buf = append(buf, `,"logging.googleapis.com/insertId":"`...)
+		buf = append(buf, r.ID...)
+		buf = append(buf, '"')
+	}
+	if r.Seq != 0 {
+		buf = append(buf, `,"seq":`...)
+		buf = strconv.AppendUint(buf, r.Seq, 10)
+	}
+
+	if r.File != "" {
+		buf = append(buf, `,"logging.googleapis.com/sourceLocation":{"file":`...)
+		buf = appendJSONString(buf, r.File)
+		buf = append(buf, `,"line":"`...)
+		buf = strconv.AppendInt(buf, int64(r.Line), 10)
+		buf = append(buf, `"}`...)
+	}
+
+	msg := r.Text()
+	if r.Detail != "" {
+		msg += "\n" + r.Detail
+	}
+	buf = append(buf, `,"message":`...)
+	buf = appendJSONString(buf, msg)
+
+	var err error
+	for _, f := range r.Fields {
+		switch f.Key {
+		case "trace":
+			buf = append(buf, `,"logging.googleapis.com/trace":`...)
+			trace, ok := f.Interface().(string)
+			if !ok {
+				trace = sprintln([]interface{}{f.Value})
+			}
+			if e.ProjectID != "" && !strings.HasPrefix(trace, "projects/") {
+				trace = "projects/" + e.ProjectID + "/traces/" + trace
+			}
+			buf = appendJSONString(buf, trace)
+			continue
+		case "span":
+			buf = append(buf, `,"logging.googleapis.com/spanId":`...)
+		default:
+			buf = append(buf, ',')
+			buf = appendJSONString(buf, f.Key)
+			buf = append(buf, ':')
+		}
+		if buf, err = appendJSONField(buf, f); err != nil {
+			return buf, err
+		}
+	}
+	return append(buf, "}\n"...), nil
+}
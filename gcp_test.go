@@ -0,0 +1,40 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestGCPEncoder(t *testing.T) {
+	r := Record{Time: time.Date(2021, 3, 28, 21, 48, 53, 591948000, time.UTC),
+		Level: Swarn, Name: "mypkg", File: "a.go", Line: 15, Msg: "slow", ID: "01F",
+		Detail: "stack", Fields: []Field{Any("trace", "abc"), Any("span", "12"), Any("n", 3)}}
+
+	b, err := GCPEncoder{ProjectID: "proj"}.Encode(nil, &r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"severity":"WARNING","time":"2021-03-28T21:48:53.591948Z","logger":"mypkg",` +
+		`"logging.googleapis.com/insertId":"01F","logging.googleapis.com/sourceLocation":` +
+		`{"file":"a.go","line":"15"},"message":"slow\nstack",` +
+		`"logging.googleapis.com/trace":"projects/proj/traces/abc",` +
+		`"logging.googleapis.com/spanId":"12","n":3}` + "\n"
+	if string(b) != want {
+		t.Fatalf("unexpected record:\n%s%s", b, want)
+	}
+
+	r = Record{Level: Sfatal, Fields: []Field{Any("trace", "projects/p/traces/x")}}
+	b, _ = GCPEncoder{ProjectID: "proj"}.Encode(nil, &r)
+	var m map[string]interface{}
+	if err = json.Unmarshal(b, &m); err != nil || m["severity"] != "CRITICAL" ||
+		m["logging.googleapis.com/trace"] != "projects/p/traces/x" {
+		t.Fatal("unexpected record:", string(b), err)
+	}
+}
@@ -1,3 +1,3 @@
-module github.com/jfcg/yell
+module github.com/jfcg/yell/v2
 
 go 1.15
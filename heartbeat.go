@@ -0,0 +1,44 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"context"
+	"time"
+)
+
+// Heartbeat logs message list with info severity every interval until ctx is done, with
+// the number of beats & time since Heartbeat was called, for liveness of batch jobs:
+//
+//	lg.Heartbeat(ctx, time.Minute, "still alive")
+//	// mypkg:info: still alive beat=3 uptime=3m0.0012s
+//
+// Scope fields of ctx (see WithScope) are attached to the records. Heartbeat records have
+// no request location. Panics if interval is not positive.
+func (lg *Logger) Heartbeat(ctx context.Context, interval time.Duration,
+	msg ...interface{}) {
+
+	if interval <= 0 {
+		panic("yell: non-positive heartbeat interval")
+	}
+	msg = append([]interface{}{ctx}, msg...) // copy with scope fields
+	start := time.Now()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for beat := 1; ; beat++ {
+			select {
+			case now := <-ticker.C:
+				lg.log(noCaller, 0, nil, Sinfo, append(msg[:len(msg):len(msg)],
+					Int("beat", beat), Dur("uptime", now.Sub(start))), nil)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
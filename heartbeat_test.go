@@ -0,0 +1,53 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHeartbeat(t *testing.T) {
+	var fb flushBuffer
+	lg := New(": hb:", &fb, Sinfo)
+	lg.SetTimeMode(Tnone)
+
+	ctx, cancel := context.WithCancel(WithScope(context.Background(), Any("job", "etl")))
+	lg.Heartbeat(ctx, 20*time.Millisecond, "still", "alive")
+
+	var s string
+	for i := 0; i < 100 && strings.Count(s, "\n") < 2; i++ {
+		time.Sleep(10 * time.Millisecond)
+		s, _ = fb.state()
+	}
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+	s, _ = fb.state()
+
+	lines := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	if len(lines) < 2 || len(lines) > 4 {
+		t.Fatal("unexpected beats:", s)
+	}
+	for i, l := range lines {
+		if !strings.HasPrefix(l, "hb:info: still alive job=etl beat="+string(rune('1'+i))+" uptime=") {
+			t.Fatal("unexpected beat:", l)
+		}
+	}
+	time.Sleep(50 * time.Millisecond)
+	if s2, _ := fb.state(); s2 != s {
+		t.Fatal("heartbeat must stop when context is done")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("non-positive interval must panic")
+		}
+	}()
+	lg.Heartbeat(ctx, -1)
+}
@@ -0,0 +1,46 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// HexDump is a bounded hex dump of a binary payload, see Hex
+type HexDump struct {
+	data  []byte // first max bytes
+	total int    // payload length
+}
+
+// Hex returns a hex dump of at most max (all if max <= 0) leading bytes of b for message
+// lists & fields, rendered like hex.Dump only when logged:
+//
+//	lg.Log(yell.Sdebug, "received", yell.Hex(frame, 64))
+//
+// Truncated dumps end with a line like "... 960 more bytes". The bytes are copied, so b
+// can be reused. Dumps are multi-line, see SetIndent.
+func Hex(b []byte, max int) HexDump {
+	n := len(b)
+	if max > 0 && n > max {
+		n = max
+	}
+	return HexDump{append([]byte(nil), b[:n]...), len(b)}
+}
+
+// String returns hex dump without trailing newline
+func (h HexDump) String() string {
+	s := strings.TrimSuffix(hex.Dump(h.data), "\n")
+	if more := h.total - len(h.data); more > 0 {
+		if s != "" {
+			s += "\n"
+		}
+		s += "... " + strconv.Itoa(more) + " more bytes"
+	}
+	return s
+}
@@ -0,0 +1,36 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+func TestHex(t *testing.T) {
+	b := []byte("GET / HTTP/1.1\r\nHost: x\r\n")
+	h := Hex(b, 18)
+	b[0] = 'P' // copied
+
+	want := "00000000  47 45 54 20 2f 20 48 54  54 50 2f 31 2e 31 0d 0a  |GET / HTTP/1.1..|\n" +
+		"00000010  48 6f                                             |Ho|\n... 7 more bytes"
+	if h.String() != want {
+		t.Fatalf("bad dump:\n%s\n%s", h, want)
+	}
+	if Hex(nil, 4).String() != "" || Hex(b, 0).total != len(b) || Hex(b, 1).String() !=
+		"00000000  50                                                |P|\n... 24 more bytes" {
+		t.Fatal("bad dumps")
+	}
+
+	var recs []Record
+	lg := New(": hex:", ioutil.Discard, Sinfo)
+	lg.AddObserver(func(r Record) { recs = append(recs, r) })
+	lg.Log(Sinfo, "frame", Hex([]byte{1}, 8))
+	if len(recs) != 1 || recs[0].Msg != "frame 00000000  01                                                |.|" {
+		t.Fatal("unexpected records:", recs)
+	}
+}
@@ -0,0 +1,54 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"os"
+	"runtime"
+	"strings"
+)
+
+// files searched for container IDs
+var containerFiles = []string{"/proc/self/cgroup", "/proc/self/mountinfo"}
+
+// Host returns an Enricher of host & runtime metadata as host.name, os.type, host.arch,
+// process.runtime.version & container.id fields (OpenTelemetry names), useful for
+// heterogeneous fleets. Metadata is computed once. Container ID is detected from cgroups
+// & mounts of the process (on Linux), missing hostname & container ID are omitted.
+func Host() Enricher {
+	var fields []Field
+	if name, err := os.Hostname(); err == nil && name != "" {
+		fields = append(fields, Any("host.name", name))
+	}
+	fields = append(fields, Any("os.type", runtime.GOOS), Any("host.arch", runtime.GOARCH),
+		Any("process.runtime.version", runtime.Version()))
+	if id := containerID(); id != "" {
+		fields = append(fields, Any("container.id", id))
+	}
+	return StaticEnricher(fields...)
+}
+
+// containerID returns the first container ID (64 hex digits) found in path components of
+// containerFiles, like /docker/<id> or /var/lib/docker/containers/<id>/hostname
+func containerID() string {
+	for _, file := range containerFiles {
+		content := readTrimmed(file)
+		for _, word := range strings.FieldsFunc(content, func(r rune) bool {
+			return r == '/' || r == ' ' || r == '\n' || r == ':'
+		}) {
+			// like docker-<id>.scope or cri-containerd-<id>.scope
+			word = strings.TrimSuffix(word, ".scope")
+			if i := strings.LastIndexByte(word, '-'); i >= 0 {
+				word = word[i+1:]
+			}
+			if len(word) == 64 && strings.Trim(word, hexDigits) == "" {
+				return word
+			}
+		}
+	}
+	return ""
+}
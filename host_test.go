@@ -0,0 +1,44 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestHost(t *testing.T) {
+	dir, err := ioutil.TempDir("", "host")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(files []string) { containerFiles = files }(containerFiles)
+
+	id := strings.Repeat("0123456789abcdef", 4)
+	cgroup := filepath.Join(dir, "cgroup")
+	containerFiles = []string{filepath.Join(dir, "missing"), cgroup}
+
+	for _, content := range []string{"12:pids:/docker/" + id + "\n",
+		"0::/system.slice/docker-" + id + ".scope\n",
+		"1 2 0:3 /var/lib/docker/containers/" + id + "/hostname /etc/hostname rw\n"} {
+		ioutil.WriteFile(cgroup, []byte(content), 0644)
+		if containerID() != id {
+			t.Fatal("container ID not found in", content)
+		}
+	}
+
+	ioutil.WriteFile(cgroup, []byte("0::/user.slice\n"), 0644)
+	f := Host()()
+	if containerID() != "" || len(f) < 3 || f[len(f)-1] != Any("process.runtime.version", runtime.Version()) {
+		t.Fatal("bad metadata", f)
+	}
+}
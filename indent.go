@@ -0,0 +1,39 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+// SetIndent enables or disables indentation of multi-line messages (like pretty-printed
+// structs) by Logger's TextEncoder. With indentation, continuation lines are indented with
+// a tab like error details, so they stay grouped under the record header:
+//
+//	2021-03-28 21:48:53.591948: mypkg:info: myApp.go:15: config {
+//		"port": 80
+//	}
+//
+// and yellparse treats them as continuation lines of the record. It has no effect if
+// escaping is enabled (see SetEscape) or on other encoders. Indentation is disabled by
+// default.
+func (lg *Logger) SetIndent(on bool) {
+	lg.indent = on
+
+	out := lg.output()
+	if te, ok := out.enc.(TextEncoder); ok {
+		te.Indent = on
+		lg.setOutput(out.writer, te)
+	}
+}
+
+// appendIndented appends s to buf with a tab after each newline
+func appendIndented(buf []byte, s string) []byte {
+	for i := 0; i < len(s); i++ {
+		buf = append(buf, s[i])
+		if s[i] == '\n' {
+			buf = append(buf, '\t')
+		}
+	}
+	return buf
+}
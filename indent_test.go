@@ -0,0 +1,29 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSetIndent(t *testing.T) {
+	var sb strings.Builder
+	lg := New(": ind:", &sb, Sinfo)
+	lg.SetTimeMode(Tnone)
+	lg.SetIndent(true)
+	lg.SetColor(Cnever, false) // keeps indentation
+
+	lg.Log(Sinfo, Caller(-1), "config {\n\"port\": 80\n}", Any("k", 1))
+	lg.SetIndent(false)
+	lg.Log(Sinfo, Caller(-1), "a\nb")
+
+	if got := sb.String(); !strings.Contains(got, ": config {\n\t\"port\": 80\n\t} k=1\n") ||
+		!strings.HasSuffix(got, ": a\nb\n") {
+		t.Fatal("bad indentation", got)
+	}
+}
@@ -0,0 +1,209 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Format is the wire format of log records for built-in encoders
+type Format uint32
+
+// record formats
+const (
+	Ftext Format = iota // TextEncoder, default
+	Fjson               // JSONEncoder
+)
+
+// SetFormat sets Logger's Encoder to a built-in one. Invalid formats are ignored.
+// Ftext respects color settings, see SetColor.
+func (lg *Logger) SetFormat(format Format) {
+	writer := lg.output().writer
+	switch format {
+	case Ftext:
+		lg.setOutput(writer, lg.colorize(writer, TextEncoder{}))
+	case Fjson:
+		lg.setOutput(writer, JSONEncoder{})
+	}
+}
+
+// NewAuto is like New but chooses record format according to writer: colored text
+// (see SetColor) for terminals, JSON for pipes, files etc. Panics if arguments are invalid.
+func NewAuto(name string, writer io.Writer, minLevel Severity) Logger {
+	lg := New(name, writer, minLevel)
+	if isTerminal(writer) {
+		lg.SetColor(Cauto, true)
+	} else {
+		lg.setOutput(writer, JSONEncoder{})
+	}
+	return lg
+}
+
+// levelName returns severity name without trailing colon
+func levelName(level Severity) string {
+	return strings.TrimSuffix(Sname[level], ":")
+}
+
+// JSONEncoder writes one JSON object per record, with RFC 3339 time (see SetTimeMode),
+// sequence number & identifier (if enabled) and fields as top-level keys:
+//
+//	{"time":"2021-03-28T21:48:53.591948+03:00","name":"mypkg","level":"info",
+//	 "caller":"myApp.go:15","msg":"some info: 1 more","key":"value"}
+//
+// Level is severity name without trailing colon. Error members of message list are
+// encoded as "error" object with message, type & causes (see errors.Unwrap), or "errors"
+// array of them if there are multiple errors. Field values are encoded with encoding/json
+// unless they are strings, numbers, booleans, errors or nil.
+type JSONEncoder struct{}
+
+// Encode appends JSON record to buf
+func (JSONEncoder) Encode(buf []byte, r *Record) ([]byte, error) {
+	buf = append(buf, '{')
+	if r.tmode != Tnone {
+		buf = append(buf, `"time":`...)
+		if r.epochTime() {
+			buf = r.AppendTime(buf, "")
+		} else {
+			buf = append(buf, '"')
+			buf = r.AppendTime(buf, time.RFC3339Nano)
+			buf = append(buf, '"')
+		}
+		buf = append(buf, ',')
+	}
+	buf = append(buf, `"name":`...)
+	buf = appendJSONString(buf, r.Name)
+	buf = append(buf, `,"level":`...)
+	buf = appendJSONString(buf, levelName(r.Level))
+
+	if r.Seq != 0 {
+		buf = append(buf, `,"seq":`...)
+		buf = strconv.AppendUint(buf, r.Seq, 10)
+	}
+	if r.ID != "" {
+		buf = append(buf, `,"id":"`...)
+		buf = append(buf, r.ID...)
+		buf = append(buf, '"')
+	}
+
+	if r.File != "" {
+		buf = append(buf, `,"caller":`...)
+		buf = appendJSONString(buf, r.File)
+		buf = buf[:len(buf)-1] // drop quote
+		buf = append(buf, ':')
+		buf = strconv.AppendInt(buf, int64(r.Line), 10)
+		buf = append(buf, '"')
+	}
+
+	buf = append(buf, `,"msg":`...)
+	buf = appendJSONString(buf, r.Msg)
+	buf = appendJSONErrors(buf, r.Errs)
+
+	var err error
+	for _, f := range r.Fields {
+		buf = append(buf, ',')
+		buf = appendJSONString(buf, f.Key)
+		buf = append(buf, ':')
+		if buf, err = appendJSONField(buf, f); err != nil {
+			return buf, err
+		}
+	}
+
+	if r.Detail != "" {
+		buf = append(buf, `,"detail":`...)
+		buf = appendJSONString(buf, r.Detail)
+	}
+	return append(buf, "}\n"...), nil
+}
+
+// appendJSONValue appends v as a JSON value
+func appendJSONValue(buf []byte, v interface{}) ([]byte, error) {
+	switch x := v.(type) {
+	case nil:
+		return append(buf, "null"...), nil
+	case string:
+		return appendJSONString(buf, x), nil
+	case bool:
+		return strconv.AppendBool(buf, x), nil
+	case int:
+		return strconv.AppendInt(buf, int64(x), 10), nil
+	case int64:
+		return strconv.AppendInt(buf, x, 10), nil
+	case int32:
+		return strconv.AppendInt(buf, int64(x), 10), nil
+	case uint:
+		return strconv.AppendUint(buf, uint64(x), 10), nil
+	case uint64:
+		return strconv.AppendUint(buf, x, 10), nil
+	case uint32:
+		return strconv.AppendUint(buf, uint64(x), 10), nil
+	case float64:
+		return appendJSONFloat(buf, x), nil
+	case error:
+		return appendJSONString(buf, x.Error()), nil
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return buf, err
+	}
+	return append(buf, b...), nil
+}
+
+// appendJSONFloat appends f as a JSON number, or string if it is infinite or NaN
+func appendJSONFloat(buf []byte, f float64) []byte {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return appendJSONString(buf, strconv.FormatFloat(f, 'g', -1, 64))
+	}
+	return strconv.AppendFloat(buf, f, 'g', -1, 64)
+}
+
+// appendJSONString appends s to buf as a quoted JSON string
+func appendJSONString(buf []byte, s string) []byte {
+	buf = append(buf, '"')
+	for i := 0; i < len(s); {
+		b := s[i]
+		if b >= utf8.RuneSelf {
+			r, n := utf8.DecodeRuneInString(s[i:])
+			if r == utf8.RuneError && n == 1 {
+				buf = append(buf, `\ufffd`...)
+			} else if r == '\u2028' || r == '\u2029' {
+				buf = append(buf, `\u202`...)
+				buf = append(buf, hexDigits[r&15])
+			} else {
+				buf = append(buf, s[i:i+n]...)
+			}
+			i += n
+			continue
+		}
+
+		switch b {
+		case '"', '\\':
+			buf = append(buf, '\\', b)
+		case '\n':
+			buf = append(buf, `\n`...)
+		case '\r':
+			buf = append(buf, `\r`...)
+		case '\t':
+			buf = append(buf, `\t`...)
+		default:
+			if b < ' ' || b == 0x7f {
+				buf = append(buf, `\u00`...)
+				buf = append(buf, hexDigits[b>>4], hexDigits[b&15])
+			} else {
+				buf = append(buf, b)
+			}
+		}
+		i++
+	}
+	return append(buf, '"')
+}
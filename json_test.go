@@ -0,0 +1,45 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	var sb strings.Builder
+	lg := NewAuto(": js:", &sb, Sinfo) // not a terminal
+	if _, ok := lg.output().enc.(JSONEncoder); !ok {
+		t.Fatal("must choose JSON for non-terminals")
+	}
+
+	msg := "quote\" back\\ nl\n ctl\x01 bad\xff ls\u2028 ş"
+	if err := lg.Log(Swarn, msg, 2); err != nil {
+		t.Fatal(err)
+	}
+	out := sb.String()
+	if strings.Count(out, "\n") != 1 || !strings.HasSuffix(out, "}\n") {
+		t.Fatal("must be a single line:", out)
+	}
+
+	var rec map[string]string
+	if err := json.Unmarshal([]byte(out), &rec); err != nil {
+		t.Fatal(err)
+	}
+	if rec["name"] != "js" || rec["level"] != "warn" || rec["time"] == "" ||
+		!strings.HasPrefix(rec["caller"], "testing.go:") ||
+		rec["msg"] != strings.Replace(msg, "\xff", "�", 1)+" 2" {
+		t.Fatal("unexpected record:", out)
+	}
+
+	lg.SetFormat(Fjson + 1) // ignored
+	if _, ok := lg.output().enc.(JSONEncoder); !ok {
+		t.Fatal("must ignore invalid format")
+	}
+}
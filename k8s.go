@@ -0,0 +1,72 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PodInfoDir is the directory of Kubernetes downward API files read by Kubernetes
+var PodInfoDir = "/etc/podinfo"
+
+// namespace file of Kubernetes service accounts
+var saNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// Kubernetes returns an Enricher of pod metadata as k8s.pod.name, k8s.namespace.name &
+// k8s.node.name fields (OpenTelemetry names), so aggregated logs of a cluster are
+// attributable without relabeling by collectors. Metadata is read once, each from the
+// first non-empty of:
+//
+//	pod name:  POD_NAME env, PodInfoDir/name, HOSTNAME env (in a cluster)
+//	namespace: POD_NAMESPACE env, PodInfoDir/namespace, service account namespace
+//	node name: NODE_NAME env, PodInfoDir/nodename
+//
+// Environment variables & files are typically set via downward API in pod spec:
+//
+//	env:
+//	- name: POD_NAME
+//	  valueFrom: {fieldRef: {fieldPath: metadata.name}}
+//
+// Missing metadata are omitted.
+func Kubernetes() Enricher {
+	var fields []Field
+	add := func(key string, sources ...string) {
+		for _, s := range sources {
+			if s != "" {
+				fields = append(fields, Any(key, s))
+				return
+			}
+		}
+	}
+
+	var host string
+	if os.Getenv("KUBERNETES_SERVICE_HOST") != "" {
+		host = os.Getenv("HOSTNAME") // pod name by default
+	}
+	add("k8s.pod.name", os.Getenv("POD_NAME"), podInfo("name"), host)
+	add("k8s.namespace.name", os.Getenv("POD_NAMESPACE"), podInfo("namespace"),
+		readTrimmed(saNamespaceFile))
+	add("k8s.node.name", os.Getenv("NODE_NAME"), podInfo("nodename"))
+	return StaticEnricher(fields...)
+}
+
+// podInfo returns content of downward API file name
+func podInfo(name string) string {
+	return readTrimmed(filepath.Join(PodInfoDir, name))
+}
+
+// readTrimmed returns content of file without surrounding space, empty if unreadable
+func readTrimmed(file string) string {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(b))
+}
@@ -0,0 +1,41 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestKubernetes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "podinfo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(d, ns string) { PodInfoDir, saNamespaceFile = d, ns }(PodInfoDir, saNamespaceFile)
+	PodInfoDir, saNamespaceFile = dir, filepath.Join(dir, "sa-namespace")
+
+	for _, k := range []string{"POD_NAME", "POD_NAMESPACE", "NODE_NAME", "KUBERNETES_SERVICE_HOST"} {
+		defer os.Setenv(k, os.Getenv(k))
+		os.Unsetenv(k)
+	}
+	if f := Kubernetes()(); len(f) != 0 {
+		t.Fatal("unexpected metadata", f)
+	}
+
+	ioutil.WriteFile(filepath.Join(dir, "nodename"), []byte("node-1\n"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "sa-namespace"), []byte("prod"), 0644)
+	os.Setenv("POD_NAME", "web-5d9")
+	f := Kubernetes()()
+	if len(f) != 3 || f[0] != Any("k8s.pod.name", "web-5d9") ||
+		f[1] != Any("k8s.namespace.name", "prod") || f[2] != Any("k8s.node.name", "node-1") {
+		t.Fatal("bad metadata", f)
+	}
+}
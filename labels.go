@@ -0,0 +1,91 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"strings"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// LabelStyle selects severity labels of TextEncoder
+type LabelStyle uint32
+
+// label styles
+const (
+	Lname   LabelStyle = iota // Sname like "warn:", default
+	Lletter                   // single letters like "W:"
+	Lpadded                   // Sname padded with spaces to the same width like "warn: "
+	Lemoji                    // emoji like "🔶:"
+)
+
+// Sletter & Semoji are the lists of single letter & emoji labels (in increasing severity)
+var (
+	Sletter = [...]string{"D:", "I:", "W:", "E:", "F:"}
+	Semoji  = [...]string{"🐛:", "💬:", "🔶:", "🔴:", "💀:"}
+)
+
+// SetLabels sets severity label style of Logger's TextEncoder, so aligned or compact
+// columns make console logs easier to scan:
+//
+//	2021-03-28 21:48:53.591948: mypkg:W: myApp.go:15: some warning
+//
+// yellparse (and tools using it, like yelltest.Recorder) only recognize Lname & Lpadded
+// records. SetLabels has no effect on other encoders.
+func (lg *Logger) SetLabels(style LabelStyle) {
+	if style > Lemoji {
+		style = Lname
+	}
+	lg.labels = style
+
+	out := lg.output()
+	if te, ok := out.enc.(TextEncoder); ok {
+		te.Labels = style
+		lg.setOutput(out.writer, te)
+	}
+}
+
+// label returns severity label of level in style
+func label(style LabelStyle, level Severity) string {
+	switch style {
+	case Lletter:
+		return Sletter[level]
+	case Lpadded:
+		return paddedLabel(level)
+	case Lemoji:
+		return Semoji[level]
+	}
+	return Sname[level]
+}
+
+// padded labels computed from names
+type padLabels struct {
+	names, labels [len(Sname)]string
+}
+
+// padCache holds *padLabels of last used Sname
+var padCache atomic.Value
+
+// paddedLabel returns Sname[level] padded with spaces to the width (in runes) of the
+// longest name. Padded labels are recomputed only when Sname changes.
+func paddedLabel(level Severity) string {
+	pl, _ := padCache.Load().(*padLabels)
+	if pl == nil || pl.names != Sname {
+		pl = &padLabels{names: Sname}
+		width := 0
+		for _, s := range pl.names {
+			if n := utf8.RuneCountInString(s); width < n {
+				width = n
+			}
+		}
+		for i, s := range pl.names {
+			pl.labels[i] = s + strings.Repeat(" ", width-utf8.RuneCountInString(s))
+		}
+		padCache.Store(pl)
+	}
+	return pl.labels[level]
+}
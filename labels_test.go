@@ -0,0 +1,50 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSetLabels(t *testing.T) {
+	var sb strings.Builder
+	lg := New(": lab:", &sb, Sinfo)
+	lg.SetTimeMode(Tnone)
+
+	for _, style := range []LabelStyle{Lletter, Lpadded, Lemoji, 9} {
+		lg.SetLabels(style)
+		lg.Log(Swarn, Caller(-1), "w")
+	}
+	lg.SetLabels(Lpadded)
+	lg.SetColor(Calways, false) // keeps labels
+	lg.Log(Sinfo, Caller(-1), "i")
+
+	lines := strings.Split(sb.String(), "\n")
+	if !strings.HasPrefix(lines[0], "lab:W: ") || !strings.HasPrefix(lines[1], "lab:warn:  ") ||
+		!strings.HasPrefix(lines[2], "lab:🔶: ") || !strings.HasPrefix(lines[3], "lab:warn: ") ||
+		!strings.HasPrefix(lines[4], "lab:"+Scolor[Sinfo]+"info: "+colorReset) {
+		t.Fatal("bad labels", sb.String())
+	}
+
+	// width is in runes
+	sname := Sname
+	defer func() { Sname = sname }()
+	Sname = [...]string{"调试:", "信息:", "警告:", "错误:", "致命的:"}
+	sb.Reset()
+	lg.SetColor(Cnever, false)
+	lg.Log(Swarn, Caller(-1), "w")
+	if s := sb.String(); !strings.HasPrefix(s, "lab:警告:  ") {
+		t.Fatalf("bad padding %q", s)
+	}
+
+	lg.SetFormat(Fjson)
+	lg.SetLabels(Lletter) // no effect
+	if _, ok := lg.output().enc.(JSONEncoder); !ok {
+		t.Fatal("encoder changed")
+	}
+}
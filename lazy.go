@@ -0,0 +1,47 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+// Lazy is a deferred message list member or field value, which is evaluated only if the
+// record will be logged. Plain func() string values are also evaluated lazily:
+//
+//	yell.Info("state:", yell.Lazy(dumpState), yell.Any("size", func() string {
+//		return strconv.Itoa(expensiveSize())
+//	}))
+type Lazy func() string
+
+// lazyValue evaluates v if it is lazy
+func lazyValue(v interface{}) (interface{}, bool) {
+	switch f := v.(type) {
+	case Lazy:
+		return f(), true
+	case func() string:
+		return f(), true
+	case Field:
+		if fv, ok := lazyValue(f.Value); ok {
+			return Field{Key: f.Key, Value: fv}, true
+		}
+	}
+	return v, false
+}
+
+// evalLazy returns msg with lazy members evaluated, msg is copied if necessary
+func evalLazy(msg []interface{}) []interface{} {
+	copied := false
+	for i, m := range msg {
+		v, ok := lazyValue(m)
+		if !ok {
+			continue
+		}
+		if !copied {
+			msg = append([]interface{}(nil), msg...)
+			copied = true
+		}
+		msg[i] = v
+	}
+	return msg
+}
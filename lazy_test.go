@@ -0,0 +1,37 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLazy(t *testing.T) {
+	var sb strings.Builder
+	lg := New(": lazy:", &sb, Swarn)
+
+	calls := 0
+	dump := func() string {
+		calls++
+		return "dump"
+	}
+	msg := []interface{}{"state:", Lazy(dump), dump, Any("k", Lazy(dump))}
+
+	lg.Log(Sinfo, msg...)
+	if calls != 0 || sb.Len() != 0 {
+		t.Fatal("disabled level must not evaluate")
+	}
+
+	lg.Log(Swarn, msg...)
+	if calls != 3 || !strings.HasSuffix(sb.String(), " state: dump dump k=dump\n") {
+		t.Fatal("must evaluate lazy members:", calls, sb.String())
+	}
+	if _, ok := msg[1].(Lazy); !ok {
+		t.Fatal("message list must not be modified")
+	}
+}
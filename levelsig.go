@@ -0,0 +1,76 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+)
+
+// LevelOnSignal installs handlers for SIGUSR1 & SIGUSR2 that lower & raise minimum
+// severity of loggers (Default & registered Loggers if none given) one step per signal,
+// so verbosity of a live daemon can be changed without restarting it:
+//
+//	defer yell.LevelOnSignal()()
+//
+//	kill -USR1 $PID  # warn => info
+//	kill -USR2 $PID  # info => warn
+//
+// Levels stay between Sdebug & Sfatal. Returns a function that uninstalls the handlers.
+// It does nothing on platforms without these signals, like Windows.
+func LevelOnSignal(loggers ...*Logger) (stop func()) {
+	if levelSignals[0] == nil {
+		return func() {}
+	}
+	ch := make(chan os.Signal, 4)
+	done := make(chan struct{})
+	signal.Notify(ch, levelSignals[:]...)
+
+	go func() {
+		for {
+			select {
+			case sig := <-ch:
+				step := 1
+				if sig == levelSignals[0] {
+					step = -1
+				}
+				shiftLevels(loggers, step)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}
+}
+
+// shiftLevels adds step to minimum severities of loggers (Default & registered Loggers
+// if empty) within Sdebug & Sfatal
+func shiftLevels(loggers []*Logger, step int) {
+	if len(loggers) == 0 {
+		loggers = Loggers()
+		if def := GetDefault(); Lookup(def.name) != def {
+			loggers = append(loggers, def)
+		}
+	}
+	for _, lg := range loggers {
+		level := int(lg.GetLevel()) + step
+		if level < int(Sdebug) {
+			level = int(Sdebug)
+		} else if level > int(Sfatal) {
+			level = int(Sfatal)
+		}
+		lg.SetLevel(Severity(level))
+	}
+}
@@ -0,0 +1,80 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestShiftLevels(t *testing.T) {
+	registry.Lock()
+	saved := registry.loggers
+	registry.loggers = nil
+	registry.Unlock()
+	defer func() {
+		registry.Lock()
+		registry.loggers = saved
+		registry.Unlock()
+	}()
+
+	reg := New(": reg:", ioutil.Discard, Sinfo)
+	def := New(": def:", ioutil.Discard, Swarn)
+	Register(&reg)
+	SetDefault(&def)
+	defer SetDefault(nil)
+
+	shiftLevels(nil, -1)
+	if reg.GetLevel() != Sdebug || def.GetLevel() != Sinfo {
+		t.Fatal("levels must be lowered:", reg.GetLevel(), def.GetLevel())
+	}
+	shiftLevels(nil, -1)
+	if reg.GetLevel() != Sdebug || def.GetLevel() != Sdebug {
+		t.Fatal("levels must stay at debug")
+	}
+
+	Register(&def) // not shifted twice
+	for i := 0; i < 5; i++ {
+		shiftLevels(nil, 1)
+	}
+	if reg.GetLevel() != Sfatal || def.GetLevel() != Sfatal {
+		t.Fatal("levels must stop at fatal:", reg.GetLevel(), def.GetLevel())
+	}
+	shiftLevels([]*Logger{&reg}, -2)
+	if reg.GetLevel() != Swarn || def.GetLevel() != Sfatal {
+		t.Fatal("only given loggers must be shifted")
+	}
+}
+
+func TestLevelOnSignal(t *testing.T) {
+	if levelSignals[0] == nil {
+		t.Skip("no level signals")
+	}
+	lg := New(": ls:", ioutil.Discard, Swarn)
+	stop := LevelOnSignal(&lg)
+	defer stop()
+
+	p, _ := os.FindProcess(os.Getpid())
+	wait := func(sig os.Signal, level Severity) {
+		if err := p.Signal(sig); err != nil {
+			t.Skip("cannot send signal:", err)
+		}
+		for i := 0; i < 100 && lg.GetLevel() != level; i++ {
+			time.Sleep(10 * time.Millisecond)
+		}
+		if lg.GetLevel() != level {
+			t.Fatal("unexpected level:", lg.GetLevel(), level)
+		}
+	}
+	wait(levelSignals[0], Sinfo)
+	wait(levelSignals[0], Sdebug)
+	wait(levelSignals[1], Sinfo)
+	stop()
+	stop()
+}
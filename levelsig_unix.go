@@ -0,0 +1,18 @@
+//go:build !windows && !plan9 && !js
+// +build !windows,!plan9,!js
+
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"os"
+	"syscall"
+)
+
+// signals that lower & raise levels, see LevelOnSignal
+var levelSignals = [2]os.Signal{syscall.SIGUSR1, syscall.SIGUSR2}
@@ -0,0 +1,188 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"io"
+	"os"
+	"time"
+)
+
+// Flusher is implemented by writers that buffer records, like bufio.Writer
+type Flusher interface {
+	Flush() error
+}
+
+// Syncer is implemented by writers that can commit records to stable storage, like
+// os.File
+type Syncer interface {
+	Sync() error
+}
+
+// LevelWriter is implemented by writers that route records by severity, like copying
+// severe records to another output. Logger writes records with WriteLevel instead of
+// Write, except the ones replayed by flight recorder (see SetFlightRecorder) and written
+// by AsyncWriter & ShardedWriter, whose levels are not kept.
+type LevelWriter interface {
+	io.Writer
+	WriteLevel(level Severity, p []byte) (int, error)
+}
+
+// standard reports whether w is standard output or error, which are never synced or
+// closed by yell
+func standard(w io.Writer) bool {
+	return w == os.Stdout || w == os.Stderr
+}
+
+// flushWriter flushes w if it is a Flusher, otherwise syncs w if it is a Syncer
+func flushWriter(w io.Writer) error {
+	if standard(w) {
+		return nil
+	}
+	switch f := w.(type) {
+	case Flusher:
+		return f.Flush()
+	case Syncer:
+		return f.Sync()
+	}
+	return nil
+}
+
+// closeWriter flushes w and closes it if it is an io.Closer
+func closeWriter(w io.Writer) error {
+	err := flushWriter(w)
+	if c, ok := w.(io.Closer); ok && !standard(w) {
+		if e := c.Close(); err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// Flush empties buffers of Logger's writer if it implements Flusher (or commits them
+// with Sync if it implements Syncer), for example on graceful shutdown. Writer wrappers
+// of yell propagate Flush to their writers. os.Stdout & os.Stderr are not synced.
+func (lg *Logger) Flush() error {
+	out := lg.output()
+	if out.lc != nil {
+		out.lc.Lock()
+		defer out.lc.Unlock()
+	}
+	return flushWriter(out.writer)
+}
+
+// Close flushes Logger's writer (see Flush) and closes it if it implements io.Closer.
+// Records logged afterwards will likely fail. Writer wrappers of yell propagate Close to
+// their writers. os.Stdout & os.Stderr are not closed.
+func (lg *Logger) Close() error {
+	out := lg.output()
+	if out.lc != nil {
+		out.lc.Lock()
+		defer out.lc.Unlock()
+	}
+	return closeWriter(out.writer)
+}
+
+// Flush flushes underlying writer, waiting for at most the timeout for a write in
+// progress. Returns ErrTimeout if it is still in progress.
+func (tw *TimeoutWriter) Flush() error {
+	return tw.idle(func() error { return flushWriter(tw.writer) })
+}
+
+// Close flushes & closes underlying writer, waiting for at most the timeout for a write
+// in progress. Returns ErrTimeout if it is still in progress.
+func (tw *TimeoutWriter) Close() error {
+	return tw.idle(func() error { return closeWriter(tw.writer) })
+}
+
+// idle calls f when no write is in progress
+func (tw *TimeoutWriter) idle(f func() error) error {
+	timer := time.NewTimer(tw.timeout)
+	defer timer.Stop()
+
+	select {
+	case tw.busy <- struct{}{}:
+	case <-timer.C:
+		return ErrTimeout
+	}
+	defer func() { <-tw.busy }()
+	return f()
+}
+
+// Flush flushes underlying writer
+func (rw *RetryWriter) Flush() error {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	return flushWriter(rw.writer)
+}
+
+// Close flushes & closes underlying writer
+func (rw *RetryWriter) Close() error {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	return closeWriter(rw.writer)
+}
+
+// Flush flushes underlying writer
+func (bw *BreakerWriter) Flush() error {
+	bw.writeMu.Lock()
+	defer bw.writeMu.Unlock()
+	return flushWriter(bw.writer)
+}
+
+// Close flushes & closes underlying writer
+func (bw *BreakerWriter) Close() error {
+	bw.writeMu.Lock()
+	defer bw.writeMu.Unlock()
+	return closeWriter(bw.writer)
+}
+
+// Flush flushes underlying writer
+func (aw *AuditWriter) Flush() error {
+	aw.mu.Lock()
+	defer aw.mu.Unlock()
+	return flushWriter(aw.writer)
+}
+
+// Close flushes & closes underlying writer
+func (aw *AuditWriter) Close() error {
+	aw.mu.Lock()
+	defer aw.mu.Unlock()
+	return closeWriter(aw.writer)
+}
+
+// Flush flushes underlying writer
+func (sw *SignWriter) Flush() error {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	return flushWriter(sw.writer)
+}
+
+// Close flushes & closes underlying writer
+func (sw *SignWriter) Close() error {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	return closeWriter(sw.writer)
+}
+
+// Flush flushes underlying writer
+func (ew *EncryptWriter) Flush() error {
+	ew.mu.Lock()
+	defer ew.mu.Unlock()
+	return flushWriter(ew.writer)
+}
+
+// Close writes the closing frame, flushes & closes underlying writer
+func (ew *EncryptWriter) Close() error {
+	ew.mu.Lock()
+	defer ew.mu.Unlock()
+	err := ew.end()
+	if e := closeWriter(ew.writer); err == nil {
+		err = e
+	}
+	return err
+}
@@ -0,0 +1,111 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+)
+
+// closeRecorder counts syncs & closes
+type closeRecorder struct {
+	bytes.Buffer
+	syncs, closes int
+}
+
+func (c *closeRecorder) Sync() error {
+	c.syncs++
+	return nil
+}
+
+func (c *closeRecorder) Close() error {
+	c.closes++
+	return nil
+}
+
+func TestFlushClose(t *testing.T) {
+	var rec closeRecorder
+	bw := bufio.NewWriter(&rec)
+	lg := New(": fc:", bw, Sinfo)
+
+	lg.Log(Sinfo, "buffered")
+	if rec.Len() != 0 {
+		t.Fatal("record must be buffered")
+	}
+	if err := lg.Flush(); err != nil || rec.Len() == 0 {
+		t.Fatal("must flush:", err)
+	}
+
+	wrappers := [...]io.Writer{
+		NewTimeoutWriter(&rec, time.Second, nil),
+		NewRetryWriter(&rec, 1, time.Millisecond, time.Millisecond, nil),
+		NewBreakerWriter(&rec, 1, time.Second, nil),
+		NewAuditWriter(&rec, nil),
+		NewSignWriter(&rec, []byte("key")),
+		func() io.Writer { ew, _ := NewEncryptWriter(&rec, make([]byte, 16)); return ew }(),
+	}
+	for i, w := range wrappers {
+		rec.syncs, rec.closes = 0, 0
+		lg.UpdateWriter(w)
+		if err := lg.Flush(); err != nil || rec.syncs != 1 {
+			t.Fatal("wrapper must propagate Flush:", i, err)
+		}
+		if err := lg.Close(); err != nil || rec.closes != 1 {
+			t.Fatal("wrapper must propagate Close:", i, err)
+		}
+	}
+
+	// standard writers are left alone
+	lg.UpdateWriter(os.Stderr)
+	if lg.Flush() != nil || lg.Close() != nil {
+		t.Fatal("must ignore standard writers")
+	}
+	if _, err := os.Stderr.Write(nil); errors.Is(err, os.ErrClosed) {
+		t.Fatal("must not close os.Stderr")
+	}
+
+	// flush waits for write in progress
+	hw := &hungWriter{release: make(chan bool), done: make(chan bool)}
+	tw := NewTimeoutWriter(hw, time.Millisecond, nil)
+	tw.Write([]byte("hung\n"))
+	if err := tw.Flush(); err != ErrTimeout {
+		t.Fatal("must time out:", err)
+	}
+	hw.release <- true
+	<-hw.done
+}
+
+// levelRecorder records levels of written records
+type levelRecorder struct {
+	bytes.Buffer
+	levels []Severity
+}
+
+func (l *levelRecorder) WriteLevel(level Severity, p []byte) (int, error) {
+	l.levels = append(l.levels, level)
+	return l.Write(p)
+}
+
+func TestLevelWriter(t *testing.T) {
+	lr := &levelRecorder{}
+	lg := New(": lw:", lr, Sdebug)
+	lg.Log(Sdebug, "d")
+	lg.SetFlightRecorder(NewRing(4), Serror)
+	lg.Log(Sinfo, "buffered")
+	lg.Log(Serror, "e")
+
+	// replayed record is written with Write
+	if len(lr.levels) != 2 || lr.levels[0] != Sdebug || lr.levels[1] != Serror ||
+		bytes.Count(lr.Bytes(), []byte("\n")) != 3 {
+		t.Fatal("bad levels", lr.levels, lr.String())
+	}
+}
@@ -0,0 +1,69 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"strconv"
+	"time"
+)
+
+// LogfmtEncoder writes records as logfmt key=value pairs with RFC 3339 time (see
+// SetTimeMode):
+//
+//	time=2021-03-28T21:48:53.591948+03:00 level=info name=mypkg caller=myApp.go:15
+//	msg="some info: 1 more" key=value
+//
+// (on a single line).
+//
+// Level is severity name without trailing colon. Error members of message list are
+// written as error=msg error.type=T error.causes="cause1; cause2" (keys are numbered if
+// there are multiple errors). Keys & values are quoted if necessary.
+type LogfmtEncoder struct{}
+
+// Encode appends logfmt record to buf
+func (LogfmtEncoder) Encode(buf []byte, r *Record) ([]byte, error) {
+	if r.tmode != Tnone {
+		buf = append(buf, "time="...)
+		buf = r.AppendTime(buf, time.RFC3339Nano)
+		buf = append(buf, ' ')
+	}
+	buf = append(buf, "level="...)
+	buf = appendValue(buf, levelName(r.Level))
+	buf = append(buf, " name="...)
+	buf = appendValue(buf, r.Name)
+
+	if r.Seq != 0 {
+		buf = append(buf, " seq="...)
+		buf = strconv.AppendUint(buf, r.Seq, 10)
+	}
+	if r.ID != "" {
+		buf = append(buf, " id="...)
+		buf = append(buf, r.ID...)
+	}
+
+	if r.File != "" {
+		buf = append(buf, " caller="...)
+		buf = appendValue(buf, r.File+":"+strconv.Itoa(r.Line))
+	}
+
+	buf = append(buf, " msg="...)
+	buf = appendValue(buf, r.Msg)
+	buf = appendLogfmtErrors(buf, r.Errs)
+
+	for _, f := range r.Fields {
+		buf = append(buf, ' ')
+		buf = appendText(buf, f.Key)
+		buf = append(buf, '=')
+		buf = appendFieldValue(buf, f)
+	}
+
+	if r.Detail != "" {
+		buf = append(buf, " detail="...)
+		buf = appendValue(buf, r.Detail)
+	}
+	return append(buf, '\n'), nil
+}
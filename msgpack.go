@@ -0,0 +1,668 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// MsgpackEncoder writes each record as a compact, self-delimiting MessagePack map for
+// bandwidth-sensitive shipping. Unlike text encoders, records do not end with a newline.
+// Keys are time (timestamp extension), name, level (severity number), seq & id (if
+// enabled), file & line (if known), msg, errors (array of {msg, causes} maps), fields
+// (map in message list order) and detail. Field values that are not nil, booleans,
+// numbers, strings, byte slices, errors, times, []interface{} or map[string]interface{}
+// are encoded as strings formatted with %v. See MsgpackDecoder to read records back.
+type MsgpackEncoder struct{}
+
+// Encode appends MessagePack record to buf
+func (MsgpackEncoder) Encode(buf []byte, r *Record) ([]byte, error) {
+	n := 4 // time, name, level, msg
+	if r.Seq != 0 {
+		n++
+	}
+	if r.ID != "" {
+		n++
+	}
+	if r.File != "" {
+		n += 2 // file, line
+	}
+	if len(r.Errs) > 0 {
+		n++
+	}
+	if len(r.Fields) > 0 {
+		n++
+	}
+	if r.Detail != "" {
+		n++
+	}
+	buf = appendMsgpackHeader(buf, 0x80, 0xde, n)
+
+	buf = appendMsgpackString(buf, "time")
+	buf = appendMsgpackTime(buf, r.Time)
+	buf = appendMsgpackString(buf, "name")
+	buf = appendMsgpackString(buf, r.Name)
+	buf = appendMsgpackString(buf, "level")
+	buf = appendMsgpackUint(buf, uint64(r.Level))
+
+	if r.Seq != 0 {
+		buf = appendMsgpackString(buf, "seq")
+		buf = appendMsgpackUint(buf, r.Seq)
+	}
+	if r.ID != "" {
+		buf = appendMsgpackString(buf, "id")
+		buf = appendMsgpackString(buf, r.ID)
+	}
+	if r.File != "" {
+		buf = appendMsgpackString(buf, "file")
+		buf = appendMsgpackString(buf, r.File)
+		buf = appendMsgpackString(buf, "line")
+		buf = appendMsgpackInt(buf, int64(r.Line))
+	}
+
+	buf = appendMsgpackString(buf, "msg")
+	buf = appendMsgpackString(buf, r.Msg)
+
+	if len(r.Errs) > 0 {
+		buf = appendMsgpackString(buf, "errors")
+		buf = appendMsgpackHeader(buf, 0x90, 0xdc, len(r.Errs))
+		for _, err := range r.Errs {
+			cs := causes(err)
+			if len(cs) == 0 {
+				buf = append(buf, 0x81)
+			} else {
+				buf = append(buf, 0x82)
+			}
+			buf = appendMsgpackString(buf, "msg")
+			buf = appendMsgpackString(buf, err.Error())
+
+			if len(cs) > 0 {
+				buf = appendMsgpackString(buf, "causes")
+				buf = appendMsgpackHeader(buf, 0x90, 0xdc, len(cs))
+				for _, c := range cs {
+					buf = appendMsgpackString(buf, c)
+				}
+			}
+		}
+	}
+
+	if len(r.Fields) > 0 {
+		buf = appendMsgpackString(buf, "fields")
+		buf = appendMsgpackHeader(buf, 0x80, 0xde, len(r.Fields))
+		for _, f := range r.Fields {
+			buf = appendMsgpackString(buf, f.Key)
+			buf = appendMsgpackValue(buf, f.Interface())
+		}
+	}
+
+	if r.Detail != "" {
+		buf = appendMsgpackString(buf, "detail")
+		buf = appendMsgpackString(buf, r.Detail)
+	}
+	return buf, nil
+}
+
+// appendMsgpackHeader appends array or map header with n members. fix is the fixarray or
+// fixmap prefix, c16 is the 16-bit length prefix, followed by the 32-bit one.
+func appendMsgpackHeader(buf []byte, fix, c16 byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(buf, fix|byte(n))
+	case n <= math.MaxUint16:
+		return append(buf, c16, byte(n>>8), byte(n))
+	}
+	return appendUint32(append(buf, c16+1), uint32(n))
+}
+
+// appendUint32 appends big-endian u
+func appendUint32(buf []byte, u uint32) []byte {
+	return append(buf, byte(u>>24), byte(u>>16), byte(u>>8), byte(u))
+}
+
+// appendUint64 appends big-endian u
+func appendUint64(buf []byte, u uint64) []byte {
+	return appendUint32(appendUint32(buf, uint32(u>>32)), uint32(u))
+}
+
+// appendMsgpackString appends s as a MessagePack string
+func appendMsgpackString(buf []byte, s string) []byte {
+	n := len(s)
+	switch {
+	case n < 32:
+		buf = append(buf, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		buf = append(buf, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		buf = append(buf, 0xda, byte(n>>8), byte(n))
+	default:
+		buf = appendUint32(append(buf, 0xdb), uint32(n))
+	}
+	return append(buf, s...)
+}
+
+// appendMsgpackBinary appends b as MessagePack binary
+func appendMsgpackBinary(buf, b []byte) []byte {
+	n := len(b)
+	switch {
+	case n <= math.MaxUint8:
+		buf = append(buf, 0xc4, byte(n))
+	case n <= math.MaxUint16:
+		buf = append(buf, 0xc5, byte(n>>8), byte(n))
+	default:
+		buf = appendUint32(append(buf, 0xc6), uint32(n))
+	}
+	return append(buf, b...)
+}
+
+// appendMsgpackUint appends u as a MessagePack integer
+func appendMsgpackUint(buf []byte, u uint64) []byte {
+	switch {
+	case u < 128:
+		return append(buf, byte(u))
+	case u <= math.MaxUint8:
+		return append(buf, 0xcc, byte(u))
+	case u <= math.MaxUint16:
+		return append(buf, 0xcd, byte(u>>8), byte(u))
+	case u <= math.MaxUint32:
+		return appendUint32(append(buf, 0xce), uint32(u))
+	}
+	return appendUint64(append(buf, 0xcf), u)
+}
+
+// appendMsgpackInt appends i as a MessagePack integer
+func appendMsgpackInt(buf []byte, i int64) []byte {
+	switch {
+	case i >= 0:
+		return appendMsgpackUint(buf, uint64(i))
+	case i >= -32:
+		return append(buf, byte(i)) // negative fixint
+	case i >= math.MinInt8:
+		return append(buf, 0xd0, byte(i))
+	case i >= math.MinInt16:
+		return append(buf, 0xd1, byte(i>>8), byte(i))
+	case i >= math.MinInt32:
+		return appendUint32(append(buf, 0xd2), uint32(i))
+	}
+	return appendUint64(append(buf, 0xd3), uint64(i))
+}
+
+// appendMsgpackTime appends t as a 96-bit MessagePack timestamp
+func appendMsgpackTime(buf []byte, t time.Time) []byte {
+	buf = appendUint32(append(buf, 0xc7, 12, 0xff), uint32(t.Nanosecond()))
+	return appendUint64(buf, uint64(t.Unix()))
+}
+
+// appendMsgpackValue appends v as a MessagePack value
+func appendMsgpackValue(buf []byte, v interface{}) []byte {
+	switch x := v.(type) {
+	case nil:
+		return append(buf, 0xc0)
+	case bool:
+		if x {
+			return append(buf, 0xc3)
+		}
+		return append(buf, 0xc2)
+	case string:
+		return appendMsgpackString(buf, x)
+	case []byte:
+		return appendMsgpackBinary(buf, x)
+	case int:
+		return appendMsgpackInt(buf, int64(x))
+	case int64:
+		return appendMsgpackInt(buf, x)
+	case int32:
+		return appendMsgpackInt(buf, int64(x))
+	case int16:
+		return appendMsgpackInt(buf, int64(x))
+	case int8:
+		return appendMsgpackInt(buf, int64(x))
+	case uint:
+		return appendMsgpackUint(buf, uint64(x))
+	case uint64:
+		return appendMsgpackUint(buf, x)
+	case uint32:
+		return appendMsgpackUint(buf, uint64(x))
+	case uint16:
+		return appendMsgpackUint(buf, uint64(x))
+	case uint8:
+		return appendMsgpackUint(buf, uint64(x))
+	case float64:
+		return appendUint64(append(buf, 0xcb), math.Float64bits(x))
+	case float32:
+		return appendUint32(append(buf, 0xca), math.Float32bits(x))
+	case time.Duration:
+		return appendMsgpackInt(buf, int64(x))
+	case time.Time:
+		return appendMsgpackTime(buf, x)
+	case error:
+		return appendMsgpackString(buf, x.Error())
+	case []interface{}:
+		buf = appendMsgpackHeader(buf, 0x90, 0xdc, len(x))
+		for _, e := range x {
+			buf = appendMsgpackValue(buf, e)
+		}
+		return buf
+	case map[string]interface{}:
+		buf = appendMsgpackHeader(buf, 0x80, 0xde, len(x))
+		for k, e := range x {
+			buf = appendMsgpackString(buf, k)
+			buf = appendMsgpackValue(buf, e)
+		}
+		return buf
+	}
+	return appendMsgpackString(buf, fmt.Sprint(v))
+}
+
+// ErrMsgpack is returned by MsgpackDecoder for malformed records
+var ErrMsgpack = errors.New("yell: malformed msgpack record")
+
+// maximum length of MessagePack strings, arrays etc. accepted by MsgpackDecoder
+const maxMsgpackLen = 16 << 20
+
+// byteReader can read by bytes and slices
+type byteReader interface {
+	io.Reader
+	io.ByteReader
+}
+
+// MsgpackDecoder reads records written by MsgpackEncoder, so tooling can convert them
+// back to text or JSON, see ConvertMsgpack. Decoded errors keep their messages & causes.
+type MsgpackDecoder struct {
+	r   byteReader
+	buf [8]byte
+}
+
+// NewMsgpackDecoder creates a MsgpackDecoder that reads records from r
+func NewMsgpackDecoder(r io.Reader) *MsgpackDecoder {
+	br, ok := r.(byteReader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	return &MsgpackDecoder{r: br}
+}
+
+// msgError is a decoded error with its cause
+type msgError struct {
+	msg   string
+	cause error
+}
+
+func (e *msgError) Error() string {
+	return e.msg
+}
+
+func (e *msgError) Unwrap() error {
+	return e.cause
+}
+
+// Decode reads next record. Returns io.EOF if there are no more records, ErrMsgpack (or
+// read error of underlying reader) if the record is malformed or truncated.
+func (d *MsgpackDecoder) Decode() (r Record, err error) {
+	c, err := d.r.ReadByte()
+	if err != nil {
+		return // io.EOF if no more records
+	}
+	n, ok := d.header(c, 0x80, 0xde)
+	if !ok {
+		return r, ErrMsgpack
+	}
+
+	defer func() {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			err = ErrMsgpack // truncated
+		}
+	}()
+
+	for ; n > 0; n-- {
+		var key string
+		if key, err = d.string(); err != nil {
+			return
+		}
+
+		var v interface{}
+		switch key {
+		case "errors":
+			r.Errs, err = d.errors()
+		case "fields":
+			r.Fields, err = d.fields()
+		default:
+			v, err = d.value()
+		}
+		if err != nil {
+			return
+		}
+
+		ok := true
+		switch key {
+		case "time":
+			r.Time, ok = v.(time.Time)
+		case "name":
+			r.Name, ok = v.(string)
+		case "level":
+			var l int64
+			l, ok = v.(int64)
+			ok = ok && 0 <= l && l < int64(Snolog)
+			r.Level = Severity(l)
+		case "seq":
+			var s int64
+			if s, ok = v.(int64); ok {
+				r.Seq = uint64(s)
+			} else {
+				r.Seq, ok = v.(uint64)
+			}
+		case "id":
+			r.ID, ok = v.(string)
+		case "file":
+			r.File, ok = v.(string)
+		case "line":
+			var l int64
+			l, ok = v.(int64)
+			r.Line = int(l)
+		case "msg":
+			r.Msg, ok = v.(string)
+		case "detail":
+			r.Detail, ok = v.(string)
+		}
+		if !ok {
+			return r, ErrMsgpack
+		}
+	}
+	if UTC {
+		r.Time = r.Time.UTC()
+	}
+	return
+}
+
+// errors reads an array of {msg, causes} maps
+func (d *MsgpackDecoder) errors() ([]error, error) {
+	n, err := d.length(0x90, 0xdc)
+	if err != nil {
+		return nil, err
+	}
+	errs := make([]error, n)
+
+	for i := range errs {
+		v, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		m, _ := v.(map[string]interface{})
+		msg, ok := m["msg"].(string)
+		if !ok {
+			return nil, ErrMsgpack
+		}
+
+		cs, _ := m["causes"].([]interface{})
+		var cause error
+		for k := len(cs) - 1; k >= 0; k-- {
+			c, ok := cs[k].(string)
+			if !ok {
+				return nil, ErrMsgpack
+			}
+			cause = &msgError{c, cause}
+		}
+		errs[i] = &msgError{msg, cause}
+	}
+	return errs, nil
+}
+
+// fields reads a map of fields in order
+func (d *MsgpackDecoder) fields() ([]Field, error) {
+	n, err := d.length(0x80, 0xde)
+	if err != nil {
+		return nil, err
+	}
+	fields := make([]Field, n)
+
+	for i := range fields {
+		if fields[i].Key, err = d.string(); err != nil {
+			return nil, err
+		}
+		if fields[i].Value, err = d.value(); err != nil {
+			return nil, err
+		}
+	}
+	return fields, nil
+}
+
+// header returns number of members of array or map with prefix byte c. fix is the
+// fixarray or fixmap prefix, c16 is the 16-bit length prefix, followed by the 32-bit one.
+func (d *MsgpackDecoder) header(c, fix, c16 byte) (int, bool) {
+	switch c {
+	case c16:
+		b, err := d.read(2)
+		return int(binary.BigEndian.Uint16(b)), err == nil
+	case c16 + 1:
+		b, err := d.read(4)
+		if err != nil {
+			return 0, false
+		}
+		n := binary.BigEndian.Uint32(b)
+		return int(n), n <= maxMsgpackLen
+	}
+	return int(c & 15), c&0xf0 == fix
+}
+
+// length reads header of array or map, see header
+func (d *MsgpackDecoder) length(fix, c16 byte) (int, error) {
+	c, err := d.r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	n, ok := d.header(c, fix, c16)
+	if !ok {
+		return 0, ErrMsgpack
+	}
+	return n, nil
+}
+
+// string reads a string value
+func (d *MsgpackDecoder) string() (string, error) {
+	v, err := d.value()
+	if err != nil {
+		return "", err
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", ErrMsgpack
+	}
+	return s, nil
+}
+
+// read n bytes, valid until next read
+func (d *MsgpackDecoder) read(n int) ([]byte, error) {
+	b := d.buf[:n]
+	_, err := io.ReadFull(d.r, b)
+	return b, err
+}
+
+// readUint reads n byte big-endian unsigned integer
+func (d *MsgpackDecoder) readUint(n int) (u uint64, err error) {
+	b, err := d.read(n)
+	for _, c := range b {
+		u = u<<8 | uint64(c)
+	}
+	return
+}
+
+// bytes reads string or binary of n bytes with n's length given in size bytes
+func (d *MsgpackDecoder) bytes(size int) ([]byte, error) {
+	n, err := d.readUint(size)
+	if err != nil {
+		return nil, err
+	}
+	if n > maxMsgpackLen {
+		return nil, ErrMsgpack
+	}
+	b := make([]byte, n)
+	_, err = io.ReadFull(d.r, b)
+	return b, err
+}
+
+// value reads any value. Integers are returned as int64 (uint64 if too large), floats as
+// float64, arrays as []interface{} and maps as map[string]interface{}.
+func (d *MsgpackDecoder) value() (interface{}, error) {
+	c, err := d.r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case c < 0x80: // positive fixint
+		return int64(c), nil
+	case c >= 0xe0: // negative fixint
+		return int64(int8(c)), nil
+	case c&0xe0 == 0xa0: // fixstr
+		b := make([]byte, c&31)
+		_, err = io.ReadFull(d.r, b)
+		return string(b), err
+	case c&0xf0 == 0x90, c == 0xdc, c == 0xdd:
+		return d.array(c)
+	case c&0xf0 == 0x80, c == 0xde, c == 0xdf:
+		return d.dict(c)
+	}
+
+	var u uint64
+	switch c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xd9, 0xda, 0xdb: // str
+		b, err := d.bytes(1 << (c - 0xd9))
+		return string(b), err
+	case 0xc4, 0xc5, 0xc6: // bin
+		return d.bytes(1 << (c - 0xc4))
+	case 0xcc, 0xcd, 0xce, 0xcf: // uint
+		if u, err = d.readUint(1 << (c - 0xcc)); u > math.MaxInt64 {
+			return u, err
+		}
+		return int64(u), err
+	case 0xd0, 0xd1, 0xd2, 0xd3: // int
+		n := 1 << (c - 0xd0)
+		u, err = d.readUint(n)
+		sh := uint(64 - 8*n)
+		return int64(u<<sh) >> sh, err // sign extend
+	case 0xca:
+		u, err = d.readUint(4)
+		return float64(math.Float32frombits(uint32(u))), err
+	case 0xcb:
+		u, err = d.readUint(8)
+		return math.Float64frombits(u), err
+	case 0xd6, 0xd7, 0xc7: // timestamps
+		return d.time(c)
+	}
+	return nil, ErrMsgpack
+}
+
+// time reads 32, 64 or 96-bit timestamp extension with prefix c
+func (d *MsgpackDecoder) time(c byte) (t time.Time, err error) {
+	var n byte = 4
+	if c == 0xd7 {
+		n = 8
+	} else if c == 0xc7 {
+		var b []byte
+		if b, err = d.read(1); err != nil {
+			return
+		}
+		n = b[0]
+	}
+
+	b, err := d.read(1)
+	if err != nil {
+		return
+	}
+	if b[0] != 0xff || (n != 4 && n != 8 && n != 12) {
+		return t, ErrMsgpack // not a timestamp
+	}
+
+	var sec, nsec uint64
+	switch n {
+	case 4:
+		sec, err = d.readUint(4)
+	case 8:
+		if sec, err = d.readUint(8); err == nil {
+			sec, nsec = sec&(1<<34-1), sec>>34
+		}
+	case 12:
+		if nsec, err = d.readUint(4); err == nil {
+			sec, err = d.readUint(8)
+		}
+	}
+	return time.Unix(int64(sec), int64(nsec)), err
+}
+
+// array reads an array with prefix c
+func (d *MsgpackDecoder) array(c byte) (interface{}, error) {
+	n, ok := d.header(c, 0x90, 0xdc)
+	if !ok {
+		return nil, ErrMsgpack
+	}
+	a := make([]interface{}, 0, n%1024)
+	for ; n > 0; n-- {
+		v, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		a = append(a, v)
+	}
+	return a, nil
+}
+
+// dict reads a map with string keys & prefix c
+func (d *MsgpackDecoder) dict(c byte) (interface{}, error) {
+	n, ok := d.header(c, 0x80, 0xde)
+	if !ok {
+		return nil, ErrMsgpack
+	}
+	m := make(map[string]interface{}, n%1024)
+	for ; n > 0; n-- {
+		k, err := d.string()
+		if err != nil {
+			return nil, err
+		}
+		if m[k], err = d.value(); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// ConvertMsgpack reads MessagePack records from r, encodes them with enc (like
+// TextEncoder{} or JSONEncoder{}) and writes them to w. Returns number of converted
+// records.
+func ConvertMsgpack(w io.Writer, r io.Reader, enc Encoder) (records int, err error) {
+	d := NewMsgpackDecoder(r)
+	var (
+		buf []byte
+		rec Record
+	)
+	for {
+		if rec, err = d.Decode(); err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+			return
+		}
+
+		if buf, err = enc.Encode(buf[:0], &rec); err != nil {
+			return records, err
+		}
+		if _, err = w.Write(buf); err != nil {
+			return records, err
+		}
+		records++
+	}
+}
@@ -0,0 +1,105 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMsgpack(t *testing.T) {
+	values := []interface{}{nil, true, false, "", "short", strings.Repeat("x", 40),
+		strings.Repeat("y", 300), strings.Repeat("z", 70000), []byte{1, 2},
+		0, 127, 128, 255, 256, 65535, 65536, int64(math.MaxUint32), int64(math.MaxUint32 + 1),
+		-1, -32, -33, -128, -129, -32768, -32769, math.MinInt32, int64(math.MinInt32 - 1),
+		uint64(math.MaxUint64), 1.5, float32(2.5), []interface{}{"a", 1},
+		map[string]interface{}{"k": "v"}}
+
+	r := Record{Time: time.Unix(1616957333, 591948000), Level: Serror, Name: "mp",
+		File: "a.go", Line: 12, Msg: "hello", Seq: 7, ID: "01F1ZP", Detail: "det",
+		Errs: []error{fmt.Errorf("wrap: %w", errors.New("root")), errors.New("other")}}
+	for i, v := range values {
+		r.Fields = append(r.Fields, Any(fmt.Sprint("f", i), v))
+	}
+	r.Fields = append(r.Fields, Any("dur", time.Second), Any("err", errors.New("e")),
+		Any("t", r.Time), Any("s", struct{ A int }{3}))
+
+	buf, err := MsgpackEncoder{}.Encode(nil, &r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf2, _ := MsgpackEncoder{}.Encode(nil, &Record{Time: r.Time, Msg: "second"})
+	buf = append(buf, buf2...)
+
+	d := NewMsgpackDecoder(bytes.NewReader(buf))
+	q, err := d.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !q.Time.Equal(r.Time) || q.Level != r.Level || q.Name != r.Name || q.File != r.File ||
+		q.Line != r.Line || q.Msg != r.Msg || q.Seq != r.Seq || q.ID != r.ID ||
+		q.Detail != r.Detail || len(q.Fields) != len(r.Fields) || len(q.Errs) != 2 {
+		t.Fatal("decoded record differs:", q)
+	}
+	if q.Errs[0].Error() != "wrap: root" || errors.Unwrap(q.Errs[0]).Error() != "root" {
+		t.Fatal("error causes must be kept")
+	}
+
+	want := []interface{}{nil, true, false, "", "short", strings.Repeat("x", 40),
+		strings.Repeat("y", 300), strings.Repeat("z", 70000), []byte{1, 2},
+		int64(0), int64(127), int64(128), int64(255), int64(256), int64(65535),
+		int64(65536), int64(math.MaxUint32), int64(math.MaxUint32 + 1), int64(-1),
+		int64(-32), int64(-33), int64(-128), int64(-129), int64(-32768), int64(-32769),
+		int64(math.MinInt32), int64(math.MinInt32 - 1), uint64(math.MaxUint64), 1.5, 2.5,
+		[]interface{}{"a", int64(1)}, map[string]interface{}{"k": "v"},
+		int64(time.Second), "e", r.Time.Local(), "{3}"}
+	for i, f := range q.Fields {
+		if !reflect.DeepEqual(f.Value, want[i]) {
+			t.Fatal("field differs:", i, f.Value, want[i])
+		}
+	}
+
+	if q, err = d.Decode(); err != nil || q.Msg != "second" || q.File != "" {
+		t.Fatal("second record differs:", q, err)
+	}
+	if _, err = d.Decode(); err != io.EOF {
+		t.Fatal("must be at end:", err)
+	}
+
+	for _, b := range [...][]byte{buf[:len(buf)/2], {0x81, 0xa1, 'x', 0xc1}, {0x01}} {
+		if _, err = NewMsgpackDecoder(bytes.NewReader(b)).Decode(); err != ErrMsgpack {
+			t.Fatal("must fail to decode:", err)
+		}
+	}
+}
+
+func TestConvertMsgpack(t *testing.T) {
+	var bin, txt bytes.Buffer
+	lg := New(": mp:", &txt, Sinfo)
+	lg.Log(Swarn, "some", 1, Any("k", "v"), errors.New("failed"))
+
+	lg.SetEncoder(MsgpackEncoder{})
+	lg.UpdateWriter(&bin)
+	lg.Log(Swarn, "some", 1, Any("k", "v"), errors.New("failed"))
+
+	var out bytes.Buffer
+	n, err := ConvertMsgpack(&out, &bin, TextEncoder{})
+	if err != nil || n != 1 {
+		t.Fatal("must convert:", n, err)
+	}
+	i := strings.Index(txt.String(), ": mp:") // skip time
+	if !strings.HasSuffix(out.String(), txt.String()[i:]) {
+		t.Fatal("converted record differs:", out.String(), txt.String())
+	}
+}
@@ -0,0 +1,32 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import "sort"
+
+// SetSortFields enables or disables sorting of record fields by key. Fields are always in
+// a deterministic order: bound fields (of Scopes & contexts) first, then fields of the
+// message list, each group in given order. With sorting enabled, each group is sorted by
+// key (stably, duplicate keys keep their order), so outputs of encoders are stable across
+// code changes, for diffs, tests & downstream parsers. Sorting is disabled by default. It
+// should be called before Logger is used.
+func (lg *Logger) SetSortFields(on bool) {
+	lg.sortFields = on
+}
+
+// sortFields sorts first bound fields and the rest separately by key
+func sortFields(fields []Field, bound int) {
+	sortKeys(fields[:bound])
+	sortKeys(fields[bound:])
+}
+
+// sortKeys sorts fields stably by key
+func sortKeys(fields []Field) {
+	sort.SliceStable(fields, func(i, k int) bool {
+		return fields[i].Key < fields[k].Key
+	})
+}
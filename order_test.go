@@ -0,0 +1,31 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestSortFields(t *testing.T) {
+	var sb strings.Builder
+	lg := New(": ord:", &sb, Sinfo)
+	lg.SetFormat(Fjson)
+	ctx := WithScope(context.Background(), Any("user", 1), Any("req", 2))
+
+	// bound fields first, even if context is not first
+	lg.Log(Sinfo, "a", Any("z", 1), Any("b", 2), ctx, Any("b", 3))
+	lg.SetSortFields(true)
+	lg.Log(Sinfo, "a", Any("z", 1), Any("b", 2), ctx, Any("b", 3))
+
+	lines := strings.Split(sb.String(), "\n")
+	if !strings.Contains(lines[0], `"user":1,"req":2,"z":1,"b":2,"b":3}`) ||
+		!strings.Contains(lines[1], `"req":2,"user":1,"b":2,"b":3,"z":1}`) {
+		t.Fatal("bad field order", sb.String())
+	}
+}
@@ -0,0 +1,106 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import "strconv"
+
+// OpenTelemetry severity texts & numbers of yell severities
+var (
+	otelSeverity = [...]string{"DEBUG", "INFO", "WARN", "ERROR", "FATAL"}
+	otelNumber   = [...]string{"5", "9", "13", "17", "21"}
+)
+
+// OTelEncoder writes JSON records with OpenTelemetry log data model & semantic convention
+// names, for consistency with traces & metrics:
+//
+//	{"Timestamp":1616967933591948000,"SeverityText":"WARN","SeverityNumber":13,
+//	 "InstrumentationScope":{"Name":"mypkg"},"Body":"some warning",
+//	 "Attributes":{"code.filepath":"myApp.go","code.lineno":15,"key":"value"}}
+//
+// Timestamp is nanoseconds since Unix epoch. Fields named "trace" & "span" go to TraceId &
+// SpanId, other fields are attributes. Error members of message list are
+// exception.message & exception.type (arrays with an entry per error if there are
+// multiple errors), error details are exception.stacktrace. Sequence number & identifier
+// (if enabled) are log.record.seq & log.record.uid attributes.
+type OTelEncoder struct{}
+
+// Encode appends OpenTelemetry record to buf
+func (OTelEncoder) Encode(buf []byte, r *Record) ([]byte, error) {
+	buf = append(buf, `{"Timestamp":`...)
+	buf = strconv.AppendInt(buf, r.Time.UnixNano(), 10)
+	buf = append(buf, `,"SeverityText":"`...)
+	buf = append(buf, otelSeverity[r.Level]...)
+	buf = append(buf, `","SeverityNumber":`...)
+	buf = append(buf, otelNumber[r.Level]...)
+
+	var err error
+	for _, f := range r.Fields {
+		switch f.Key {
+		case "trace":
+			buf = append(buf, `,"TraceId":`...)
+		case "span":
+			buf = append(buf, `,"SpanId":`...)
+		default:
+			continue
+		}
+		if buf, err = appendJSONField(buf, f); err != nil {
+			return buf, err
+		}
+	}
+
+	buf = append(buf, `,"InstrumentationScope":{"Name":`...)
+	buf = appendJSONString(buf, r.Name)
+	buf = append(buf, `},"Body":`...)
+	buf = appendJSONString(buf, r.Msg)
+
+	buf = append(buf, `,"Attributes":{`...)
+	n := len(buf) // to detect first attribute
+	if r.File != "" {
+		buf = append(buf, `"code.filepath":`...)
+		buf = appendJSONString(buf, r.File)
+		buf = append(buf, `,"code.lineno":`...)
+		buf = strconv.AppendInt(buf, int64(r.Line), 10)
+	}
+	if r.Seq != 0 {
+		buf = otelKey(buf, n, "log.record.seq")
+		buf = strconv.AppendUint(buf, r.Seq, 10)
+	}
+	if r.ID != "" {
+		buf = otelKey(buf, n, "log.record.uid")
+		buf = appendJSONString(buf, r.ID)
+	}
+	if len(r.Errs) > 0 {
+		buf = otelKey(buf, n, "exception.message")
+		buf = appendErrorList(buf, r.Errs, errorMsg)
+		buf = otelKey(buf, n, "exception.type")
+		buf = appendErrorList(buf, r.Errs, errorType)
+	}
+	if r.Detail != "" {
+		buf = otelKey(buf, n, "exception.stacktrace")
+		buf = appendJSONString(buf, r.Detail)
+	}
+
+	for _, f := range r.Fields {
+		if f.Key == "trace" || f.Key == "span" {
+			continue
+		}
+		buf = otelKey(buf, n, f.Key)
+		if buf, err = appendJSONField(buf, f); err != nil {
+			return buf, err
+		}
+	}
+	return append(buf, "}}\n"...), nil
+}
+
+// otelKey appends attribute key & colon to buf, preceded by a comma unless buf has n bytes
+func otelKey(buf []byte, n int, key string) []byte {
+	if len(buf) > n {
+		buf = append(buf, ',')
+	}
+	buf = appendJSONString(buf, key)
+	return append(buf, ':')
+}
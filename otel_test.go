@@ -0,0 +1,54 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOTelEncoder(t *testing.T) {
+	r := Record{Time: time.Date(2021, 3, 28, 21, 45, 33, 591948000, time.UTC),
+		Level: Swarn, Name: "mypkg", File: "a.go", Line: 15, Msg: "slow",
+		Fields: []Field{Any("trace", "abc"), Any("n", 3), Any("span", "12")}}
+
+	b, err := OTelEncoder{}.Encode(nil, &r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"Timestamp":1616967933591948000,"SeverityText":"WARN","SeverityNumber":13,` +
+		`"TraceId":"abc","SpanId":"12","InstrumentationScope":{"Name":"mypkg"},"Body":"slow",` +
+		`"Attributes":{"code.filepath":"a.go","code.lineno":15,"n":3}}` + "\n"
+	if string(b) != want {
+		t.Fatalf("unexpected record:\n%s%s", b, want)
+	}
+
+	r = Record{Level: Sfatal, Msg: "failed", Errs: []error{errors.New("boom")},
+		Detail: "stack", Seq: 7}
+	b, _ = OTelEncoder{}.Encode(nil, &r)
+	var m struct {
+		SeverityNumber int
+		Attributes     map[string]interface{}
+	}
+	if err = json.Unmarshal(b, &m); err != nil || m.SeverityNumber != 21 ||
+		m.Attributes["exception.message"] != "boom" || m.Attributes["log.record.seq"] != 7.0 ||
+		m.Attributes["exception.stacktrace"] != "stack" || len(m.Attributes) != 4 {
+		t.Fatal("unexpected record:", string(b), err)
+	}
+
+	r.Errs = append(r.Errs, fmt.Errorf("open: %w", r.Errs[0]))
+	b, _ = OTelEncoder{}.Encode(nil, &r)
+	want = `"exception.message":["boom","open: boom"],` +
+		`"exception.type":["*errors.errorString","*fmt.wrapError"]`
+	if !strings.Contains(string(b), want) {
+		t.Fatal("unexpected record:", string(b))
+	}
+}
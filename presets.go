@@ -0,0 +1,33 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"os"
+	"time"
+)
+
+// NewDevelopment creates a Logger with name (like ": mypkg:" or mypkg, see NewE) for local
+// development: text records with colors (if a terminal) to os.Stderr, debug level, error
+// details and panicking DPanic. Panics if name is invalid.
+func NewDevelopment(name string) Logger {
+	lg := New(name, os.Stderr, Sdebug)
+	lg.SetColor(Cauto, true)
+	lg.SetErrorDetail(true)
+	lg.SetDevelopment(true)
+	return lg
+}
+
+// NewProduction creates a Logger with name (like ": mypkg:" or mypkg, see NewE) for
+// production services: JSON records to os.Stdout, info level and sampling of the first
+// 100 records per severity each second, then every 100th. Panics if name is invalid.
+func NewProduction(name string) Logger {
+	lg := New(name, os.Stdout, Sinfo)
+	lg.SetFormat(Fjson)
+	lg.SetSampling(100, 100, time.Second)
+	return lg
+}
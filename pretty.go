@@ -0,0 +1,235 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PrettyMode selects rendering of struct, map, slice & array members of message lists
+type PrettyMode uint32
+
+// pretty modes
+const (
+	Pnone PrettyMode = iota // default fmt verbs, default
+	Pplus                   // %+v, with struct field names
+	Pdeep                   // multi-line representation, see Pretty
+)
+
+// maximum nesting depth of Pretty
+const prettyDepth = 10
+
+// SetPretty sets rendering of struct, map, slice & array members (or pointers to them) of
+// message lists of records at or below maxLevel, typically Sdebug for diagnostics:
+//
+//	lg.SetPretty(yell.Pdeep, yell.Sdebug)
+//	lg.SetIndent(true) // keep multi-line renderings under record header
+//
+// Members implementing error or fmt.Stringer keep their own formatting. Invalid modes
+// disable pretty printing. It should be called before Logger is used.
+func (lg *Logger) SetPretty(mode PrettyMode, maxLevel Severity) {
+	if mode > Pdeep {
+		mode = Pnone
+	}
+	lg.pretty, lg.prettyMax = mode, maxLevel
+}
+
+// prettify returns msg with composite members rendered in mode, msg is copied if
+// necessary
+func prettify(msg []interface{}, mode PrettyMode) []interface{} {
+	copied := false
+	for i, m := range msg {
+		if !composite(m) {
+			continue
+		}
+		if !copied {
+			msg = append([]interface{}(nil), msg...)
+			copied = true
+		}
+		if mode == Pplus {
+			msg[i] = fmt.Sprintf("%+v", m)
+		} else {
+			msg[i] = Pretty(m)
+		}
+	}
+	return msg
+}
+
+// composite checks if m is a struct, map, slice or array (or pointer to them) without its
+// own formatting
+func composite(m interface{}) bool {
+	switch m.(type) {
+	case nil, Field, error, fmt.Stringer, string, []byte:
+		return false
+	}
+	t := reflect.TypeOf(m)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		return true
+	}
+	return false
+}
+
+// Pretty returns a multi-line Go-like representation of v, with one struct field, map
+// entry or element per tab-indented line, and map keys sorted:
+//
+//	main.Config{
+//		Port: 80,
+//		Tags: []string{
+//			"a",
+//		},
+//	}
+//
+// Unexported fields are included. Errors & fmt.Stringers are rendered as quoted strings.
+// Cyclic pointers are rendered as <cycle>, values nested too deep as ....
+func Pretty(v interface{}) string {
+	p := printer{visited: map[uintptr]bool{}}
+	p.value(reflect.ValueOf(v), 0)
+	return p.String()
+}
+
+// printer renders values for Pretty
+type printer struct {
+	strings.Builder
+	visited map[uintptr]bool // pointers in progress
+}
+
+// newline starts a new line with depth tabs
+func (p *printer) newline(depth int) {
+	p.WriteByte('\n')
+	for ; depth > 0; depth-- {
+		p.WriteByte('\t')
+	}
+}
+
+// value renders v at nesting depth
+func (p *printer) value(v reflect.Value, depth int) {
+	if !v.IsValid() {
+		p.WriteString("nil")
+		return
+	}
+	if v.CanInterface() {
+		switch x := v.Interface().(type) {
+		case error:
+			if v.Kind() != reflect.Ptr || !v.IsNil() {
+				p.WriteString(strconv.Quote(x.Error()))
+				return
+			}
+		case fmt.Stringer:
+			if v.Kind() != reflect.Ptr || !v.IsNil() {
+				p.WriteString(strconv.Quote(x.String()))
+				return
+			}
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		p.WriteString(strconv.FormatBool(v.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		p.WriteString(strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		p.WriteString(strconv.FormatUint(v.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		p.WriteString(strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()))
+	case reflect.Complex64, reflect.Complex128:
+		p.WriteString(strconv.FormatComplex(v.Complex(), 'g', -1, v.Type().Bits()))
+	case reflect.String:
+		p.WriteString(strconv.Quote(v.String()))
+
+	case reflect.Ptr:
+		if v.IsNil() {
+			p.WriteString("nil")
+			return
+		}
+		ptr := v.Pointer()
+		if p.visited[ptr] {
+			p.WriteString("<cycle>")
+			return
+		}
+		p.visited[ptr] = true
+		p.WriteByte('&')
+		p.value(v.Elem(), depth)
+		delete(p.visited, ptr)
+	case reflect.Interface:
+		p.value(v.Elem(), depth)
+
+	case reflect.Struct:
+		p.composite(v, depth, v.NumField(), func(i int) {
+			p.WriteString(v.Type().Field(i).Name)
+			p.WriteString(": ")
+			p.value(v.Field(i), depth+1)
+		})
+	case reflect.Map:
+		if v.IsNil() {
+			p.WriteString("nil")
+			return
+		}
+		keys := v.MapKeys()
+		names := make([]string, len(keys))
+		for i, k := range keys {
+			names[i] = fmt.Sprint(k)
+		}
+		sort.Sort(byName{keys, names})
+		p.composite(v, depth, len(keys), func(i int) {
+			p.value(keys[i], depth+1)
+			p.WriteString(": ")
+			p.value(v.MapIndex(keys[i]), depth+1)
+		})
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			p.WriteString("nil")
+			return
+		}
+		p.composite(v, depth, v.Len(), func(i int) {
+			p.value(v.Index(i), depth+1)
+		})
+	default: // chan, func, unsafe pointer
+		p.WriteString(v.Type().String())
+	}
+}
+
+// composite renders n members of v with member at nesting depth
+func (p *printer) composite(v reflect.Value, depth, n int, member func(int)) {
+	p.WriteString(v.Type().String())
+	p.WriteByte('{')
+	if n == 0 {
+		p.WriteByte('}')
+		return
+	}
+	if depth >= prettyDepth {
+		p.WriteString("...}")
+		return
+	}
+	for i := 0; i < n; i++ {
+		p.newline(depth + 1)
+		member(i)
+		p.WriteByte(',')
+	}
+	p.newline(depth)
+	p.WriteByte('}')
+}
+
+// byName sorts map keys by their names
+type byName struct {
+	keys  []reflect.Value
+	names []string
+}
+
+func (b byName) Len() int           { return len(b.keys) }
+func (b byName) Less(i, k int) bool { return b.names[i] < b.names[k] }
+func (b byName) Swap(i, k int) {
+	b.keys[i], b.keys[k] = b.keys[k], b.keys[i]
+	b.names[i], b.names[k] = b.names[k], b.names[i]
+}
@@ -0,0 +1,79 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+)
+
+type prettyNode struct {
+	Name  string
+	next  *prettyNode
+	Tags  []string
+	Attrs map[string]interface{}
+	Err   error
+	When  time.Duration
+}
+
+func TestPretty(t *testing.T) {
+	n := &prettyNode{Name: "a", Tags: []string{"x"}, Attrs: map[string]interface{}{
+		"z": 1.5, "b": []int{}}, Err: errors.New("e"), When: time.Second}
+	n.next = n
+
+	want := `&yell.prettyNode{
+	Name: "a",
+	next: <cycle>,
+	Tags: []string{
+		"x",
+	},
+	Attrs: map[string]interface {}{
+		"b": []int{},
+		"z": 1.5,
+	},
+	Err: "e",
+	When: "1s",
+}`
+	if got := Pretty(n); got != want {
+		t.Fatalf("bad rendering:\n%s\n%s", got, want)
+	}
+	if Pretty(nil) != "nil" || Pretty([]int(nil)) != "nil" || Pretty(map[int]bool(nil)) != "nil" ||
+		Pretty(make(chan int)) != "chan int" || Pretty(uint8(7)) != "7" || Pretty(true) != "true" {
+		t.Fatal("bad leaf rendering")
+	}
+
+	var deep interface{} = 0
+	for i := 0; i < 2*prettyDepth; i++ {
+		deep = []interface{}{deep}
+	}
+	if !strings.Contains(Pretty(deep), "[]interface {}{...}") {
+		t.Fatal("depth not limited")
+	}
+}
+
+func TestSetPretty(t *testing.T) {
+	var recs []Record
+	lg := New(": pr:", ioutil.Discard, Sdebug)
+	lg.AddObserver(func(r Record) { recs = append(recs, r) })
+	v := struct{ A, B int }{1, 2}
+
+	lg.SetPretty(Pplus, Sdebug)
+	lg.Log(Sdebug, "v", v, 3, "s", errors.New("e"))
+	lg.Log(Sinfo, "v", v)
+	lg.SetPretty(Pdeep, Sinfo)
+	lg.Log(Sinfo, "v", &v)
+	lg.SetPretty(7, Sfatal)
+	lg.Log(Sinfo, "v", v)
+
+	if len(recs) != 4 || recs[0].Text() != "v {A:1 B:2} 3 s e" || recs[1].Msg != "v {1 2}" ||
+		recs[2].Msg != "v &struct { A int; B int }{\n\tA: 1,\n\tB: 2,\n}" || recs[3].Msg != "v {1 2}" {
+		t.Fatal("unexpected records:", recs)
+	}
+}
@@ -0,0 +1,102 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"fmt"
+	"time"
+)
+
+// Field is a key-value pair attached to a record. Typed fields (see String) keep their
+// values outside Value, see Interface.
+type Field struct {
+	Key   string
+	Value interface{}
+
+	kind fieldKind // of typed value in num or str
+	num  int64
+	str  string
+}
+
+// Any creates a Field. Fields can be anywhere in message lists:
+//
+//	yell.Warn("slow request", yell.Any("path", path), yell.Any("took", dur))
+func Any(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Record is a structured log record
+type Record struct {
+	Time   time.Time // local or UTC time, see UTC
+	Level  Severity  // severity of record
+	Name   string    // logger name like mypkg
+	File   string    // request location file name, empty if unknown
+	Line   int       // request location line number
+	Msg    string    // message list formatted like fmt.Sprintln, without newline & Errs
+	Errs   []error   // error members of message list, see Text
+	Fields []Field   // fields in message list
+	Seq    uint64    // sequence number, zero if disabled, see SetSequence
+	ID     string    // unique identifier, empty if disabled, see SetID
+	Detail string    // error details (stack traces, causes), see SetErrorDetail
+
+	text   string     // message list with Errs in place as text, set by Log if there are Errs
+	tmode  TimeMode   // rendering of Time, see AppendTime
+	tcache *timeCache // of Logger, see SetTimeCache
+	delims *[2]string // of Logger name, nil means ": " & ":", see SetNameDelims
+}
+
+// Observer inspects records of a Logger. Observers are called synchronously by Log for
+// every record severe enough to be logged, so they should be quick.
+type Observer func(Record)
+
+// AddObserver adds obs to Logger's observers. It should be called before Logger is used.
+func (lg *Logger) AddObserver(obs Observer) {
+	if obs != nil {
+		lg.observers = append(lg.observers, obs)
+	}
+}
+
+// splitFields formats non-Field members of msg like fmt.Sprintln without newline,
+// and returns Field members separately.
+func splitFields(msg []interface{}) (string, []Field) {
+	var (
+		fields []Field
+		rest   []interface{}
+	)
+	for i, m := range msg {
+		f, ok := m.(Field)
+		if !ok {
+			if fields != nil {
+				rest = append(rest, m)
+			}
+			continue
+		}
+		if fields == nil { // first field, copy preceding members
+			rest = append(make([]interface{}, 0, len(msg)), msg[:i]...)
+		}
+		fields = append(fields, f)
+	}
+
+	if fields == nil {
+		rest = msg // no fields
+	}
+	return sprintln(rest), fields
+}
+
+// sprintln formats msg like fmt.Sprintln without newline
+func sprintln(msg []interface{}) string {
+	switch len(msg) {
+	case 0:
+		return ""
+	case 1:
+		if s, ok := msg[0].(string); ok {
+			return s
+		}
+	}
+	s := fmt.Sprintln(msg...)
+	return s[:len(s)-1]
+}
@@ -0,0 +1,37 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+func TestObserver(t *testing.T) {
+	var recs []Record
+	lg := New(": obs:", ioutil.Discard, Swarn)
+	lg.AddObserver(nil) // ignored
+	lg.AddObserver(func(r Record) {
+		recs = append(recs, r)
+	})
+
+	if err := lg.Log(Sinfo, "ignored"); err != nil {
+		t.Fatal(err)
+	}
+	if err := lg.Log(Serror, "some", 2); err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 1 {
+		t.Fatal("must observe one record")
+	}
+
+	r := recs[0]
+	if r.Time.IsZero() || r.Level != Serror || r.Name != "obs" || r.File != "testing.go" ||
+		r.Line <= 0 || r.Msg != "some 2" {
+		t.Fatal("unexpected record:", r)
+	}
+}
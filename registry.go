@@ -0,0 +1,145 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// registry of Loggers by name
+var registry struct {
+	sync.RWMutex
+	loggers map[string]*Logger
+	rules   []levelRule // see SetLevels
+}
+
+// levelRule sets level of Loggers whose names match pattern
+type levelRule struct {
+	pattern string
+	level   Severity
+}
+
+// Register adds lg to the global registry under its name (like mypkg), replacing any
+// Logger registered with the same name. Registered Loggers can be managed at runtime,
+// for example by yellhttp.LevelHandler. lg must stay valid, typically a package variable:
+//
+//	var Logger = yell.New(": mypkg:", os.Stdout, yell.Swarn)
+//
+//	func init() {
+//		yell.Register(&Logger)
+//	}
+func Register(lg *Logger) {
+	registry.Lock()
+	if registry.loggers == nil {
+		registry.loggers = make(map[string]*Logger)
+	}
+	name := lg.name
+	registry.loggers[name] = lg
+	applyRules(name, lg)
+	registry.Unlock()
+}
+
+// applyRules sets level of lg with the first matching rule, registry must be locked
+func applyRules(name string, lg *Logger) {
+	for _, r := range registry.rules {
+		if ok, _ := path.Match(r.pattern, name); ok {
+			lg.SetLevel(r.level)
+			return
+		}
+	}
+}
+
+// SetLevels configures levels of registered Loggers by name patterns (see path.Match)
+// with a comma separated list of pattern=level rules, like:
+//
+//	yell.SetLevels("net.*=debug, db=info, *=warn")
+//
+// Each Logger gets the level of the first rule matching its name, Loggers matching no
+// rule are unchanged. Rules are kept and also applied to Loggers registered later, an
+// empty spec clears them. Returns an error without changing anything if spec is invalid.
+func SetLevels(spec string) error {
+	var rules []levelRule
+	for _, rule := range strings.Split(spec, ",") {
+		if rule = strings.TrimSpace(rule); rule == "" {
+			continue
+		}
+		i := strings.LastIndexByte(rule, '=')
+		if i < 0 {
+			return fmt.Errorf("yell: invalid level rule %q", rule)
+		}
+		pattern := strings.TrimSpace(rule[:i])
+		if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+			return fmt.Errorf("yell: invalid pattern in level rule %q", rule)
+		}
+		level, err := ParseSeverity(rule[i+1:])
+		if err != nil {
+			return fmt.Errorf("yell: invalid level rule %q: %w", rule, err)
+		}
+		rules = append(rules, levelRule{pattern, level})
+	}
+
+	registry.Lock()
+	registry.rules = rules
+	for name, lg := range registry.loggers {
+		applyRules(name, lg)
+	}
+	registry.Unlock()
+	return nil
+}
+
+// Lookup returns registered Logger with name, or nil
+func Lookup(name string) *Logger {
+	registry.RLock()
+	defer registry.RUnlock()
+	return registry.loggers[name]
+}
+
+// Names returns names of registered Loggers in order
+func Names() []string {
+	registry.RLock()
+	list := make([]string, 0, len(registry.loggers))
+	for name := range registry.loggers {
+		list = append(list, name)
+	}
+	registry.RUnlock()
+
+	sort.Strings(list)
+	return list
+}
+
+// SetLevelFor sets minimum severity level of registered Logger with name, like:
+//
+//	yell.SetLevelFor("mypkg", yell.Sdebug)
+//
+// Returns false if there is no such Logger.
+func SetLevelFor(name string, level Severity) bool {
+	lg := Lookup(name)
+	if lg == nil {
+		return false
+	}
+	lg.SetLevel(level)
+	return true
+}
+
+// Loggers returns registered Loggers sorted by name
+func Loggers() []*Logger {
+	registry.RLock()
+	list := make([]*Logger, 0, len(registry.loggers))
+	for _, lg := range registry.loggers {
+		list = append(list, lg)
+	}
+	registry.RUnlock()
+
+	sort.Slice(list, func(i, k int) bool {
+		return list[i].name < list[k].name
+	})
+	return list
+}
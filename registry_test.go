@@ -0,0 +1,83 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	lg1 := New(": reg1:", ioutil.Discard, Sinfo)
+	lg2 := New(": reg0:", ioutil.Discard, Sinfo)
+	Register(&lg1)
+	Register(&lg2)
+
+	if Lookup("reg1") != &lg1 || Lookup("none") != nil {
+		t.Fatal("unexpected lookup")
+	}
+
+	var names []string
+	for _, lg := range Loggers() {
+		names = append(names, lg.name)
+	}
+	i := 0
+	for ; i < len(names) && names[i] != "reg0"; i++ {
+	}
+	if i+1 >= len(names) || names[i+1] != "reg1" {
+		t.Fatal("must be sorted:", names)
+	}
+
+	names = Names()
+	i = 0
+	for ; i < len(names) && names[i] != "reg0"; i++ {
+	}
+	if i+1 >= len(names) || names[i+1] != "reg1" {
+		t.Fatal("names must be sorted:", names)
+	}
+
+	if !SetLevelFor("reg1", Sdebug) || lg1.GetLevel() != Sdebug || lg2.GetLevel() != Sinfo {
+		t.Fatal("must set level of reg1")
+	}
+	if SetLevelFor("none", Sdebug) {
+		t.Fatal("must fail for unregistered name")
+	}
+}
+
+func TestSetLevels(t *testing.T) {
+	net := New(": net.http:", ioutil.Discard, Sinfo)
+	db := New(": db:", ioutil.Discard, Sinfo)
+	Register(&net)
+	Register(&db)
+
+	for _, spec := range [...]string{"net.*", "[=info", "*=loud", "=warn"} {
+		if SetLevels(spec) == nil {
+			t.Fatal("must reject spec:", spec)
+		}
+	}
+	if net.GetLevel() != Sinfo || db.GetLevel() != Sinfo {
+		t.Fatal("invalid spec must not change levels")
+	}
+
+	if err := SetLevels("net.*=debug, db=error:, "); err != nil {
+		t.Fatal(err)
+	}
+	if net.GetLevel() != Sdebug || db.GetLevel() != Serror {
+		t.Fatal("unexpected levels:", net.GetLevel(), db.GetLevel())
+	}
+
+	// rules apply to later registrations
+	net2 := New(": net.rpc:", ioutil.Discard, Swarn)
+	Register(&net2)
+	if net2.GetLevel() != Sdebug {
+		t.Fatal("rule must apply to new Logger")
+	}
+
+	if err := SetLevels(""); err != nil || net.GetLevel() != Sdebug {
+		t.Fatal("clearing rules must keep levels")
+	}
+}
@@ -0,0 +1,73 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"io"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// RetryWriter retries failed writes to network-backed writers with exponential backoff
+// & jitter before a record is declared lost. Partially written records are resumed.
+// Writes are serialized, so records are not reordered.
+type RetryWriter struct {
+	mu       sync.Mutex
+	writer   io.Writer
+	retries  int
+	min, max time.Duration
+	lost     func(record []byte, err error)
+}
+
+// NewRetryWriter creates a RetryWriter for writer, which retries a failed write up to
+// retries times. Delay before the first retry is min, doubled for each retry up to max,
+// and randomized to half to full delay. Optional lost callback is called with records
+// that could not be written and the last error; it must not retain record. Panics if
+// writer is nil, retries is negative or delays are invalid.
+func NewRetryWriter(writer io.Writer, retries int, min, max time.Duration,
+	lost func(record []byte, err error)) *RetryWriter {
+
+	if writer == nil || retries < 0 || min <= 0 || max < min {
+		panic("yell: invalid arguments to NewRetryWriter")
+	}
+	return &RetryWriter{writer: writer, retries: retries, min: min, max: max, lost: lost}
+}
+
+// Write writes p to underlying writer, retrying on errors. Returns the last error if p
+// could not be written.
+func (rw *RetryWriter) Write(p []byte) (n int, err error) {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+
+	delay := rw.min
+	for try := 0; ; try++ {
+		var k int
+		k, err = rw.writer.Write(p[n:])
+		n += k
+		if err == nil && n >= len(p) {
+			return len(p), nil
+		}
+		if err == nil {
+			err = io.ErrShortWrite
+		}
+		if try >= rw.retries {
+			break
+		}
+
+		// sleep for half to full delay
+		time.Sleep(delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1)))
+		if delay *= 2; delay > rw.max {
+			delay = rw.max
+		}
+	}
+
+	if rw.lost != nil {
+		rw.lost(p, err)
+	}
+	return
+}
@@ -0,0 +1,57 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// flakyWriter fails first fails writes, writing half of the input
+type flakyWriter struct {
+	fails int
+	sb    strings.Builder
+}
+
+func (f *flakyWriter) Write(p []byte) (int, error) {
+	if f.fails > 0 {
+		f.fails--
+		n, _ := f.sb.Write(p[:len(p)/2])
+		return n, errWrite
+	}
+	return f.sb.Write(p)
+}
+
+func TestRetryWriter(t *testing.T) {
+	f := &flakyWriter{fails: 2}
+	var lost []string
+	rw := NewRetryWriter(f, 2, time.Millisecond, 2*time.Millisecond,
+		func(rec []byte, err error) {
+			if !errors.Is(err, errWrite) {
+				t.Error("unexpected error:", err)
+			}
+			lost = append(lost, string(rec))
+		})
+	lg := New(": rty:", rw, Sinfo)
+
+	if err := lg.Log(Sinfo, "resumed"); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(f.sb.String(), "\n") != 1 || !strings.HasSuffix(f.sb.String(), " resumed\n") {
+		t.Fatalf("unexpected output: %q", f.sb.String())
+	}
+
+	f.fails = 3
+	if err := lg.Log(Sinfo, "lost"); !errors.Is(err, errWrite) {
+		t.Fatal("must fail", err)
+	}
+	if len(lost) != 1 || !strings.HasSuffix(lost[0], " lost\n") {
+		t.Fatal("must report lost record:", lost)
+	}
+}
@@ -0,0 +1,117 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"io"
+	"sync"
+)
+
+// Ring is an io.Writer that retains the last N records in memory, so detailed recent
+// history can be emitted only when something goes wrong, see SetRing & DumpTo. Each Write
+// must be a complete record, as Logger does. It is safe for concurrent use.
+type Ring struct {
+	mu   sync.Mutex
+	recs [][]byte // circular, buffers are reused
+	next int      // index of next record
+	full bool     // all records are in use
+}
+
+// NewRing creates a Ring retaining last n records. Panics if n is not positive.
+func NewRing(n int) *Ring {
+	if n <= 0 {
+		panic("yell: Ring size must be positive")
+	}
+	return &Ring{recs: make([][]byte, n)}
+}
+
+// Write stores a copy of record p, evicting the oldest record if Ring is full
+func (rg *Ring) Write(p []byte) (int, error) {
+	rg.mu.Lock()
+	rg.recs[rg.next] = append(rg.recs[rg.next][:0], p...)
+	if rg.next++; rg.next == len(rg.recs) {
+		rg.next, rg.full = 0, true
+	}
+	rg.mu.Unlock()
+	return len(p), nil
+}
+
+// Len returns number of retained records
+func (rg *Ring) Len() int {
+	rg.mu.Lock()
+	defer rg.mu.Unlock()
+	if rg.full {
+		return len(rg.recs)
+	}
+	return rg.next
+}
+
+// Reset discards retained records
+func (rg *Ring) Reset() {
+	rg.mu.Lock()
+	rg.next, rg.full = 0, false
+	rg.mu.Unlock()
+}
+
+// snapshot returns a copy of retained records, oldest first
+func (rg *Ring) snapshot() []byte {
+	rg.mu.Lock()
+	defer rg.mu.Unlock()
+
+	var buf []byte
+	if rg.full {
+		for _, r := range rg.recs[rg.next:] {
+			buf = append(buf, r...)
+		}
+	}
+	for _, r := range rg.recs[:rg.next] {
+		buf = append(buf, r...)
+	}
+	return buf
+}
+
+// drain returns copies of retained records (oldest first) and discards them
+func (rg *Ring) drain() [][]byte {
+	rg.mu.Lock()
+	defer rg.mu.Unlock()
+
+	var list [][]byte
+	if rg.full {
+		for _, r := range rg.recs[rg.next:] {
+			list = append(list, append([]byte(nil), r...))
+		}
+	}
+	for _, r := range rg.recs[:rg.next] {
+		list = append(list, append([]byte(nil), r...))
+	}
+	rg.next, rg.full = 0, false
+	return list
+}
+
+// DumpTo writes retained records to w (oldest first) without discarding them, for
+// example from a crash handler. Ring is not locked while writing to w.
+func (rg *Ring) DumpTo(w io.Writer) (int64, error) {
+	n, err := w.Write(rg.snapshot())
+	return int64(n), err
+}
+
+// SetRing makes Logger write every record to rg, including records below minimum
+// severity (see SetLevel) and records dropped by sampling, which are otherwise not
+// logged. Records are encoded with Logger's Encoder, so a dump looks like the regular
+// log. nil disables it, which is the default. It should be called before Logger is used.
+//
+//	ring := yell.NewRing(1000)
+//	lg.SetRing(ring)
+//	defer func() {
+//		if r := recover(); r != nil {
+//			ring.DumpTo(os.Stderr)
+//			panic(r)
+//		}
+//	}()
+func (lg *Logger) SetRing(rg *Ring) {
+	lg.ring = rg
+}
@@ -0,0 +1,88 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRing(t *testing.T) {
+	rg := NewRing(3)
+	var buf bytes.Buffer
+	if n, err := rg.DumpTo(&buf); n != 0 || err != nil || rg.Len() != 0 {
+		t.Fatal("empty ring dump")
+	}
+
+	for _, s := range []string{"a\n", "b\n"} {
+		rg.Write([]byte(s))
+	}
+	if rg.DumpTo(&buf); buf.String() != "a\nb\n" || rg.Len() != 2 {
+		t.Fatal("bad dump", buf.String())
+	}
+	for _, s := range []string{"c\n", "d\n", "e\n"} {
+		rg.Write([]byte(s))
+	}
+	buf.Reset()
+	if rg.DumpTo(&buf); buf.String() != "c\nd\ne\n" || rg.Len() != 3 {
+		t.Fatal("bad dump", buf.String())
+	}
+	rg.Reset()
+	if rg.Len() != 0 || len(rg.drain()) != 0 {
+		t.Fatal("reset failed")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("NewRing(0) must panic")
+		}
+	}()
+	NewRing(0)
+}
+
+func TestSetRing(t *testing.T) {
+	var out, dump bytes.Buffer
+	lg := New(": rg:", &out, Swarn)
+	rg := NewRing(10)
+	lg.SetRing(rg)
+
+	var observed int
+	lg.AddObserver(func(Record) { observed++ })
+
+	lg.Log(Sdebug, "debug detail")
+	lg.Log(Sinfo, "info detail")
+	lg.Log(Serror, "failure")
+
+	if strings.Count(out.String(), "\n") != 1 || observed != 1 || lg.Count(Sinfo) != 0 {
+		t.Fatal("records below minimum severity logged", out.String())
+	}
+	rg.DumpTo(&dump)
+	lines := strings.Split(strings.TrimSuffix(dump.String(), "\n"), "\n")
+	if len(lines) != 3 || !strings.Contains(lines[0], "rg:debug: testing.go:") ||
+		!strings.HasSuffix(lines[0], "debug detail") ||
+		!strings.HasSuffix(lines[2], "failure") {
+		t.Fatal("bad ring dump", dump.String())
+	}
+
+	// sampled records go to ring only
+	lg.SetSampling(1, 0, time.Hour)
+	lg.Log(Swarn, "w1")
+	lg.Log(Swarn, "w2")
+	if strings.Contains(out.String(), "w2") || !strings.HasSuffix(string(rg.snapshot()), "w2\n") {
+		t.Fatal("sampled record", out.String())
+	}
+
+	// ignored levels
+	lg.SetLevel(Snolog)
+	lg.SetMaxLevel(Swarn)
+	lg.Log(Serror, "above max")
+	if rg.Len() != 5 {
+		t.Fatal("record above max level retained")
+	}
+}
@@ -0,0 +1,68 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// sampler limits records per severity in each tick, shared by copies of Logger
+type sampler struct {
+	first, thereafter uint64
+	tick              int64 // nanoseconds
+
+	window  int64          // start of current tick in Unix nanoseconds, accessed atomically
+	counts  [Serror]uint64 // records in current tick, accessed atomically
+	dropped uint64         // accessed atomically
+}
+
+// SetSampling limits high-volume records: in each tick, the first records of each
+// severity are logged, and thereafter every thereafter-th record (none if thereafter is
+// zero). Error & fatal records are never sampled. Non-positive first or tick disables
+// sampling, which is the default. It should be called before Logger is used.
+func (lg *Logger) SetSampling(first, thereafter int, tick time.Duration) {
+	if first <= 0 || tick <= 0 {
+		lg.sampler = nil
+		return
+	}
+	if thereafter < 0 {
+		thereafter = 0
+	}
+	lg.sampler = &sampler{first: uint64(first), thereafter: uint64(thereafter),
+		tick: int64(tick)}
+}
+
+// Sampled returns number of records dropped by sampling
+func (lg *Logger) Sampled() uint64 {
+	if lg.sampler == nil {
+		return 0
+	}
+	return atomic.LoadUint64(&lg.sampler.dropped)
+}
+
+// allow decides if a record with level at time now is logged
+func (s *sampler) allow(level Severity, now time.Time) bool {
+	if level >= Serror {
+		return true
+	}
+
+	ns := now.UnixNano()
+	if w := atomic.LoadInt64(&s.window); ns-w >= s.tick &&
+		atomic.CompareAndSwapInt64(&s.window, w, ns) {
+		for i := range s.counts {
+			atomic.StoreUint64(&s.counts[i], 0) // new tick
+		}
+	}
+
+	n := atomic.AddUint64(&s.counts[level], 1)
+	if n <= s.first || s.thereafter > 0 && (n-s.first)%s.thereafter == 0 {
+		return true
+	}
+	atomic.AddUint64(&s.dropped, 1)
+	return false
+}
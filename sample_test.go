@@ -0,0 +1,51 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestSampling(t *testing.T) {
+	lg := New(": sample:", ioutil.Discard, Sdebug)
+	lg.SetSampling(3, 5, time.Hour)
+
+	for i := 0; i < 20; i++ {
+		lg.Log(Sinfo, "info")
+		lg.Log(Serror, "error")
+	}
+	// 3 first, then 8th, 13th, 18th
+	if lg.Count(Sinfo) != 6 || lg.Sampled() != 14 || lg.Count(Serror) != 20 {
+		t.Fatal("unexpected counts:", lg.Count(Sinfo), lg.Sampled(), lg.Count(Serror))
+	}
+
+	// new tick
+	lg.sampler.window -= int64(time.Hour)
+	lg.Log(Sinfo, "info")
+	if lg.Count(Sinfo) != 7 {
+		t.Fatal("new tick must reset counts")
+	}
+
+	lg.SetSampling(0, 0, 0)
+	if lg.sampler != nil || lg.Sampled() != 0 {
+		t.Fatal("must disable sampling")
+	}
+}
+
+func TestPresets(t *testing.T) {
+	dev := NewDevelopment(": dev:")
+	if dev.GetLevel() != Sdebug || !dev.detail || dev.colorMode != Cauto || !dev.development {
+		t.Fatal("unexpected development settings")
+	}
+	prod := NewProduction(": prod:")
+	if _, ok := prod.output().enc.(JSONEncoder); !ok || prod.GetLevel() != Sinfo ||
+		prod.sampler == nil {
+		t.Fatal("unexpected production settings")
+	}
+}
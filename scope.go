@@ -0,0 +1,141 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import "context"
+
+// Scope is an immutable stack of fields, like user_id of a request, that are attached to
+// records of any Logger when the Scope (or a context carrying it, see WithScope) is a
+// member of a message list, so a logger need not be threaded through every call:
+//
+//	func handle(ctx context.Context, user string) {
+//		ctx = yell.WithScope(ctx, yell.Any("user_id", user))
+//		process(ctx)
+//	}
+//
+//	func process(ctx context.Context) {
+//		lg.Log(yell.Sinfo, ctx, "processing") // has user_id field
+//	}
+//
+// Scope fields precede other fields of the record, see SetSortFields. nil Scope is the
+// empty Scope.
+type Scope struct {
+	parent *Scope
+	fields []Field
+}
+
+// Push returns a new Scope with fields added to s, s is unchanged
+func (s *Scope) Push(fields ...Field) *Scope {
+	if len(fields) == 0 {
+		return s
+	}
+	return &Scope{s, append([]Field(nil), fields...)}
+}
+
+// Pop returns s without its last pushed fields
+func (s *Scope) Pop() *Scope {
+	if s == nil {
+		return nil
+	}
+	return s.parent
+}
+
+// Fields returns fields of s, outermost first
+func (s *Scope) Fields() []Field {
+	return s.appendFields(nil)
+}
+
+// appendFields appends fields of s to list, outermost first
+func (s *Scope) appendFields(list []Field) []Field {
+	if s == nil {
+		return list
+	}
+	return append(s.parent.appendFields(list), s.fields...)
+}
+
+// scopeKey is the context key of Scope
+type scopeKey struct{}
+
+// WithScope returns a copy of ctx whose Scope has fields pushed, see Scope. Records of
+// the returned context carry fields until the parent context is used again.
+func WithScope(ctx context.Context, fields ...Field) context.Context {
+	return context.WithValue(ctx, scopeKey{}, ScopeFrom(ctx).Push(fields...))
+}
+
+// ScopeFrom returns Scope of ctx, nil if it has none
+func ScopeFrom(ctx context.Context) *Scope {
+	s, _ := ctx.Value(scopeKey{}).(*Scope)
+	return s
+}
+
+// expandScopes returns msg with *Scope & context.Context members removed and their
+// fields moved to the front, and the number of those fields. msg is copied if necessary.
+func expandScopes(msg []interface{}) ([]interface{}, int) {
+	var bound []interface{}
+	scoped := false
+	for _, m := range msg {
+		var s *Scope
+		switch x := m.(type) {
+		case *Scope:
+			s = x
+		case context.Context:
+			s = ScopeFrom(x)
+		default:
+			continue
+		}
+		scoped = true
+		for _, f := range s.Fields() {
+			bound = append(bound, f)
+		}
+	}
+	if !scoped {
+		return msg, 0
+	}
+
+	n := len(bound)
+	for _, m := range msg {
+		switch m.(type) {
+		case *Scope, context.Context:
+		default:
+			bound = append(bound, m)
+		}
+	}
+	return bound, n
+}
+
+// levelKey is the context key of minimum severity overrides
+type levelKey struct{}
+
+// WithMinLevel returns a copy of ctx that lowers minimum severity of any Logger to level
+// for records with the context, for example to debug a single request in production
+// (see yellhttp.DebugHeader). It cannot raise minimum severities. Maximum severities
+// still apply.
+func WithMinLevel(ctx context.Context, level Severity) context.Context {
+	return context.WithValue(ctx, levelKey{}, level)
+}
+
+// MinLevelFrom returns minimum severity override of ctx, Snolog if it has none
+func MinLevelFrom(ctx context.Context) Severity {
+	if level, ok := ctx.Value(levelKey{}).(Severity); ok {
+		return level
+	}
+	return Snolog
+}
+
+// scopeLevel returns the lowest minimum severity override of contexts in msg, Snolog
+// if there is none
+func scopeLevel(msg []interface{}) Severity {
+	level := Snolog
+	for _, m := range msg {
+		if ctx, ok := m.(context.Context); ok {
+			if l := MinLevelFrom(ctx); l < level {
+				level = l
+			}
+		}
+	}
+	return level
+}
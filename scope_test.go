@@ -0,0 +1,84 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestScope(t *testing.T) {
+	var s *Scope
+	if s.Fields() != nil || s.Pop() != nil || s.Push() != nil {
+		t.Fatal("bad empty scope")
+	}
+	s1 := s.Push(Any("a", 1))
+	s2 := s1.Push(Any("b", 2), Any("c", 3))
+	if f := s2.Fields(); len(f) != 3 || f[0].Key != "a" || f[2].Key != "c" ||
+		len(s1.Fields()) != 1 || s2.Pop() != s1 {
+		t.Fatal("bad scope fields", f)
+	}
+
+	var sb strings.Builder
+	lg := New(": sc:", &sb, Sinfo)
+	lg.Log(Sinfo, s2, "explicit")
+	if !strings.HasSuffix(sb.String(), " explicit a=1 b=2 c=3\n") {
+		t.Fatal("bad scoped record", sb.String())
+	}
+}
+
+func TestWithScope(t *testing.T) {
+	var sb strings.Builder
+	lg := New(": sc:", &sb, Sinfo)
+
+	ctx := context.Background()
+	if ScopeFrom(ctx) != nil {
+		t.Fatal("background has scope")
+	}
+	req := WithScope(ctx, Any("user_id", 7))
+	inner := WithScope(req, Any("step", "load"))
+
+	lg.Log(Sinfo, inner, "loading", Any("n", 2), errors.New("slow"))
+	lg.Log(Sinfo, req, "done")
+	lg.Log(Sinfo, ctx, "plain")
+
+	lines := strings.Split(sb.String(), "\n")
+	if !strings.HasSuffix(lines[0], " loading slow user_id=7 step=load n=2") ||
+		!strings.HasSuffix(lines[1], " done user_id=7") || !strings.HasSuffix(lines[2], ": plain") {
+		t.Fatal("bad context records", sb.String())
+	}
+
+	// context alone is an empty message
+	sb.Reset()
+	if lg.Log(Sinfo, Caller(1), ctx) != nil || sb.Len() != 0 {
+		t.Fatal("empty message logged")
+	}
+}
+
+func TestWithMinLevel(t *testing.T) {
+	var sb strings.Builder
+	lg := New(": ml:", &sb, Swarn)
+	lg.SetTimeMode(Tnone)
+
+	ctx := context.Background()
+	if MinLevelFrom(ctx) != Snolog {
+		t.Fatal("background has level")
+	}
+	dbg := WithMinLevel(WithScope(ctx, Any("req", 1)), Sdebug)
+	lg.Log(Sdebug, "dropped")
+	lg.Log(Sdebug, dbg, "kept")
+	lg.Log(Sinfo, WithMinLevel(ctx, Swarn), "high")
+	lg.Log(Sinfo, WithScope(dbg, Any("step", 2)), "nested")
+
+	lines := strings.Split(sb.String(), "\n")
+	if len(lines) != 3 || !strings.HasSuffix(lines[0], " kept req=1") ||
+		!strings.HasSuffix(lines[1], " nested req=1 step=2") {
+		t.Fatal("bad escalated records:", sb.String())
+	}
+}
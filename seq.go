@@ -0,0 +1,29 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import "sync/atomic"
+
+// SetSequence enables or disables sequence numbers in records. When enabled, each record
+// gets the next number of a counter (starting from 1) shared with copies of Logger, so
+// consumers can detect dropped or reordered records. It should be called before Logger
+// is used.
+func (lg *Logger) SetSequence(on bool) {
+	if !on {
+		lg.seq = nil
+	} else if lg.seq == nil {
+		lg.seq = new(uint64)
+	}
+}
+
+// nextSeq returns next sequence number, or zero if disabled
+func (lg *Logger) nextSeq() uint64 {
+	if lg.seq == nil {
+		return 0
+	}
+	return atomic.AddUint64(lg.seq, 1)
+}
@@ -0,0 +1,58 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"io/ioutil"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSequence(t *testing.T) {
+	var sb strings.Builder
+	lg := New(": seq:", &sb, Sinfo)
+	lg.SetSequence(true)
+
+	if err := lg.Log(Sinfo, "first"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(sb.String(), "first seq=1\n") {
+		t.Fatal("unexpected record:", sb.String())
+	}
+
+	// concurrent logging must yield distinct numbers
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		seen = map[uint64]bool{}
+	)
+	lg.UpdateWriter(ioutil.Discard)
+	lg.AddObserver(func(r Record) {
+		mu.Lock()
+		seen[r.Seq] = true
+		mu.Unlock()
+	})
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				lg.Log(Sinfo, "msg")
+			}
+		}()
+	}
+	wg.Wait()
+	if len(seen) != 400 || !seen[2] || !seen[401] {
+		t.Fatal("sequence numbers must be distinct & consecutive")
+	}
+
+	lg.SetSequence(false)
+	if lg.nextSeq() != 0 {
+		t.Fatal("must be disabled")
+	}
+}
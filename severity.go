@@ -0,0 +1,59 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"errors"
+	"strings"
+)
+
+// canonical severity names, which do not change with Sname
+var sevNames = [...]string{"debug", "info", "warn", "error", "fatal", "nolog"}
+
+// String returns canonical severity name like "warn" (independent of Sname), "nolog"
+// for Snolog or "invalid".
+func (s Severity) String() string {
+	if s <= Snolog {
+		return sevNames[s]
+	}
+	return "invalid"
+}
+
+// ErrSeverity is returned by ParseSeverity for unknown severity names
+var ErrSeverity = errors.New("yell: unknown severity")
+
+// ParseSeverity returns severity with canonical name (case-insensitive, trailing colon
+// optional), see Severity.String. Customized Sname does not affect it.
+func ParseSeverity(name string) (Severity, error) {
+	name = strings.TrimSuffix(strings.TrimSpace(name), ":")
+	for s, sn := range sevNames {
+		if strings.EqualFold(name, sn) {
+			return Severity(s), nil
+		}
+	}
+	return Snolog, ErrSeverity
+}
+
+// Set parses name into s (see ParseSeverity), so *Severity is a flag.Value:
+//
+//	level := yell.Swarn
+//	flag.Var(&level, "loglevel", "minimum severity to log")
+//	flag.Parse()
+//	Logger.SetLevel(level)
+func (s *Severity) Set(name string) error {
+	level, err := ParseSeverity(name)
+	if err != nil {
+		return err
+	}
+	*s = level
+	return nil
+}
+
+// Type returns "severity", for compatibility with github.com/spf13/pflag
+func (*Severity) Type() string {
+	return "severity"
+}
@@ -0,0 +1,55 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"flag"
+	"io/ioutil"
+	"testing"
+)
+
+func TestSeverity(t *testing.T) {
+	for s := Sdebug; s <= Snolog; s++ {
+		if p, err := ParseSeverity(s.String()); err != nil || p != s {
+			t.Fatal("must parse", s)
+		}
+	}
+	if s, err := ParseSeverity(" WARN: "); err != nil || s != Swarn {
+		t.Fatal("must parse warn")
+	}
+	if _, err := ParseSeverity("loud"); err != ErrSeverity {
+		t.Fatal("must not parse")
+	}
+	if (Snolog + 1).String() != "invalid" {
+		t.Fatal("must be invalid")
+	}
+
+	// customized names are for display only
+	sname := Sname
+	defer func() { Sname = sname }()
+	Sname = [...]string{"调试:", "信息:", "警告:", "错误:", "致命的:"}
+	if s, err := ParseSeverity("info"); err != nil || s != Sinfo || s.String() != "info" {
+		t.Fatal("must parse canonical name:", s, err)
+	}
+}
+
+func TestSeverityFlag(t *testing.T) {
+	level := Swarn
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	fs.Var(&level, "loglevel", "minimum severity to log")
+
+	if err := fs.Parse([]string{"-loglevel=debug"}); err != nil || level != Sdebug {
+		t.Fatal("must parse flag:", err, level)
+	}
+	if err := fs.Parse([]string{"-loglevel", "loud"}); err == nil || level != Sdebug {
+		t.Fatal("must reject invalid level")
+	}
+	if f := fs.Lookup("loglevel"); f.DefValue != "warn" || level.Type() != "severity" {
+		t.Fatal("unexpected flag:", f.DefValue)
+	}
+}
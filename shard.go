@@ -0,0 +1,225 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"io"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ShardedWriter buffers records in per-processor shards, so concurrent logging goroutines
+// rarely contend for a lock. A background flusher merges shards in the order records were
+// written (hence records of each goroutine stay in order) and writes them to underlying
+// writer every interval, or earlier when a shard grows large. Flush writes buffered
+// records immediately. Severe records bypass shards, see SetSyncLevel.
+//
+//	sw := yell.NewShardedWriter(file, 100*time.Millisecond)
+//	lg := yell.New(": mypkg:", sw, yell.Sinfo)
+//	defer lg.Close() // writes buffered records, closes file
+type ShardedWriter struct {
+	seq uint64 // of last record, accessed atomically, first for alignment
+
+	writer io.Writer
+	shards []*shard
+	pool   sync.Pool // of *shard, for processor affinity
+	next   uint32    // round robin shard for pool, accessed atomically
+
+	mu     sync.Mutex // held while merging & writing
+	merged []byte
+	err    error  // first write error since last Flush
+	closed uint32 // 1 after Close, accessed atomically
+
+	wake chan struct{}
+	stop chan struct{}
+	done chan struct{}
+}
+
+// shard is a buffer of records
+type shard struct {
+	mu   sync.Mutex
+	buf  []byte
+	recs []shardRec
+
+	// spare buffers for next swap, accessed with ShardedWriter.mu held
+	sbuf  []byte
+	srecs []shardRec
+}
+
+// shardRec is a record in shard buffer ending at end
+type shardRec struct {
+	seq uint64
+	end int
+}
+
+// shardLimit is the shard size that triggers an early flush
+const shardLimit = 64 << 10
+
+// NewShardedWriter creates a ShardedWriter for writer with GOMAXPROCS shards, flushed
+// every interval. Panics if writer is nil or interval is not positive.
+func NewShardedWriter(writer io.Writer, interval time.Duration) *ShardedWriter {
+	if writer == nil || interval <= 0 {
+		panic("yell: invalid arguments to NewShardedWriter")
+	}
+	sw := &ShardedWriter{writer: writer, shards: make([]*shard, runtime.GOMAXPROCS(0)),
+		wake: make(chan struct{}, 1), stop: make(chan struct{}), done: make(chan struct{})}
+	for i := range sw.shards {
+		sw.shards[i] = new(shard)
+	}
+	sw.pool.New = func() interface{} {
+		return sw.shards[atomic.AddUint32(&sw.next, 1)%uint32(len(sw.shards))]
+	}
+	go sw.run(interval)
+	return sw
+}
+
+// Write appends p to a shard of current processor. Returns ErrClosed after Close.
+func (sw *ShardedWriter) Write(p []byte) (int, error) {
+	s := sw.pool.Get().(*shard)
+	s.mu.Lock()
+	if atomic.LoadUint32(&sw.closed) != 0 { // under lock, so Close writes p or rejects it
+		s.mu.Unlock()
+		sw.pool.Put(s)
+		return 0, ErrClosed
+	}
+	seq := atomic.AddUint64(&sw.seq, 1) // under lock, so shards are in seq order
+	s.buf = append(s.buf, p...)
+	s.recs = append(s.recs, shardRec{seq, len(s.buf)})
+	large := len(s.buf) >= shardLimit
+	s.mu.Unlock()
+	sw.pool.Put(s)
+
+	if large {
+		select {
+		case sw.wake <- struct{}{}:
+		default:
+		}
+	}
+	return len(p), nil
+}
+
+// Flush writes buffered records and flushes underlying writer. Returns the first write
+// error since previous Flush, if any.
+func (sw *ShardedWriter) Flush() error {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	if atomic.LoadUint32(&sw.closed) != 0 {
+		return ErrClosed
+	}
+	sw.write()
+	err := sw.err
+	if e := flushWriter(sw.writer); err == nil {
+		err = e
+	}
+	sw.err = nil
+	return err
+}
+
+// writeSync writes buffered records, then p directly, and flushes underlying writer
+func (sw *ShardedWriter) writeSync(p []byte) error {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	if atomic.LoadUint32(&sw.closed) != 0 {
+		return ErrClosed
+	}
+	sw.write()
+	_, err := sw.writer.Write(p)
+	if e := flushWriter(sw.writer); err == nil {
+		err = e
+	}
+	return err
+}
+
+// Close writes buffered records, stops the flusher and closes underlying writer (see
+// Logger.Close). Later Writes return ErrClosed.
+func (sw *ShardedWriter) Close() error {
+	if !atomic.CompareAndSwapUint32(&sw.closed, 0, 1) {
+		return ErrClosed
+	}
+
+	close(sw.stop)
+	<-sw.done
+
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	sw.write()
+	err := sw.err
+	if e := closeWriter(sw.writer); err == nil {
+		err = e
+	}
+	return err
+}
+
+// run flushes shards every interval, or when woken, until Close
+func (sw *ShardedWriter) run(interval time.Duration) {
+	defer close(sw.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-sw.wake:
+		case <-sw.stop:
+			return
+		}
+		sw.mu.Lock()
+		sw.write()
+		sw.mu.Unlock()
+	}
+}
+
+// write merges shards in seq order and writes them. Must be called with sw.mu held.
+func (sw *ShardedWriter) write() {
+	// all shards are locked together, so buffered records have no seq gaps
+	for _, s := range sw.shards {
+		s.mu.Lock()
+	}
+	for _, s := range sw.shards {
+		s.buf, s.sbuf = s.sbuf[:0], s.buf
+		s.recs, s.srecs = s.srecs[:0], s.recs
+		s.mu.Unlock()
+	}
+
+	// merge records with smallest seq first
+	sw.merged = sw.merged[:0]
+	next := make([]int, len(sw.shards)) // record index in each shard
+	for {
+		k := -1
+		for i, s := range sw.shards {
+			if next[i] < len(s.srecs) &&
+				(k < 0 || s.srecs[next[i]].seq < sw.shards[k].srecs[next[k]].seq) {
+				k = i
+			}
+		}
+		if k < 0 {
+			break
+		}
+		s, start := sw.shards[k], 0
+		if next[k] > 0 {
+			start = s.srecs[next[k]-1].end
+		}
+		sw.merged = append(sw.merged, s.sbuf[start:s.srecs[next[k]].end]...)
+		next[k]++
+	}
+	for _, s := range sw.shards {
+		if cap(s.sbuf) > 4*shardLimit { // after a burst
+			s.sbuf, s.srecs = nil, nil
+		}
+	}
+
+	if len(sw.merged) > 0 {
+		if _, err := sw.writer.Write(sw.merged); sw.err == nil {
+			sw.err = err
+		}
+	}
+	if cap(sw.merged) > 4*shardLimit {
+		sw.merged = nil
+	}
+}
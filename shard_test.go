@@ -0,0 +1,104 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestShardedWriter(t *testing.T) {
+	var cb closeBuffer
+	sw := NewShardedWriter(&cb, time.Hour)
+	lg := New(": sh:", sw, Sinfo)
+	lg.SetTimeMode(Tnone)
+	written := func() int { // by flusher
+		sw.mu.Lock()
+		defer sw.mu.Unlock()
+		return cb.Len()
+	}
+
+	const workers, n = 8, 200
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				lg.Log(Sinfo, w, i)
+			}
+		}(w)
+	}
+	wg.Wait()
+	if written() != 0 {
+		t.Fatal("records must be buffered")
+	}
+	if err := lg.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	next := make([]int, workers)
+	lines := strings.Split(strings.TrimSuffix(cb.String(), "\n"), "\n")
+	if len(lines) != workers*n {
+		t.Fatal("missing records:", len(lines))
+	}
+	for _, l := range lines {
+		s := strings.Fields(l)
+		w, _ := strconv.Atoi(s[len(s)-2])
+		i, _ := strconv.Atoi(s[len(s)-1])
+		if next[w] != i {
+			t.Fatal("out of order:", l)
+		}
+		next[w]++
+	}
+
+	// large shard wakes flusher
+	cb.Reset()
+	sw.Write(bytes.Repeat([]byte("x"), shardLimit))
+	for i := 0; i < 100 && written() == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := written(); n != shardLimit {
+		t.Fatal("large shard must be flushed early:", n)
+	}
+
+	lg.Log(Swarn, "last")
+	if err := lg.Close(); err != nil || !cb.closed || !strings.HasSuffix(cb.String(), " last\n") {
+		t.Fatal("close must write buffered records", err)
+	}
+	if _, err := sw.Write([]byte("lost\n")); err != ErrClosed ||
+		sw.Flush() != ErrClosed || sw.Close() != ErrClosed {
+		t.Fatal("expected ErrClosed")
+	}
+}
+
+func TestShardMerge(t *testing.T) {
+	var buf bytes.Buffer
+	sw := &ShardedWriter{writer: &buf, shards: []*shard{
+		{buf: []byte("1\n4\n5\n"), recs: []shardRec{{1, 2}, {4, 4}, {5, 6}}},
+		{},
+		{buf: []byte("2\n3\n6\n"), recs: []shardRec{{2, 2}, {3, 4}, {6, 6}}},
+	}}
+	sw.write()
+	if buf.String() != "1\n2\n3\n4\n5\n6\n" {
+		t.Fatal("bad merge:", buf.String())
+	}
+	for _, s := range sw.shards {
+		if len(s.buf) != 0 || len(s.recs) != 0 {
+			t.Fatal("shards must be emptied")
+		}
+	}
+	buf.Reset()
+	sw.write()
+	if buf.Len() != 0 {
+		t.Fatal("nothing to write")
+	}
+}
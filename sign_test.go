@@ -0,0 +1,57 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSign(t *testing.T) {
+	key := []byte("secret")
+	var buf bytes.Buffer
+	lg := New(": sig:", NewSignWriter(&buf, key), Sinfo)
+	lg.SetErrorDetail(true)
+
+	lg.Log(Sinfo, "text", 1)
+	lg.Log(Sinfo, "point", struct{ X, Y int }{1, 2})
+	lg.Log(Serror, "detailed", fmt.Errorf("a: %w", errors.New("b")))
+	lg.SetFormat(Fjson)
+	lg.Log(Swarn, "json", 3)
+
+	log := buf.String()
+	if n, err := VerifySigned(strings.NewReader(log), key); err != nil || n != 4 ||
+		!strings.Contains(log, "point {1 2} sig=") {
+		t.Fatal("must verify:", n, err, log)
+	}
+
+	// records are signed independently
+	tail := log[strings.IndexByte(log, '\n')+1:]
+	if n, err := VerifySigned(strings.NewReader(tail), key); err != nil || n != 3 {
+		t.Fatal("must verify partial log:", n, err)
+	}
+
+	tampered := [...]string{
+		strings.Replace(log, "text 1", "text 2", 1),     // altered
+		strings.Replace(log, `"json 3"`, `"json 4"`, 1), // altered JSON
+		strings.Replace(log, "sig=", "sig=0", 1),        // invalid
+		log + "unsigned\n",                              // unsigned
+	}
+	for i, s := range tampered {
+		var se *SignError
+		if _, err := VerifySigned(strings.NewReader(s), key); !errors.As(err, &se) {
+			t.Fatal("must fail to verify:", i, err)
+		}
+	}
+
+	if _, err := VerifySigned(strings.NewReader(log), []byte("wrong")); err == nil {
+		t.Fatal("must fail with wrong key")
+	}
+}
@@ -0,0 +1,68 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+)
+
+// CloseAll closes registered Loggers (see Register & Logger.Close) and returns the first
+// error. Loggers sharing a writer close it once.
+func CloseAll() (err error) {
+	closed := map[interface{}]bool{}
+	for _, lg := range Loggers() {
+		w := lg.output().writer
+		if reflect.TypeOf(w).Comparable() {
+			if closed[w] {
+				continue
+			}
+			closed[w] = true
+		}
+		if e := lg.Close(); err == nil {
+			err = e
+		}
+	}
+	return
+}
+
+// exit is os.Exit, replaced by tests
+var exit = os.Exit
+
+// CloseOnSignal installs a handler for sigs (SIGTERM & SIGINT if none given, only SIGINT
+// on plan9) that calls at-exit hooks (see AtExit), closes registered Loggers with CloseAll
+// and exits with status 128 + signal number, so buffered records are not lost when the
+// process is asked to terminate, for example by Kubernetes. Returns a function that
+// uninstalls the handler.
+func CloseOnSignal(sigs ...os.Signal) (stop func()) {
+	if len(sigs) == 0 {
+		sigs = exitSignals[:]
+	}
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(ch, sigs...)
+
+	go func() {
+		select {
+		case sig := <-ch:
+			runExitHooks()
+			CloseAll()
+			exit(exitCode(sig))
+		case <-done:
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}
+}
@@ -0,0 +1,20 @@
+//go:build plan9
+// +build plan9
+
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import "os"
+
+// default signals of CloseOnSignal
+var exitSignals = [...]os.Signal{os.Interrupt}
+
+// exitCode returns 1, signals have no numbers
+func exitCode(os.Signal) int {
+	return 1
+}
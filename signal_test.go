@@ -0,0 +1,43 @@
+//go:build !windows && !plan9 && !js
+// +build !windows,!plan9,!js
+
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestCloseOnSignal(t *testing.T) {
+	var rec closeRecorder
+	lg1 := New(": sig1:", &rec, Sinfo)
+	lg2 := New(": sig2:", &rec, Sinfo)
+	Register(&lg1)
+	Register(&lg2)
+
+	codes := make(chan int, 1)
+	exit = func(code int) { codes <- code }
+	defer func() { exit = os.Exit }()
+
+	stop := CloseOnSignal(syscall.SIGHUP)
+	defer stop()
+	p, _ := os.FindProcess(os.Getpid())
+	if err := p.Signal(syscall.SIGHUP); err != nil {
+		t.Skip("cannot send signal:", err)
+	}
+
+	if code := <-codes; code != 128+int(syscall.SIGHUP) {
+		t.Fatal("unexpected exit code:", code)
+	}
+	if rec.closes != 1 {
+		t.Fatal("shared writer must be closed once:", rec.closes)
+	}
+	stop()
+}
@@ -0,0 +1,26 @@
+//go:build !plan9
+// +build !plan9
+
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"os"
+	"syscall"
+)
+
+// default signals of CloseOnSignal
+var exitSignals = [...]os.Signal{syscall.SIGTERM, os.Interrupt}
+
+// exitCode returns 128 + signal number, or 1 if sig has no number
+func exitCode(sig os.Signal) int {
+	if s, ok := sig.(syscall.Signal); ok {
+		return 128 + int(s)
+	}
+	return 1
+}
@@ -0,0 +1,86 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"io"
+	"strings"
+	"unicode"
+)
+
+// number of leading bytes & non-numeric words of a line searched by SniffSeverity
+const (
+	sniffBytes = 64
+	sniffWords = 3
+)
+
+// sniffNames maps upper case severity words of legacy output to levels
+var sniffNames = map[string]Severity{
+	"TRACE": Sdebug, "DEBUG": Sdebug, "DBG": Sdebug,
+	"INFO": Sinfo, "INF": Sinfo, "NOTICE": Sinfo,
+	"WARN": Swarn, "WARNING": Swarn, "WRN": Swarn,
+	"ERROR": Serror, "ERR": Serror, "ERRO": Serror,
+	"FATAL": Sfatal, "FTL": Sfatal, "PANIC": Sfatal, "CRIT": Sfatal, "CRITICAL": Sfatal,
+}
+
+// SniffSeverity heuristically detects severity of a legacy output line from common
+// prefixes like "ERROR: msg", "[warn] msg", "2021-06-01 10:00:00 INFO msg",
+// "level=debug msg" or glog style "E0601 10:00:00.000000 ...". Only the first three
+// non-numeric words are searched. Returns false if none is found.
+func SniffSeverity(line string) (Severity, bool) {
+	if len(line) > sniffBytes {
+		line = line[:sniffBytes]
+	}
+	words := strings.FieldsFunc(line, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	n := 0 // non-numeric words, like those of timestamps
+	for i, w := range words {
+		if strings.Trim(w, "0123456789") == "" {
+			continue
+		}
+		if s, ok := sniffNames[strings.ToUpper(w)]; ok {
+			return s, true
+		}
+		if i == 0 {
+			if s, ok := glogSeverity(w); ok {
+				return s, true
+			}
+		}
+		if n++; n >= sniffWords {
+			break
+		}
+	}
+	return 0, false
+}
+
+// glogSeverity detects severity of glog style header word like E0601
+func glogSeverity(w string) (Severity, bool) {
+	if len(w) != 5 || strings.Trim(w[1:], "0123456789") != "" {
+		return 0, false
+	}
+	switch w[0] {
+	case 'I':
+		return Sinfo, true
+	case 'W':
+		return Swarn, true
+	case 'E':
+		return Serror, true
+	case 'F':
+		return Sfatal, true
+	}
+	return 0, false
+}
+
+// SniffWriter returns an io.Writer that splits written data into lines and logs them with
+// severities detected by SniffSeverity, or level if none is detected, for example to
+// adopt output of legacy components. Empty lines are ignored. The writer implements
+// Flusher to log an incomplete last line. It must not be used concurrently. Request
+// location of the records is not meaningful.
+func (lg *Logger) SniffWriter(level Severity) io.Writer {
+	return &captureWriter{lg: lg, level: level, sniff: true}
+}
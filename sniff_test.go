@@ -0,0 +1,52 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+func TestSniffSeverity(t *testing.T) {
+	tests := []struct {
+		line  string
+		level Severity
+		ok    bool
+	}{
+		{"ERROR: disk full", Serror, true},
+		{"[warn] retrying", Swarn, true},
+		{"2021-06-01 10:00:00 INFO started", Sinfo, true},
+		{"time=12:00 level=debug msg=x", Sdebug, true},
+		{"E0601 10:00:00.000000 1 main.go:1] boom", Serror, true},
+		{"W0601 something", Swarn, true},
+		{"<panic> in worker", Sfatal, true},
+		{"processed 5 items", 0, false},
+		{"I saw an error later in this long sentence that goes on", 0, false},
+		{"", 0, false},
+	}
+	for _, tc := range tests {
+		if s, ok := SniffSeverity(tc.line); s != tc.level || ok != tc.ok {
+			t.Fatal("bad sniff", tc.line, s, ok)
+		}
+	}
+}
+
+func TestSniffWriter(t *testing.T) {
+	var recs []Record
+	lg := New(": sniff:", ioutil.Discard, Sinfo)
+	lg.AddObserver(func(r Record) { recs = append(recs, r) })
+
+	w := lg.SniffWriter(Sinfo)
+	w.Write([]byte("plain\nWARN: lo"))
+	w.Write([]byte("w space\n[debug] hidden\nERROR tail"))
+	w.(Flusher).Flush()
+
+	if len(recs) != 3 || recs[0].Level != Sinfo || recs[1].Level != Swarn ||
+		recs[1].Msg != "WARN: low space" || recs[2].Level != Serror || recs[2].Msg != "ERROR tail" {
+		t.Fatal("unexpected records:", recs)
+	}
+}
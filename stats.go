@@ -0,0 +1,57 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"expvar"
+	"sync/atomic"
+)
+
+// stats of a Logger, shared by its copies
+type stats struct {
+	counts [Snolog]uint64 // records per severity, accessed atomically
+	failed uint64         // failed records, accessed atomically
+
+	started int64 // unix nano time of Startup, accessed atomically
+}
+
+// Count returns number of records logged with severity level (including failed ones)
+func (lg *Logger) Count(level Severity) uint64 {
+	if level >= Snolog {
+		return 0
+	}
+	return atomic.LoadUint64(&lg.stats.counts[level])
+}
+
+// Failed returns number of failed records, see LogError
+func (lg *Logger) Failed() uint64 {
+	return atomic.LoadUint64(&lg.stats.failed)
+}
+
+// Publish publishes Logger's minimum level, total & failed records and per-severity
+// record counts as an expvar variable like:
+//
+//	"yell.mypkg": {"level": "warn", "records": 12, "failed": 0, "warn": 10, "error": 2, ...}
+//
+// so they are served with other expvars at /debug/vars. Like expvar.Publish, panics if
+// name is already registered.
+func (lg *Logger) Publish(name string) {
+	expvar.Publish(name, expvar.Func(lg.vars))
+}
+
+// vars returns Logger state for expvar
+func (lg *Logger) vars() interface{} {
+	m := map[string]interface{}{"level": lg.GetLevel().String(), "failed": lg.Failed()}
+	total := uint64(0)
+	for l := Severity(0); l < Snolog; l++ {
+		n := lg.Count(l)
+		m[levelName(l)] = n
+		total += n
+	}
+	m["records"] = total
+	return m
+}
@@ -0,0 +1,43 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"encoding/json"
+	"expvar"
+	"io/ioutil"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	lg := New(": sta:", ioutil.Discard, Swarn)
+	lg.Log(Sinfo, "ignored")
+	lg.Log(Swarn, Caller(1)) // empty
+	lg.Log(Swarn, "w1")
+	lg.Log(Swarn, "w2")
+	lg.Log(Sfatal, "f")
+	lg.LogTo(failWriter{}, Serror, "e")
+
+	if lg.Count(Sinfo) != 0 || lg.Count(Swarn) != 2 || lg.Count(Serror) != 1 ||
+		lg.Count(Sfatal) != 1 || lg.Count(Snolog) != 0 || lg.Failed() != 1 {
+		t.Fatal("unexpected counts")
+	}
+
+	lg.Publish("yell.sta")
+	var m map[string]interface{}
+	if err := json.Unmarshal([]byte(expvar.Get("yell.sta").String()), &m); err != nil {
+		t.Fatal(err)
+	}
+	if m["level"] != "warn" || m["records"] != 4.0 || m["failed"] != 1.0 || m["warn"] != 2.0 {
+		t.Fatal("unexpected vars:", m)
+	}
+
+	lg.SetLevel(Snolog)
+	if lg.vars().(map[string]interface{})["level"] != "nolog" {
+		t.Fatal("must be nolog")
+	}
+}
@@ -0,0 +1,41 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"log"
+	"strings"
+)
+
+// Stdlib returns a standard library logger without prefix & flags, whose output is
+// logged to Logger with severity level, so third-party libraries using log package flow
+// through Logger:
+//
+//	srv := &http.Server{ErrorLog: yell.Stdlib(&lg, yell.Serror)}
+//
+// Trailing newlines are removed, empty outputs are ignored. Request location is the
+// caller of log package, like log.Printf, plus caller skips of Logger. Later changes to
+// settings of Logger (like its level or writer) apply to the returned logger.
+func Stdlib(lg *Logger, level Severity) *log.Logger {
+	return log.New(&stdWriter{lg, level}, "", 0)
+}
+
+// stdWriter logs outputs of a standard library logger
+type stdWriter struct {
+	lg    *Logger
+	level Severity
+}
+
+// Write logs p to Logger, it must be called directly by log package (from log.Printf etc.
+// through an unexported method) for correct caller depth. It always reports success, so
+// log package does not retry or panic.
+func (w *stdWriter) Write(p []byte) (int, error) {
+	if msg := strings.TrimRight(string(p), "\n"); msg != "" {
+		w.lg.log(2, mStdlib, nil, w.level, []interface{}{msg}, nil)
+	}
+	return len(p), nil
+}
@@ -0,0 +1,48 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"io/ioutil"
+	"runtime"
+	"testing"
+)
+
+func TestStdlib(t *testing.T) {
+	var recs []Record
+	lg := New(": std:", ioutil.Discard, Sinfo)
+	lg.AddObserver(func(r Record) { recs = append(recs, r) })
+
+	std := Stdlib(&lg, Swarn)
+	std.Printf("conn %d reset\n", 3)
+	std.Print("")
+	Stdlib(&lg, Sdebug).Println("ignored")
+
+	// caller skips of Logger are kept
+	wrapped := lg.WithCallerSkip(1)
+	wstd := Stdlib(&wrapped, Swarn)
+	logw := func() { wstd.Print("wrapped") }
+	logw()
+	_, _, line, _ := runtime.Caller(0)
+	lg.SetLevel(Serror)
+	std.Print("dropped")
+
+	// test frames belong to yell, only log package frames must be skipped
+	lg.SetAutoCaller(true)
+	Stdlib(&lg, Serror).Println("auto")
+
+	if len(recs) != 3 {
+		t.Fatal("expected three records:", recs)
+	}
+	r := recs[0]
+	if r.Level != Swarn || r.Msg != "conn 3 reset" || r.File != "stdlib_test.go" ||
+		recs[1].Msg != "wrapped" || recs[1].Line != line-1 ||
+		recs[2].Level != Serror || recs[2].Msg != "auto" || recs[2].File == "log.go" ||
+		recs[2].File == "stdlib.go" {
+		t.Fatal("unexpected records:", recs)
+	}
+}
@@ -0,0 +1,68 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"sync"
+	"time"
+)
+
+// noCaller is the skip of log for records without request location, like those logged
+// from background goroutines
+const noCaller = 1 << 30
+
+// StartSummary starts logging a summary record with level every interval, which has
+// counts of records per severity, failed & sampled records since previous summary:
+//
+//	mypkg:info: yell: summary debug=0 info=12 warn=1 error=0 fatal=0 failed=0 sampled=0
+//
+// so silence of low-traffic services is not ambiguous. Summary records have no request
+// location and are not counted themselves. Returns a function that stops summaries.
+// Panics if interval is not positive.
+func (lg *Logger) StartSummary(level Severity, interval time.Duration) (stop func()) {
+	if interval <= 0 {
+		panic("yell: non-positive summary interval")
+	}
+	done := make(chan struct{})
+	prev := lg.summaryCounts()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-done:
+				return
+			}
+			cur := lg.summaryCounts()
+			msg := make([]interface{}, 1, len(cur)+1)
+			msg[0] = "yell: summary"
+			for i, n := range cur {
+				msg = append(msg, Uint64(summaryKeys[i], n-prev[i]))
+			}
+			lg.log(noCaller, mUncounted, nil, level, msg, nil)
+			prev = cur
+		}
+	}()
+
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }
+}
+
+// summaryKeys are field keys of summary records
+var summaryKeys = [...]string{"debug", "info", "warn", "error", "fatal", "failed", "sampled"}
+
+// summaryCounts returns current counts of summary records
+func (lg *Logger) summaryCounts() (c [len(summaryKeys)]uint64) {
+	for s := Sdebug; s < Snolog; s++ {
+		c[s] = lg.Count(s)
+	}
+	c[Snolog] = lg.Failed()
+	c[Snolog+1] = lg.Sampled()
+	return
+}
@@ -0,0 +1,51 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSummary(t *testing.T) {
+	var fb flushBuffer
+	lg := New(": su:", &fb, Sinfo)
+	lg.SetTimeMode(Tnone)
+	lg.Log(Serror, "before")
+
+	stop := lg.StartSummary(Sinfo, 50*time.Millisecond)
+	lg.Log(Sinfo, "one")
+	lg.Log(Sinfo, "two")
+	lg.Log(Swarn, "three")
+	lg.LogTo(failWriter{}, Serror, "failed")
+
+	var s string
+	for i := 0; i < 100 && strings.Count(s, "summary") < 2; i++ {
+		time.Sleep(10 * time.Millisecond)
+		s, _ = fb.state()
+	}
+	stop()
+	stop()
+
+	lines := strings.Split(s, "\n")
+	if len(lines) < 6 ||
+		lines[4] != "su:info: yell: summary debug=0 info=2 warn=1 error=1 fatal=0 failed=1 sampled=0" ||
+		lines[5] != "su:info: yell: summary debug=0 info=0 warn=0 error=0 fatal=0 failed=0 sampled=0" {
+		t.Fatal("unexpected summaries:", s)
+	}
+	if lg.Count(Sinfo) != 2 {
+		t.Fatal("summaries must not be counted")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("non-positive interval must panic")
+		}
+	}()
+	lg.StartSummary(Sinfo, 0)
+}
@@ -0,0 +1,154 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// template placeholders
+const (
+	tLiteral = iota
+	tTime
+	tName
+	tLevel
+	tCaller
+	tFile
+	tLine
+	tMsg
+	tFields
+	tSeq
+	tID
+)
+
+var placeholders = map[string]int{"time": tTime, "name": tName, "level": tLevel,
+	"caller": tCaller, "file": tFile, "line": tLine, "msg": tMsg, "fields": tFields,
+	"seq": tSeq, "id": tID}
+
+// ErrTemplate is returned by NewTemplateEncoder for invalid layouts
+var ErrTemplate = errors.New("yell: invalid template")
+
+// segment of a template, a placeholder or literal text
+type segment struct {
+	kind int
+	text string
+}
+
+// TemplateEncoder is a text Encoder with custom layout, see NewTemplateEncoder
+type TemplateEncoder struct {
+	segs []segment
+}
+
+// NewTemplateEncoder creates a TemplateEncoder from layout, which is literal text with
+// placeholders:
+//
+//	{time}   time formatted with TimeFormat, see SetTimeMode
+//	{name}   logger name like mypkg
+//	{level}  severity name without trailing colon
+//	{caller} request location file.go:line, empty if unknown
+//	{file}   request location file name, empty if unknown
+//	{line}   request location line number, empty if unknown
+//	{msg}    message list, see Record.Text
+//	{fields} space separated key=value pairs, empty if none
+//	{seq}    sequence number, empty if disabled
+//	{id}     record identifier, empty if disabled
+//
+// Use {{ for a literal brace. For example, layout of TextEncoder without fields,
+// sequence number & identifier is:
+//
+//	{time}: {name}:{level}: {caller}: {msg}
+//
+// Records end with a newline, error details (if any) follow like TextEncoder.
+// Returns ErrTemplate for unknown placeholders or unclosed braces.
+func NewTemplateEncoder(layout string) (*TemplateEncoder, error) {
+	te := &TemplateEncoder{}
+	var lit strings.Builder
+
+	for layout != "" {
+		i := strings.IndexByte(layout, '{')
+		if i < 0 {
+			lit.WriteString(layout)
+			break
+		}
+		lit.WriteString(layout[:i])
+		layout = layout[i+1:]
+
+		if strings.HasPrefix(layout, "{") {
+			lit.WriteByte('{')
+			layout = layout[1:]
+			continue
+		}
+		k := strings.IndexByte(layout, '}')
+		if k < 0 {
+			return nil, ErrTemplate
+		}
+		kind, ok := placeholders[layout[:k]]
+		if !ok {
+			return nil, ErrTemplate
+		}
+		layout = layout[k+1:]
+
+		if lit.Len() > 0 {
+			te.segs = append(te.segs, segment{tLiteral, lit.String()})
+			lit.Reset()
+		}
+		te.segs = append(te.segs, segment{kind: kind})
+	}
+
+	if lit.Len() > 0 {
+		te.segs = append(te.segs, segment{tLiteral, lit.String()})
+	}
+	return te, nil
+}
+
+// Encode appends text record to buf according to layout
+func (te *TemplateEncoder) Encode(buf []byte, r *Record) ([]byte, error) {
+	for _, s := range te.segs {
+		switch s.kind {
+		case tLiteral:
+			buf = append(buf, s.text...)
+		case tTime:
+			buf = r.AppendTime(buf, TimeFormat)
+		case tName:
+			buf = append(buf, r.Name...)
+		case tLevel:
+			buf = append(buf, levelName(r.Level)...)
+		case tCaller:
+			if r.File != "" {
+				buf = append(buf, r.File...)
+				buf = append(buf, ':')
+				buf = strconv.AppendInt(buf, int64(r.Line), 10)
+			}
+		case tFile:
+			buf = append(buf, r.File...)
+		case tLine:
+			if r.File != "" {
+				buf = strconv.AppendInt(buf, int64(r.Line), 10)
+			}
+		case tMsg:
+			buf = append(buf, r.Text()...)
+		case tFields:
+			for i, f := range r.Fields {
+				if i > 0 {
+					buf = append(buf, ' ')
+				}
+				buf = appendText(buf, f.Key)
+				buf = append(buf, '=')
+				buf = appendFieldValue(buf, f)
+			}
+		case tSeq:
+			if r.Seq != 0 {
+				buf = strconv.AppendUint(buf, r.Seq, 10)
+			}
+		case tID:
+			buf = append(buf, r.ID...)
+		}
+	}
+	return appendDetail(append(buf, '\n'), r), nil
+}
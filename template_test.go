@@ -0,0 +1,41 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTemplate(t *testing.T) {
+	for _, l := range [...]string{"{msg", "{unknown}", "{time} {"} {
+		if _, err := NewTemplateEncoder(l); err != ErrTemplate {
+			t.Fatal("must reject layout:", l)
+		}
+	}
+
+	r := Record{Time: time.Date(2021, 3, 28, 21, 48, 53, 591948000, time.UTC),
+		Level: Swarn, Name: "mypkg", File: "a.go", Line: 15, Msg: "hi", Seq: 3,
+		Fields: []Field{Any("k", "v"), Any("n", 2)}}
+
+	cases := [...]struct{ layout, want string }{
+		{"{time}: {name}:{level}: {caller}: {msg}",
+			"2021-03-28 21:48:53.591948: mypkg:warn: a.go:15: hi\n"},
+		{"[{level}] {msg} ({fields}) #{seq}{id} {{x}", "[warn] hi (k=v n=2) #3 {x}\n"},
+		{"{file}|{line}|{name}", "a.go|15|mypkg\n"},
+		{"", "\n"},
+	}
+	for _, c := range cases {
+		te, err := NewTemplateEncoder(c.layout)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b, _ := te.Encode(nil, &r); string(b) != c.want {
+			t.Fatal("unexpected record:", string(b), c.want)
+		}
+	}
+}
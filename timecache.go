@@ -0,0 +1,137 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// SetTimeCache sets the granularity of cached time formatting, one second by default.
+// Formatted times are reused within each granularity window, only their fractional
+// seconds are rendered for every record. Granularities above one second are only correct
+// for layouts without seconds (like "15:04"). Zero or negative granularity disables the
+// cache. It should be called before Logger is used.
+func (lg *Logger) SetTimeCache(granularity time.Duration) {
+	if granularity <= 0 {
+		lg.tcache = nil
+		return
+	}
+	lg.tcache = &timeCache{every: int64(granularity)}
+}
+
+// timeCache holds the last formatted time of a Logger, shared by its copies
+type timeCache struct {
+	every int64        // granularity in nanoseconds
+	last  atomic.Value // *timeEntry
+}
+
+// timeEntry is an immutable formatted time window
+type timeEntry struct {
+	layout string
+	loc    *time.Location
+	start  int64 // of window in Unix nanoseconds
+
+	prefix, suffix []byte // around fractional seconds
+	frac           fracSecond
+}
+
+// fracSecond is the fractional seconds element of a layout like .000 or ,999
+type fracSecond struct {
+	sep    byte // . or , zero if layout has no fractional seconds
+	digits int  // 1 to 9
+	trim   bool // drop trailing zeros, and separator if nothing remains
+}
+
+// append appends t formatted with layout to buf, uses cache if c is not nil
+func (c *timeCache) append(buf []byte, t time.Time, layout string) []byte {
+	if c == nil {
+		return t.AppendFormat(buf, layout)
+	}
+	ns := t.UnixNano()
+	start := ns - ns%c.every
+	if ns < 0 && ns%c.every != 0 {
+		start -= c.every
+	}
+	e, _ := c.last.Load().(*timeEntry)
+	if e == nil || e.start != start || e.layout != layout || e.loc != t.Location() {
+		if e = newTimeEntry(t, start, layout); e == nil {
+			return t.AppendFormat(buf, layout)
+		}
+		c.last.Store(e)
+	}
+	buf = append(buf, e.prefix...)
+	if e.frac.sep != 0 {
+		buf = e.frac.append(buf, t.Nanosecond())
+	}
+	return append(buf, e.suffix...)
+}
+
+// newTimeEntry formats parts of layout around its fractional seconds for t, returns
+// nil if layout has more than one or an unusual fractional seconds element.
+func newTimeEntry(t time.Time, start int64, layout string) *timeEntry {
+	e := &timeEntry{layout: layout, loc: t.Location(), start: start}
+	i, j, f := findFrac(layout, 0)
+	if j == 0 {
+		e.prefix = t.AppendFormat(nil, layout)
+		return e
+	}
+	if _, k, _ := findFrac(layout, j); f.sep == 0 || k != 0 {
+		return nil
+	}
+	e.prefix = t.AppendFormat(nil, layout[:i])
+	e.suffix = t.AppendFormat(nil, layout[j:])
+	e.frac = f
+	return e
+}
+
+// findFrac finds fractional seconds element layout[i:j] at or after layout[from:],
+// following the rules of package time. j is zero if there is none, f is zero if it has
+// more than 9 digits.
+func findFrac(layout string, from int) (i, j int, f fracSecond) {
+	for i = from; i+1 < len(layout); i++ {
+		c := layout[i]
+		if c != '.' && c != ',' {
+			continue
+		}
+		d := layout[i+1]
+		if d != '0' && d != '9' {
+			continue
+		}
+		for j = i + 1; j < len(layout) && layout[j] == d; j++ {
+		}
+		if j < len(layout) && '0' <= layout[j] && layout[j] <= '9' {
+			i = j - 1
+			continue
+		}
+		if j-i-1 > 9 {
+			return i, j, fracSecond{}
+		}
+		return i, j, fracSecond{c, j - i - 1, d == '9'}
+	}
+	return 0, 0, fracSecond{}
+}
+
+// append appends fractional part of nanoseconds ns to buf
+func (f fracSecond) append(buf []byte, ns int) []byte {
+	var digits [9]byte
+	for k := 8; k >= 0; k-- {
+		digits[k] = byte('0' + ns%10)
+		ns /= 10
+	}
+	n := f.digits
+	if f.trim {
+		for n > 0 && digits[n-1] == '0' {
+			n--
+		}
+		if n == 0 {
+			return buf
+		}
+	}
+	buf = append(buf, f.sep)
+	return append(buf, digits[:n]...)
+}
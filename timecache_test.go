@@ -0,0 +1,76 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeCache(t *testing.T) {
+	layouts := []string{TimeFormat, time.RFC3339Nano, time.RFC3339, time.StampMicro,
+		"15:04:05,000", "05.9 .000", "2006.01.02 15:04:05.0000000000", "01.002 15h", ""}
+	zones := []*time.Location{time.UTC, time.FixedZone("X", -3*3600-1800)}
+	nanos := []int{0, 1, 120000000, 999999999, 500, 123456789, 100000}
+	base := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+
+	c := &timeCache{every: int64(time.Second)}
+	for _, layout := range layouts {
+		for _, loc := range zones {
+			for k, ns := range nanos {
+				tm := base.Add(time.Duration(ns) + time.Duration(k/3)*time.Second).In(loc)
+				want := tm.Format(layout)
+				if got := string(c.append(nil, tm, layout)); got != want {
+					t.Fatalf("%q: expected %q, got %q", layout, want, got)
+				}
+			}
+		}
+	}
+
+	neg := time.Unix(-1, 5e8)
+	if got := string(c.append(nil, neg, TimeFormat)); got != neg.Format(TimeFormat) {
+		t.Fatal("bad time before epoch:", got)
+	}
+	if got := string((*timeCache)(nil).append(nil, base, TimeFormat)); got != base.Format(TimeFormat) {
+		t.Fatal("bad uncached time:", got)
+	}
+
+	// coarse window for a layout without seconds
+	c = &timeCache{every: int64(time.Minute)}
+	for s := 0; s < 60; s += 7 {
+		tm := base.Add(time.Duration(s)*time.Second + 3e6)
+		if got := string(c.append(nil, tm, "15:04.000")); got != tm.Format("15:04.000") {
+			t.Fatal("bad time in minute window:", got)
+		}
+	}
+}
+
+func TestSetTimeCache(t *testing.T) {
+	var buf bytes.Buffer
+	lg := New(": tc:", &buf, Sinfo)
+	if lg.tcache == nil || lg.tcache.every != int64(time.Second) {
+		t.Fatal("time cache must be enabled by default")
+	}
+	lg.SetTimeCache(0)
+	if lg.tcache != nil {
+		t.Fatal("time cache must be disabled")
+	}
+	lg.SetTimeCache(time.Millisecond)
+
+	before := time.Now()
+	lg.Log(Sinfo, "cached")
+	lg.Log(Sinfo, "cached")
+	after := time.Now()
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		tm, err := time.ParseInLocation(TimeFormat, line[:len(TimeFormat)], time.Local)
+		if err != nil || tm.Before(before.Truncate(time.Microsecond)) || tm.After(after) {
+			t.Fatal("bad cached time:", line, err)
+		}
+	}
+}
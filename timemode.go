@@ -0,0 +1,56 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"strconv"
+	"time"
+)
+
+// TimeMode is the rendering of record times
+type TimeMode uint32
+
+// time modes
+const (
+	Tdefault     TimeMode = iota // encoder's format: TimeFormat for text, RFC 3339 otherwise
+	Trfc3339nano                 // RFC 3339 with nanoseconds
+	Tunix                        // seconds since Unix epoch
+	Tunixmilli                   // milliseconds since Unix epoch
+	Tnone                        // omit time, for environments that add their own
+)
+
+// SetTimeMode sets rendering of record times for built-in encoders, invalid modes are
+// ignored. Epoch modes are written as numbers. Tnone omits time (and its separator or
+// key) from records, for example when running under systemd or a container runtime that
+// timestamps lines itself. It should be called before Logger is used.
+func (lg *Logger) SetTimeMode(mode TimeMode) {
+	if mode <= Tnone {
+		lg.timeMode = mode
+	}
+}
+
+// AppendTime appends Time of record rendered according to time mode of its Logger (see
+// SetTimeMode), with layout for Tdefault. Appends nothing for Tnone. Custom encoders can
+// use it to honor time modes.
+func (r *Record) AppendTime(buf []byte, layout string) []byte {
+	switch r.tmode {
+	case Trfc3339nano:
+		return r.tcache.append(buf, r.Time, time.RFC3339Nano)
+	case Tunix:
+		return strconv.AppendInt(buf, r.Time.Unix(), 10)
+	case Tunixmilli:
+		return strconv.AppendInt(buf, r.Time.UnixNano()/1e6, 10)
+	case Tnone:
+		return buf
+	}
+	return r.tcache.append(buf, r.Time, layout)
+}
+
+// epochTime reports whether record time is rendered as a number
+func (r *Record) epochTime() bool {
+	return r.tmode == Tunix || r.tmode == Tunixmilli
+}
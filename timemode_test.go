@@ -0,0 +1,68 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeMode(t *testing.T) {
+	var buf bytes.Buffer
+	lg := New(": tm:", &buf, Sinfo)
+
+	lg.SetTimeMode(Tunix)
+	before := time.Now().Unix()
+	lg.Log(Sinfo, "unix")
+	s := buf.String()
+	sec, err := strconv.ParseInt(s[:strings.IndexByte(s, ':')], 10, 64)
+	if err != nil || sec < before || sec > before+1 {
+		t.Fatal("expected unix seconds:", s)
+	}
+
+	buf.Reset()
+	lg.SetTimeMode(Tunixmilli)
+	lg.SetFormat(Fjson)
+	lg.Log(Sinfo, "millis")
+	if s = buf.String(); !strings.HasPrefix(s, `{"time":1`) || len(s) < 22 || s[21] != ',' {
+		t.Fatal("expected numeric milliseconds:", s)
+	}
+
+	buf.Reset()
+	lg.SetTimeMode(Trfc3339nano)
+	lg.SetFormat(Ftext)
+	lg.Log(Sinfo, "rfc")
+	s = buf.String()
+	if _, err = time.Parse(time.RFC3339Nano, s[:strings.Index(s, ": tm:")]); err != nil {
+		t.Fatal("expected RFC 3339 time:", s)
+	}
+
+	buf.Reset()
+	lg.SetTimeMode(Tnone)
+	lg.Log(Sinfo, "none")
+	lg.SetFormat(Fjson)
+	lg.Log(Sinfo, "none")
+	lg.SetEncoder(LogfmtEncoder{})
+	lg.Log(Sinfo, "none")
+	if s = buf.String(); !strings.HasPrefix(s, "tm:info: ") ||
+		!strings.Contains(s, "\n{\"name\":\"tm\",") || !strings.Contains(s, "\nlevel=info ") {
+		t.Fatal("time must be omitted:", s)
+	}
+
+	lg.SetTimeMode(Tnone + 1)
+	if lg.timeMode != Tnone {
+		t.Fatal("invalid mode must be ignored")
+	}
+
+	r := Record{Time: time.Unix(1, 0)}
+	if b := r.AppendTime(nil, "05"); string(b) != "01" {
+		t.Fatal("default mode must use layout:", string(b))
+	}
+}
@@ -0,0 +1,94 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"errors"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// ErrTimeout is returned by TimeoutWriter when a write does not complete in time
+var ErrTimeout = errors.New("yell: write timed out")
+
+// TimeoutWriter protects callers from slow or hung writers (like network or NFS files)
+// by waiting each write for at most a timeout. At most one write is in progress on the
+// underlying writer, so writes are not reordered. Writes that time out are counted and
+// go to the optional fallback writer (like os.Stderr). A hung write is not cancelled and
+// may still complete later, so its record can appear in both writers.
+type TimeoutWriter struct {
+	timeouts uint64 // accessed atomically, first for alignment
+
+	writer   io.Writer
+	fallback io.Writer
+	timeout  time.Duration
+	busy     chan struct{} // holds a token while a write is in progress
+}
+
+// NewTimeoutWriter creates a TimeoutWriter for writer with timeout & optional fallback.
+// Panics if writer is nil or timeout is not positive.
+func NewTimeoutWriter(writer io.Writer, timeout time.Duration,
+	fallback io.Writer) *TimeoutWriter {
+
+	if writer == nil || timeout <= 0 {
+		panic("yell: invalid arguments to NewTimeoutWriter")
+	}
+	return &TimeoutWriter{writer: writer, fallback: fallback, timeout: timeout,
+		busy: make(chan struct{}, 1)}
+}
+
+// write result
+type result struct {
+	n   int
+	err error
+}
+
+// Write writes p to underlying writer, waiting for at most the timeout. On timeout, p
+// is written to fallback writer if there is one, otherwise ErrTimeout is returned. If the
+// write of p has started, it may still complete, so p can appear in both writers.
+func (tw *TimeoutWriter) Write(p []byte) (int, error) {
+	timer := time.NewTimer(tw.timeout)
+	defer timer.Stop()
+
+	select {
+	case tw.busy <- struct{}{}:
+	case <-timer.C: // previous write is still in progress
+		return tw.timedOut(p)
+	}
+
+	// p may be reused by caller after a timeout
+	cp := append([]byte(nil), p...)
+	done := make(chan result, 1)
+
+	go func() {
+		n, err := tw.writer.Write(cp)
+		<-tw.busy
+		done <- result{n, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.n, r.err
+	case <-timer.C:
+		return tw.timedOut(p)
+	}
+}
+
+// timedOut counts a timeout and tries fallback writer
+func (tw *TimeoutWriter) timedOut(p []byte) (int, error) {
+	atomic.AddUint64(&tw.timeouts, 1)
+	if tw.fallback == nil {
+		return 0, ErrTimeout
+	}
+	return tw.fallback.Write(p)
+}
+
+// Timeouts returns number of writes that timed out
+func (tw *TimeoutWriter) Timeouts() uint64 {
+	return atomic.LoadUint64(&tw.timeouts)
+}
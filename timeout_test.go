@@ -0,0 +1,56 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// hungWriter blocks writes until released
+type hungWriter struct {
+	release, done chan bool
+	sb            strings.Builder
+}
+
+func (h *hungWriter) Write(p []byte) (int, error) {
+	<-h.release
+	n, err := h.sb.Write(p)
+	h.done <- true
+	return n, err
+}
+
+func TestTimeoutWriter(t *testing.T) {
+	h := &hungWriter{release: make(chan bool, 1), done: make(chan bool, 2)}
+	h.release <- true
+	tw := NewTimeoutWriter(h, 50*time.Millisecond, nil)
+	lg := New(": tmo:", tw, Sinfo)
+
+	if err := lg.Log(Sinfo, "fast"); err != nil || tw.Timeouts() != 0 {
+		t.Fatal("must write in time", err)
+	}
+
+	// hung write, then busy writer
+	if err := lg.Log(Sinfo, "hung"); !errors.Is(err, ErrTimeout) || tw.Timeouts() != 1 {
+		t.Fatal("must time out", err)
+	}
+	var fb strings.Builder
+	tw.fallback = &fb
+	if err := lg.Log(Swarn, "busy"); err != nil || tw.Timeouts() != 2 ||
+		!strings.HasSuffix(fb.String(), " busy\n") {
+		t.Fatal("must fall back", err)
+	}
+
+	h.release <- true // hung write completes
+	<-h.done
+	<-h.done
+	if !strings.Contains(h.sb.String(), " hung\n") {
+		t.Fatal("hung write must complete")
+	}
+}
@@ -0,0 +1,29 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import "time"
+
+// Timer starts timing an operation and returns a function that logs message list (like
+// operation name) with severity level and an "elapsed" field when called, typically
+// deferred:
+//
+//	func handle() {
+//		defer lg.Timer(yell.Sinfo, "handle", yell.Any("user", id))()
+//		...
+//	}
+//
+// Request location is the function that calls the returned function. Settings of Logger
+// (like level) at the time of that call apply.
+func (lg *Logger) Timer(level Severity, msg ...interface{}) func() {
+	start := time.Now()
+	return func() {
+		elapsed := time.Since(start)
+		lg.log(0, 0, nil, level,
+			append(msg[:len(msg):len(msg)], Any("elapsed", elapsed)), nil)
+	}
+}
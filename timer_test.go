@@ -0,0 +1,37 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestTimer(t *testing.T) {
+	var recs []Record
+	lg := New(": timer:", ioutil.Discard, Sinfo)
+	lg.AddObserver(func(r Record) { recs = append(recs, r) })
+
+	func() {
+		defer lg.Timer(Swarn, "op", Any("k", 1))()
+		time.Sleep(time.Millisecond)
+	}()
+	lg.Timer(Sdebug, "disabled")()
+	late := lg.Timer(Sinfo, "late")
+	lg.SetLevel(Swarn)
+	late()
+
+	if len(recs) != 1 {
+		t.Fatal("expected one record:", recs)
+	}
+	r := recs[0]
+	if r.Level != Swarn || r.Msg != "op" || r.File != "timer_test.go" || len(r.Fields) != 2 ||
+		r.Fields[1].Key != "elapsed" || r.Fields[1].Value.(time.Duration) < time.Millisecond {
+		t.Fatal("unexpected record:", r)
+	}
+}
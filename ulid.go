@@ -0,0 +1,88 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"crypto/rand"
+	"sync"
+	"time"
+)
+
+// SetID enables or disables unique record identifiers. When enabled, each record gets
+// a ULID (https://github.com/ulid/spec), a 26 character identifier sortable by time, so
+// records can be referenced & cross-linked between sinks. It should be called before
+// Logger is used.
+func (lg *Logger) SetID(on bool) {
+	lg.id = on
+}
+
+// NewID returns a new ULID like record identifiers, for example as a correlation ID
+func NewID() string {
+	return newULID(time.Now())
+}
+
+// Crockford's base32 alphabet
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// monotonic ULID generator state
+var ulidGen struct {
+	sync.Mutex
+	ms      uint64   // last timestamp
+	entropy [10]byte // last random part
+}
+
+// newULID returns a ULID for t. ULIDs within the same millisecond are monotonic.
+func newULID(t time.Time) string {
+	ms := uint64(t.UnixNano() / int64(time.Millisecond))
+	var id [16]byte
+
+	ulidGen.Lock()
+	if ms > ulidGen.ms {
+		ulidGen.ms = ms
+		if _, err := rand.Read(ulidGen.entropy[:]); err != nil {
+			// fall back to time based entropy
+			n := uint64(time.Now().UnixNano())
+			for i := range ulidGen.entropy {
+				ulidGen.entropy[i] = byte(n >> uint(i*6))
+			}
+		}
+	} else {
+		// same (or earlier) millisecond, increment entropy
+		ms = ulidGen.ms
+		for i := len(ulidGen.entropy) - 1; i >= 0; i-- {
+			ulidGen.entropy[i]++
+			if ulidGen.entropy[i] != 0 {
+				break
+			}
+		}
+	}
+	copy(id[6:], ulidGen.entropy[:])
+	ulidGen.Unlock()
+
+	for i := 5; i >= 0; i-- {
+		id[i] = byte(ms)
+		ms >>= 8
+	}
+	return encodeULID(&id)
+}
+
+// encodeULID encodes 128 bits as 26 base32 characters
+func encodeULID(id *[16]byte) string {
+	var s [26]byte
+	// 130 bits, top 2 bits are zero
+	hi := uint64(id[0])<<56 | uint64(id[1])<<48 | uint64(id[2])<<40 | uint64(id[3])<<32 |
+		uint64(id[4])<<24 | uint64(id[5])<<16 | uint64(id[6])<<8 | uint64(id[7])
+	lo := uint64(id[8])<<56 | uint64(id[9])<<48 | uint64(id[10])<<40 | uint64(id[11])<<32 |
+		uint64(id[12])<<24 | uint64(id[13])<<16 | uint64(id[14])<<8 | uint64(id[15])
+
+	for i := 25; i >= 0; i-- {
+		s[i] = crockford[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(s[:])
+}
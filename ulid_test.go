@@ -0,0 +1,55 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestULID(t *testing.T) {
+	var id [16]byte
+	if s := encodeULID(&id); s != "00000000000000000000000000" {
+		t.Fatal("unexpected ULID:", s)
+	}
+	for i := range id {
+		id[i] = 255
+	}
+	if s := encodeULID(&id); s != "7ZZZZZZZZZZZZZZZZZZZZZZZZZ" {
+		t.Fatal("unexpected ULID:", s)
+	}
+
+	// spec example timestamp 1469918176385 encodes to 01ARYZ6S41
+	id = [16]byte{0x01, 0x56, 0x3d, 0xf3, 0x64, 0x81}
+	if s := encodeULID(&id); s != "01ARYZ6S410000000000000000" {
+		t.Fatal("unexpected ULID:", s)
+	}
+
+	now := time.Now()
+	prev := newULID(now)
+	for i := 0; i < 100; i++ {
+		id := newULID(now)
+		if len(id) != 26 || id <= prev {
+			t.Fatal("ULIDs must be monotonic:", prev, id)
+		}
+		prev = id
+	}
+	if id := NewID(); len(id) != 26 || id <= prev {
+		t.Fatal("bad NewID:", id)
+	}
+
+	var sb strings.Builder
+	lg := New(": id:", &sb, Sinfo)
+	lg.SetID(true)
+	if err := lg.Log(Sinfo, "msg"); err != nil {
+		t.Fatal(err)
+	}
+	if i := strings.Index(sb.String(), " id="); i < 0 || len(sb.String()) != i+4+26+1 {
+		t.Fatal("unexpected record:", sb.String())
+	}
+}
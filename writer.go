@@ -0,0 +1,41 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"io"
+	"strings"
+)
+
+// Writer returns an io.Writer that logs each line of written data as a record with
+// severity level, for example to capture output of a subprocess:
+//
+//	cmd.Stderr = lg.Writer(yell.Serror)
+//
+// Empty lines are ignored and lines split across Write calls become separate records.
+// Request location is the caller of Write. Later changes to settings of Logger (like its
+// level or writer) apply to the returned writer. It always reports success.
+func (lg *Logger) Writer(level Severity) io.Writer {
+	return &lineWriter{lg, level}
+}
+
+// lineWriter logs lines written to it
+type lineWriter struct {
+	lg    *Logger
+	level Severity
+}
+
+// Write logs each non-empty line of p, it must be called directly by the user of
+// lineWriter for correct caller depth.
+func (w *lineWriter) Write(p []byte) (int, error) {
+	for _, line := range strings.Split(string(p), "\n") {
+		if line = strings.TrimSuffix(line, "\r"); line != "" {
+			w.lg.log(0, 0, nil, w.level, []interface{}{line}, nil)
+		}
+	}
+	return len(p), nil
+}
@@ -0,0 +1,33 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"fmt"
+	"io/ioutil"
+	"testing"
+)
+
+func TestWriter(t *testing.T) {
+	var recs []Record
+	lg := New(": wr:", ioutil.Discard, Sinfo)
+	lg.AddObserver(func(r Record) { recs = append(recs, r) })
+
+	w := lg.Writer(Swarn)
+	if n, err := w.Write([]byte("first\r\n\nsecond\n")); n != 15 || err != nil {
+		t.Fatal("bad write", n, err)
+	}
+	fmt.Fprint(w, "third")
+	lg.Writer(Sdebug).Write([]byte("ignored\n"))
+	lg.SetLevel(Serror)
+	w.Write([]byte("dropped\n"))
+
+	if len(recs) != 3 || recs[0].Msg != "first" || recs[1].Msg != "second" ||
+		recs[2].Msg != "third" || recs[0].Level != Swarn || recs[0].File != "writer_test.go" {
+		t.Fatal("unexpected records:", recs)
+	}
+}
@@ -0,0 +1,69 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"io"
+	"net"
+	"unsafe"
+)
+
+// SetWritev enables writing records with message texts of at least threshold bytes to
+// TCP & Unix connections as header, message & trailer segments with single writev calls,
+// so large messages are not copied into record buffers. It applies to TextEncoder without
+// Indent, and Loggers without rings (see SetRing & SetFlightRecorder). Zero or negative
+// threshold disables it, which is the default. It should be called before Logger is used.
+func (lg *Logger) SetWritev(threshold int) {
+	if threshold < 0 {
+		threshold = 0
+	}
+	lg.writev = threshold
+}
+
+// writevConn reports whether writes of net.Buffers to w use writev
+func writevConn(w io.Writer) bool {
+	switch w.(type) {
+	case *net.TCPConn, *net.UnixConn:
+		return true
+	}
+	return false
+}
+
+// segments returns Text of record if it should be written in segments to out
+func (lg *Logger) segments(out *output, r *Record) (TextEncoder, string, bool) {
+	e, ok := out.enc.(TextEncoder)
+	if !ok || e.Indent || lg.ring != nil || lg.flight != nil || !writevConn(out.writer) {
+		return e, "", false
+	}
+	m := r.Text()
+	return e, m, len(m) >= lg.writev
+}
+
+// writeSegments writes record with message text m in segments, buffering its header &
+// trailer in *bp
+func (out *output) writeSegments(e TextEncoder, r *Record, m string, bp *[]byte) error {
+	buf := append(e.appendHead((*bp)[:0], r), ' ')
+	h := len(buf)
+	buf = e.appendTail(buf, r)
+	*bp = buf
+
+	bufs := net.Buffers{buf[:h], stringBytes(m), buf[h:]}
+	if out.lc != nil {
+		out.lc.Lock()
+		defer out.lc.Unlock()
+	}
+	_, err := bufs.WriteTo(out.writer)
+	return err
+}
+
+// stringBytes returns bytes of s without copying, they must not be modified
+func stringBytes(s string) []byte {
+	return *(*[]byte)(unsafe.Pointer(&struct {
+		string
+		c int
+	}{s, len(s)}))
+}
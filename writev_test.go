@@ -0,0 +1,79 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yell
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestWritev(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip("no loopback:", err)
+	}
+	defer ln.Close()
+	got := make(chan []byte)
+	go func() {
+		c, err := ln.Accept()
+		if err != nil {
+			got <- nil
+			return
+		}
+		b, _ := ioutil.ReadAll(c)
+		c.Close()
+		got <- b
+	}()
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var want bytes.Buffer
+	lg := New(": wv:", conn, Sinfo)
+	ref := New(": wv:", &want, Sinfo)
+	long := strings.Repeat("large message ", 100)
+	for _, l := range []*Logger{&lg, &ref} {
+		l.SetTimeMode(Tnone)
+		l.SetSequence(true)
+		l.SetWritev(64)
+		l.Log(Sinfo, "short", Any("k", 1))
+		l.Log(Swarn, long, Int("n", 2), errors.New("failed"))
+		l.Log(Serror, long)
+	}
+	conn.Close()
+
+	if b := <-got; !bytes.Equal(b, want.Bytes()) {
+		t.Fatalf("segmented records differ:\n%s\n%s", b, want.Bytes())
+	}
+
+	out := &output{writer: conn, enc: TextEncoder{}}
+	r := Record{Msg: long}
+	if _, m, ok := lg.segments(out, &r); !ok || m != long {
+		t.Fatal("long message must be segmented")
+	}
+	out.enc = TextEncoder{Indent: true}
+	if _, _, ok := lg.segments(out, &r); ok {
+		t.Fatal("indented messages must not be segmented")
+	}
+	out = &output{writer: &want, enc: TextEncoder{}}
+	if _, _, ok := lg.segments(out, &r); ok {
+		t.Fatal("segments need a network connection")
+	}
+
+	lg.SetWritev(-1)
+	if lg.writev != 0 {
+		t.Fatal("writev must be disabled")
+	}
+	if b := stringBytes("abc"); string(b) != "abc" || len(stringBytes("")) != 0 {
+		t.Fatal("bad string bytes")
+	}
+}
@@ -4,27 +4,32 @@
 	file, You can obtain one at http://mozilla.org/MPL/2.0/.
 */
 
-// Package yell is yet another minimalist logging library. It provides four severity
+// Package yell is yet another minimalist logging library. It provides five severity
 // levels, simple API, io.Writer & sync.Locker support, package-specific loggers,
 // customizations (severity names, time format, local or UTC time), easy & granular
 // request location (file.go:line) logging.
 package yell
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
-	"runtime"
+	"strings"
+	"sync/atomic"
 	"time"
+	"unicode"
 )
 
 // Severity is log severity type
 type Severity uint32
 
-// log severity levels
+// log severity levels. Sdebug was added in v2, which renumbered the other levels and
+// Sname: level numbers of v1 must be incremented, see README.
 const (
-	Sinfo Severity = iota
+	Sdebug Severity = iota
+	Sinfo
 	Swarn
 	Serror
 	Sfatal
@@ -32,7 +37,7 @@ const (
 )
 
 // Sname is the list of severity names (in increasing severity) that appear in logs
-var Sname = [...]string{"info:", "warn:", "error:", "fatal:"}
+var Sname = [...]string{"debug:", "info:", "warn:", "error:", "fatal:"}
 
 // TimeFormat in logs
 var TimeFormat = "2006-01-02 15:04:05.000000"
@@ -45,7 +50,7 @@ var UTC = false
 //
 //  import (
 //  	"os"
-//  	"github.com/jfcg/yell"
+//  	"github.com/jfcg/yell/v2"
 //  )
 //
 //  // log to stdout with warn or higher severity (for example).
@@ -68,42 +73,200 @@ var UTC = false
 //  	return
 //  }
 //
-//  // Fatal tries to log message list with fatal severity and panics
-//  func Fatal(msg ...interface{}) (err error) {
-//  	err = Logger.Log(yell.Sfatal, msg...)
-//  	pm := Logger.Name() + yell.Sname[yell.Sfatal]
-//  	if err != nil {
-//  		pm += err.Error()
-//  	}
-//  	// probably panic or os.Exit(1) in a fatal situation
-//  	panic(pm)
+//  // Fatal logs message list with fatal severity, flushes & closes Loggers, and exits
+//  func Fatal(msg ...interface{}) {
+//  	Logger.FatalExit(1, append([]interface{}{yell.Caller(1)}, msg...)...)
 //  }
 type Logger struct {
-	// name of package or application, must be of the form ": mypkg:"
+	// out holds *output, swapped atomically
+	out atomic.Value
+
+	// minLevel is minimum severity for logging, accessed atomically
+	minLevel Severity
+
+	// maxLevel is maximum severity for logging, accessed atomically
+	maxLevel Severity
+
+	settings
+}
+
+// settings of Logger that are not accessed atomically, copied as is by derive
+type settings struct {
+	// name of package or application, like mypkg
 	name string
 
-	// writer is used to log messages, can also be sync.Locker, must not be nil
-	writer io.Writer
+	// delims around name, ": " & ":" for names of the form ": mypkg:", see SetNameDelims
+	delims [2]string
 
-	// minLevel is minimum severity for logging
-	minLevel Severity
+	// escape control characters in message lists
+	escape bool
+
+	// color settings of SetColor
+	colorMode ColorMode
+	colorName bool
+
+	// labels is the severity label style, see SetLabels
+	labels LabelStyle
+
+	// indent enables indentation of multi-line messages, see SetIndent
+	indent bool
+
+	// pretty printing of composite members at or below prettyMax, see SetPretty
+	pretty    PrettyMode
+	prettyMax Severity
+
+	// observers of records
+	observers []Observer
+
+	// enrichers add fields to records
+	enrichers []Enricher
+
+	// seq is the sequence counter, nil if disabled
+	seq *uint64
+
+	// id enables record identifiers
+	id bool
+
+	// detail enables error details
+	detail bool
+
+	// sortFields enables sorting of record fields by key
+	sortFields bool
+
+	// onError is called for failed records
+	onError func(error)
+
+	// stats are shared by copies of Logger
+	stats *stats
+
+	// timeMode is the rendering of record times
+	timeMode TimeMode
+
+	// tcache is the formatted time cache, nil if disabled
+	tcache *timeCache
+
+	// writev is the minimum message length for segmented writes, zero if disabled
+	writev int
+
+	// syncLevel is the minimum severity written synchronously to async writers
+	syncLevel Severity
+
+	// development enables panics of DPanic
+	development bool
+
+	// rate monitors error records, nil if disabled
+	rate *rateMonitor
+
+	// skip is the extra caller depth, see WithCallerSkip
+	skip int
+
+	// wrappers are packages skipped by automatic caller detection, nil if disabled
+	wrappers []string
+
+	// sampler limits records, nil if disabled
+	sampler *sampler
+
+	// ring retains all records, nil if disabled
+	ring *Ring
+
+	// flight buffers records below trigger severity, nil if disabled
+	flight  *Ring
+	trigger Severity
 }
 
-// New creates a Logger with package/application name (must be of the form ": mypkg:"),
+// New creates a Logger with package/application name (of the form ": mypkg:", see NewE),
 // writer to log (which can also implement sync.Locker to protect logging) and minimum
 // severity level to log. Panics if arguments are invalid.
 func New(name string, writer io.Writer, minLevel Severity) Logger {
+	lg, err := NewE(name, writer, minLevel)
+	if err != nil {
+		panic(err.Error())
+	}
+	return lg
+}
+
+// NewE is like New but returns an error describing invalid arguments instead of
+// panicking, for Loggers built from user configuration. Besides the ": mypkg:" form, name
+// can be any non-empty printable text like mypkg, which is used as is without delimiters,
+// see Name & SetNameDelims.
+func NewE(name string, writer io.Writer, minLevel Severity) (Logger, error) {
 	l := len(name) - 1
-	if l < 3 || name[0] != ':' || name[1] != ' ' || name[2] <= ' ' ||
-		name[l-1] <= ' ' || name[l] != ':' || writer == nil || minLevel > Snolog {
-		panic("yell: invalid arguments to New")
+	legacy := l >= 3 && name[0] == ':' && name[1] == ' ' && name[l] == ':'
+	switch {
+	case legacy && (name[2] <= ' ' || name[l-1] <= ' '):
+		return Logger{}, fmt.Errorf("yell: logger name %q must not start or end with space", name)
+	case name == "" || strings.IndexFunc(name, notPrint) >= 0:
+		return Logger{}, fmt.Errorf("yell: logger name %q must be non-empty printable text", name)
+	case writer == nil:
+		return Logger{}, errors.New("yell: nil writer")
+	case minLevel > Snolog:
+		return Logger{}, fmt.Errorf("yell: invalid minimum level %d", minLevel)
+	}
+	var delims [2]string
+	if legacy {
+		name, delims = name[2:l], nameDelims
 	}
-	return Logger{name, writer, minLevel}
+	return newLogger(name, delims, writer, minLevel), nil
+}
+
+// nameDelims are delimiters of names of the form ": mypkg:"
+var nameDelims = [2]string{": ", ":"}
+
+// notPrint reports whether r is not printable
+func notPrint(r rune) bool {
+	return !unicode.IsPrint(r)
+}
+
+// newLogger creates a Logger with bare name & its delimiters without validation
+func newLogger(name string, delims [2]string, writer io.Writer,
+	minLevel Severity) (lg Logger) {
+
+	lg.name, lg.delims = name, delims
+	lg.minLevel, lg.maxLevel, lg.stats = minLevel, Sfatal, new(stats)
+	lg.tcache = &timeCache{every: int64(time.Second)}
+	lg.syncLevel = Sfatal
+	lg.setOutput(writer, TextEncoder{})
+	return
+}
+
+// output is an immutable writer & encoder pair of Logger
+type output struct {
+	// writer is used to log messages, can also be sync.Locker, must not be nil
+	writer io.Writer
+	lc     locker // writer as sync.Locker, or nil
+
+	enc Encoder // must not be nil
+
+	discard bool // writer discards records, see Discarder
+}
+
+// setOutput atomically replaces Logger's writer & encoder
+func (lg *Logger) setOutput(writer io.Writer, enc Encoder) {
+	lc, _ := writer.(locker)
+	lg.out.Store(&output{writer, lc, enc, discards(writer)})
+}
+
+// output returns current writer & encoder of Logger
+func (lg *Logger) output() *output {
+	return lg.out.Load().(*output)
 }
 
-// Name of Logger, skipping ": "
+// Name of Logger with its closing delimiter, like mypkg: for ": mypkg:", or the name
+// as is if it has no delimiters, see NewE
 func (lg *Logger) Name() string {
-	return lg.name[2:]
+	return lg.name + lg.delims[1]
+}
+
+// SetNameDelims sets delimiters around Logger name in text records, which are ": " & ":"
+// for names of the form ": mypkg:" and none for other names (see NewE). Opening delimiter
+// follows time and is omitted without time (see Tnone). Empty delimiters are written as
+// a space, so "" & "" give records like:
+//
+//	2021-03-28 21:48:53.591948 mypkg info: myApp.go:15: some info
+//
+// It should be called before Logger is used.
+func (lg *Logger) SetNameDelims(opening, closing string) {
+	lg.delims = [2]string{opening, closing}
 }
 
 // for not importing sync
@@ -112,42 +275,50 @@ type locker interface {
 	Unlock()
 }
 
-// UpdateWriter tries to update Logger's writer. If both old & new writers implement
-// sync.Locker, they must resolve to the same locker. Otherwise UpdateWriter refuses
-// to update because old locker could still be in use in Log() calls while we update.
-// Returns true on successful update.
+// UpdateWriter updates Logger's writer, which can also implement sync.Locker to protect
+// logging. It is safe to call UpdateWriter while Logger is in use, Log() calls in progress
+// complete with the old writer (and its locker). Returns false if writer is nil.
 func (lg *Logger) UpdateWriter(writer io.Writer) (success bool) {
 	if writer == nil {
 		return false
 	}
 
-	// see if writers are also sync.Locker
-	if lc, ok := lg.writer.(locker); ok {
-
-		if lc2, ok := writer.(locker); ok && lc != lc2 {
-			return false // different lockers
-		}
-
-		lc.Lock()
-		defer lc.Unlock()
+	enc := lg.output().enc
+	if lg.colorMode == Cauto {
+		enc = lg.colorize(writer, enc)
 	}
-
-	lg.writer = writer
+	lg.setOutput(writer, enc)
 	return true
 }
 
-// SetLevel sets minimum severity level for logging
+// SetLevel sets minimum severity level for logging. It is safe to call SetLevel while
+// Logger is in use.
 func (lg *Logger) SetLevel(level Severity) {
 	if level > Snolog {
-		lg.minLevel = Snolog
-	} else {
-		lg.minLevel = level
+		level = Snolog
 	}
+	atomic.StoreUint32((*uint32)(&lg.minLevel), uint32(level))
 }
 
 // GetLevel returns minimum severity level for logging
 func (lg *Logger) GetLevel() Severity {
-	return lg.minLevel
+	return Severity(atomic.LoadUint32((*uint32)(&lg.minLevel)))
+}
+
+// SetMaxLevel sets maximum severity level for logging, Sfatal (default) means no cap.
+// For example, a Logger can write debug & info records to os.Stdout while another one
+// writes warn and above to os.Stderr. It is safe to call SetMaxLevel while Logger is in
+// use.
+func (lg *Logger) SetMaxLevel(level Severity) {
+	if level > Sfatal {
+		level = Sfatal
+	}
+	atomic.StoreUint32((*uint32)(&lg.maxLevel), uint32(level))
+}
+
+// GetMaxLevel returns maximum severity level for logging
+func (lg *Logger) GetMaxLevel() Severity {
+	return Severity(atomic.LoadUint32((*uint32)(&lg.maxLevel)))
 }
 
 // Caller type allows to log request location (file.go:line) with more granularity like:
@@ -163,81 +334,277 @@ type Caller int
 // location (file.go:line) in records, so it must be called as described in Logger doc.
 // If Logger.writer also implements sync.Locker, Lock/Unlock is used to protect logging.
 // First member of message list can be caller depth, which must be 1 or more, otherwise
-// it is ignored. See Caller doc.
-func (lg *Logger) Log(level Severity, msg ...interface{}) (err error) {
+// it is ignored. See Caller doc. Field members of message list are attached to record
+// as fields, see Any. Scope & context members are replaced with their fields, which
+// precede other fields, see Scope. Contexts can lower minimum severity of Logger for
+// their records, see WithMinLevel. Control characters in message list are escaped if
+// enabled with SetEscape. Lazy members are evaluated only if the record is logged. Log
+// builds a Record, calls observers and encodes it with Logger's Encoder. Failures are
+// returned as *LogError.
+func (lg *Logger) Log(level Severity, msg ...interface{}) error {
+	return lg.log(1, 0, nil, level, msg, nil)
+}
 
-	if !(lg.minLevel <= level && level < Snolog && 0 < len(msg)) {
+// LogTo is like Log but writes the record to writer (which can also implement
+// sync.Locker) instead of Logger's writer, for example to an audit file. Name, level
+// checks and encoding are the same. nil writer means Logger's writer.
+func (lg *Logger) LogTo(writer io.Writer, level Severity, msg ...interface{}) error {
+	return lg.log(1, 0, writer, level, msg, nil)
+}
+
+// logMode adjusts records of log
+type logMode uint8
+
+const (
+	mDetail    logMode = 1 << iota // error detail regardless of SetErrorDetail
+	mExact                         // skip is exact, WithCallerSkip & SetAutoCaller ignored
+	mStdlib                        // SetAutoCaller also skips log package frames
+	mUncounted                     // record is not counted nor monitored, see SetErrorRate
+)
+
+// log implements Log, LogTo, LogFields etc. Request location is skip frames above the
+// caller of log (0 is the caller of the function calling log), noCaller for records
+// without request location. fields are appended to fields of msg.
+func (lg *Logger) log(skip int, mode logMode, writer io.Writer, level Severity,
+	msg []interface{}, fields []Field) (err error) {
+
+	// records below minimum severity only go to ring, unless a context lowers it
+	logged := lg.GetLevel() <= level || scopeLevel(msg) <= level
+	if !((logged || lg.ring != nil) && level <= lg.GetMaxLevel() && 0 < len(msg)) {
 		return // ignored level or empty msg
 	}
 	now := time.Now() // call Now() asap
 
 	// consume caller depth if present
-	skip, cok := msg[0].(Caller)
+	depth, cok := msg[0].(Caller)
 	if cok {
 		if len(msg) == 1 {
 			return // empty msg
 		}
+		msg = msg[1:]
+
+		if depth < 0 {
+			depth = 0 // user must provide positive caller depth
+		} else if depth > 99 {
+			depth = 99 // avoid excessive caller depths
+		}
+	}
+	counted := mode&mUncounted == 0
 
-		if skip < 0 {
-			skip = 0 // user must provide positive caller depth
-		} else if skip > 99 {
-			skip = 99 // avoid excessive caller depths
+	// records to a discarding writer are only sampled & counted
+	if writer == nil && lg.observers == nil && lg.ring == nil && lg.flight == nil &&
+		lg.rate == nil && lg.output().discard {
+		if (lg.sampler == nil || lg.sampler.allow(level, now)) && counted {
+			atomic.AddUint64(&lg.stats.counts[level], 1)
 		}
+		return
+	}
+
+	// scopes & contexts are replaced by their fields
+	msg, bound := expandScopes(msg)
+	if len(msg) == 0 {
+		return // empty msg
+	}
+	if lg.enrichers != nil {
+		msg, bound = lg.enrich(msg, bound)
 	}
 
-	// prepare all input to Fprintln before possible locking
+	if logged && lg.sampler != nil && !lg.sampler.allow(level, now) {
+		if lg.ring == nil {
+			return // sampled out
+		}
+		logged = false
+	}
+	if logged && counted {
+		atomic.AddUint64(&lg.stats.counts[level], 1)
+		if lg.rate != nil && level >= Serror && lg.rate.exceeded(now) {
+			defer lg.rateAlert(skip, mode, int(depth))
+		}
+	}
+	msg = evalLazy(msg)
+	if lg.pretty != Pnone && level <= lg.prettyMax {
+		msg = prettify(msg, lg.pretty)
+	}
+
+	// prepare record before possible locking
 	if UTC {
 		now = now.UTC()
 	}
-	prem := now.Format(TimeFormat) + lg.name + Sname[level]
+	r := Record{Time: now, Level: level, Name: lg.name, Seq: lg.nextSeq(),
+		tmode: lg.timeMode, tcache: lg.tcache, delims: &lg.delims}
+	if lg.id {
+		r.ID = newULID(now)
+	}
 
 	// try to discover request location
-	_, file, line, ok := runtime.Caller(int(skip) + 2)
+	var (
+		file string
+		line int
+		ok   bool
+	)
+	switch {
+	case skip == noCaller: // background record
+	case mode&mExact != 0:
+		file, line, ok = caller(skip + 2)
+	case lg.wrappers != nil:
+		file, line, ok = lg.autoCaller(int(depth), mode&mStdlib != 0)
+	default:
+		file, line, ok = caller(skip + int(depth) + 2 + lg.skip)
+	}
 	if ok {
-		file = filepath.Base(file) // full path to file name
-		prem += fmt.Sprintf(" %s:%d:", file, line)
+		r.File, r.Line = file, line
 	}
 
-	// prepend prem to msg
-	if cok {
-		msg[0] = prem // avoid append when we have the Caller spot
+	if (lg.detail || mode&mDetail != 0) && level >= Serror {
+		r.Detail = errorDetail(msg)
+	}
+
+	// errors are kept separately for structured encoders
+	if r.Errs = messageErrors(msg); r.Errs != nil {
+		r.text, _ = splitFields(msg) // errors stay in place for text encoders
+		msg = withoutErrors(msg, len(r.Errs))
+	}
+
+	r.Msg, r.Fields = splitFields(msg)
+	if r.Fields == nil && !lg.sortFields {
+		r.Fields = fields // not modified
+	} else if fields != nil {
+		r.Fields = append(r.Fields, fields...)
+	}
+	if lg.sortFields {
+		sortFields(r.Fields, bound)
+	}
+	if lg.escape {
+		r.Msg = escape(r.Msg)
+		r.text = escape(r.text)
+	}
+
+	if logged && lg.observers != nil {
+		r.Fields = boxFields(r.Fields) // observers see values of typed fields
+		for _, obs := range lg.observers {
+			obs(r)
+		}
+	}
+
+	bp := bufPool.Get().(*[]byte)
+	defer putBuf(bp)
+
+	out := lg.output()
+	if writer != nil {
+		lc, _ := writer.(locker)
+		out = &output{writer: writer, lc: lc, enc: out.enc}
+	}
+	if lg.writev > 0 {
+		if e, m, ok := lg.segments(out, &r); ok {
+			if err = out.writeSegments(e, &r, m, bp); err != nil {
+				return lg.fail(OpWrite, &r, err)
+			}
+			return
+		}
+	}
+	*bp, err = out.enc.Encode((*bp)[:0], &r)
+	if !logged {
+		if err == nil {
+			lg.ring.Write(*bp)
+		}
+		return nil
+	}
+	if err != nil {
+		return lg.fail(OpEncode, &r, err)
+	}
+	if lg.ring != nil {
+		lg.ring.Write(*bp)
+	}
+	if lg.flight != nil && writer == nil { // LogTo records bypass flight recorder
+		if level < lg.trigger {
+			lg.flight.Write(*bp)
+			return // buffered until a trigger record
+		}
+		if err = lg.replay(out); err != nil {
+			return lg.fail(OpWrite, &r, err)
+		}
+	}
+
+	if level >= lg.syncLevel {
+		err = out.writeSync(level, *bp)
 	} else {
-		msg = append([]interface{}{prem}, msg...)
+		err = out.write(level, *bp)
+	}
+	if err != nil {
+		return lg.fail(OpWrite, &r, err)
 	}
+	return
+}
+
+// write p with level to writer, with locker if available. Snolog means level is unknown.
+func (out *output) write(level Severity, p []byte) (err error) {
 
 	// see if writer is also a sync.Locker
-	if lc, ok := lg.writer.(locker); ok {
+	if out.lc != nil {
 
-		lc.Lock() // lock just before logging
-		defer lc.Unlock()
+		out.lc.Lock() // lock just before logging
+		defer out.lc.Unlock()
 	}
 
-	_, err = fmt.Fprintln(lg.writer, msg...)
+	if lw, ok := out.writer.(LevelWriter); ok && level < Snolog {
+		_, err = lw.WriteLevel(level, p)
+		return
+	}
+	_, err = out.writer.Write(p)
 	return
 }
 
-// Default logger utilizes os.Args[0] for name, os.Stdout as writer, with warn severity
-var Default = Logger{": " + filepath.Base(os.Args[0]) + ":", os.Stdout, Swarn}
+// Default logger utilizes os.Args[0] for name, os.Stdout as writer, with warn severity.
+// Package-level functions like Info use it, unless another Logger is set with SetDefault.
+var Default = newLogger(filepath.Base(os.Args[0]), nameDelims, os.Stdout, Swarn)
+
+// current holds *Logger of package-level functions, nil means Default
+var current atomic.Value
+
+// SetDefault atomically installs lg as the Logger of package-level functions like Info,
+// so applications can put a fully configured Logger (JSON, file writer etc.) behind them.
+// nil lg means Default. It is safe to call SetDefault while package-level functions are
+// in use.
+func SetDefault(lg *Logger) {
+	if lg == nil {
+		lg = &Default
+	}
+	current.Store(lg)
+}
+
+// GetDefault returns the Logger of package-level functions, see SetDefault
+func GetDefault() *Logger {
+	if lg, _ := current.Load().(*Logger); lg != nil {
+		return lg
+	}
+	return &Default
+}
+
+// Debug tries to log message list with debug severity to Default logger
+func Debug(msg ...interface{}) error {
+	return GetDefault().Log(Sdebug, msg...)
+}
 
 // Info tries to log message list with info severity to Default logger
 func Info(msg ...interface{}) error {
-	return Default.Log(Sinfo, msg...)
+	return GetDefault().Log(Sinfo, msg...)
 }
 
 // Warn tries to log message list with warn severity to Default logger
 func Warn(msg ...interface{}) error {
-	return Default.Log(Swarn, msg...)
+	return GetDefault().Log(Swarn, msg...)
 }
 
 // Error tries to log message list with error severity to Default logger
 func Error(msg ...interface{}) error {
-	return Default.Log(Serror, msg...)
+	return GetDefault().Log(Serror, msg...)
 }
 
 // Fatal tries to log message list with fatal severity to Default logger and panics
 func Fatal(msg ...interface{}) (err error) {
-	err = Default.Log(Sfatal, msg...)
-	pm := Default.Name() + Sname[Sfatal]
+	lg := GetDefault()
+	err = lg.Log(Sfatal, msg...)
+	pm := lg.name + ":" + Sname[Sfatal]
 	if err != nil {
 		pm += err.Error()
 	}
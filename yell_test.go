@@ -8,8 +8,11 @@ package yell
 
 import (
 	"errors"
+	"io"
+	"io/ioutil"
 	"os"
 	"strings"
+	"sync"
 	"testing"
 )
 
@@ -44,10 +47,6 @@ func (m *myLocker) onlyWrite() bool {
 	return m.lo == 0 && m.wr == 1 && m.ul == 0
 }
 
-func (m *myLocker) onlyLock() bool {
-	return m.lo == 1 && m.wr == 0 && m.ul == 2
-}
-
 func (m *myLocker) all() bool {
 	return m.lo == 1 && m.wr == 2 && m.ul == 3
 }
@@ -76,7 +75,7 @@ func newPanics() (ok bool) {
 			ok = true
 		}
 	}()
-	_ = New("badName", os.Stdout, Sinfo)
+	_ = New("bad\nName", os.Stdout, Sinfo)
 	return
 }
 
@@ -183,19 +182,21 @@ func TestWL(t *testing.T) {
 	if !Default.UpdateWriter(&wl) {
 		t.Fatal("must update the writer")
 	}
-	if !wl.onlyLock() {
-		t.Fatal("must call lock/unlock only")
+	if !wl.isZero() {
+		t.Fatal("must not call any method")
 	}
-	wl.zero()
 
 	// different writer/locker
 	var wl2 myLocker
-	if Default.UpdateWriter(&wl2) {
-		t.Fatal("must not update with different locker")
+	if !Default.UpdateWriter(&wl2) || !Default.UpdateWriter(&wl) {
+		t.Fatal("must update with different locker")
 	}
-	if !wl.isZero() {
+	if !wl.isZero() || !wl2.isZero() {
 		t.Fatal("must not call any method")
 	}
+	if Default.UpdateWriter(nil) {
+		t.Fatal("must not update with nil writer")
+	}
 
 	// disable logging
 	Default.SetLevel(Snolog + 1) // to test invalid severity levels
@@ -225,3 +226,176 @@ func TestWL(t *testing.T) {
 		t.Fatal("must not log anything")
 	}
 }
+
+func TestSetLevelRace(t *testing.T) {
+	lg := New(": race:", ioutil.Discard, Sinfo)
+	done := make(chan bool)
+
+	go func() {
+		for i := 0; i < 1000; i++ {
+			lg.SetLevel(Severity(i % 5))
+		}
+		done <- true
+	}()
+
+	for i := 0; i < 1000; i++ {
+		if err := lg.Log(Swarn, "msg", i); err != nil {
+			t.Fatal(err)
+		}
+		if lg.GetLevel() > Snolog {
+			t.Fatal("invalid level")
+		}
+	}
+	<-done
+}
+
+func TestUpdateWriterRace(t *testing.T) {
+	var sb1, sb2 strings.Builder
+	w1, w2 := &lockedWriter{w: &sb1}, &lockedWriter{w: &sb2}
+	lg := New(": race:", w1, Sinfo)
+	done := make(chan bool)
+
+	go func() {
+		for i := 0; i < 1000; i++ {
+			if !lg.UpdateWriter(w2) || !lg.UpdateWriter(w1) {
+				t.Error("must update the writer")
+			}
+		}
+		done <- true
+	}()
+
+	for i := 0; i < 1000; i++ {
+		if err := lg.Log(Swarn, "msg", i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	<-done
+
+	if n := strings.Count(sb1.String(), "\n") + strings.Count(sb2.String(), "\n"); n != 1000 {
+		t.Fatal("lost records:", n)
+	}
+}
+
+// lockedWriter is a writer with its own locker
+type lockedWriter struct {
+	sync.Mutex
+	w io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	return l.w.Write(p)
+}
+
+func TestLogTo(t *testing.T) {
+	var main, audit strings.Builder
+	lg := New(": to:", &main, Swarn)
+
+	if err := lg.LogTo(&audit, Sinfo, "ignored level"); err != nil {
+		t.Fatal(err)
+	}
+	if err := lg.LogTo(&audit, Swarn, "audit", 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := lg.LogTo(nil, Serror, "main", 2); err != nil {
+		t.Fatal(err)
+	}
+	if main.Len() == 0 || strings.Contains(main.String(), "audit") ||
+		!strings.Contains(audit.String(), ": to:warn: testing.go:") ||
+		!strings.HasSuffix(audit.String(), " audit 1\n") {
+		t.Fatal("unexpected records:", main.String(), audit.String())
+	}
+}
+
+func TestMaxLevel(t *testing.T) {
+	var out, errs strings.Builder
+	lo := New(": cap:", &out, Sdebug)
+	hi := New(": cap:", &errs, Swarn)
+	lo.SetMaxLevel(Sinfo)
+
+	if lo.GetMaxLevel() != Sinfo || hi.GetMaxLevel() != Sfatal {
+		t.Fatal("unexpected max levels")
+	}
+	for l := Sdebug; l <= Snolog; l++ {
+		lo.Log(l, "msg")
+		hi.Log(l, "msg")
+	}
+	if strings.Count(out.String(), "\n") != 2 || strings.Count(errs.String(), "\n") != 3 ||
+		strings.Contains(out.String(), "warn") {
+		t.Fatal("unexpected records:", out.String(), errs.String())
+	}
+
+	lo.SetMaxLevel(Snolog)
+	if lo.GetMaxLevel() != Sfatal {
+		t.Fatal("invalid cap must mean no cap")
+	}
+}
+
+func TestNewE(t *testing.T) {
+	bad := [...]struct {
+		name   string
+		writer io.Writer
+		level  Severity
+		err    string
+	}{
+		{"", ioutil.Discard, Sinfo, "non-empty"},
+		{"a\nb", ioutil.Discard, Sinfo, "printable"},
+		{":  pkg:", ioutil.Discard, Sinfo, "space"},
+		{": pkg :", ioutil.Discard, Sinfo, "space"},
+		{": pkg:", nil, Sinfo, "nil writer"},
+		{": pkg:", ioutil.Discard, Snolog + 1, "level"},
+	}
+	for _, b := range bad {
+		if _, err := NewE(b.name, b.writer, b.level); err == nil ||
+			!strings.Contains(err.Error(), b.err) {
+			t.Fatal("unexpected error:", b.name, err)
+		}
+	}
+
+	lg, err := NewE(": pkg:", ioutil.Discard, Snolog)
+	if err != nil || lg.Name() != "pkg:" {
+		t.Fatal("must create Logger:", err)
+	}
+	lg, err = NewE("my pkg", ioutil.Discard, Snolog)
+	if err != nil || lg.Name() != "my pkg" || lg.name != "my pkg" {
+		t.Fatal("must accept relaxed name:", err)
+	}
+
+	// names without delimiters & custom delimiters in text records
+	var sb strings.Builder
+	lg, _ = NewE("mypkg", &sb, Sinfo)
+	lg.Log(Sinfo, Caller(1), "plain")
+	lg.SetNameDelims(" <", "> ")
+	lg.Log(Sinfo, Caller(1), "custom")
+	lg.SetTimeMode(Tnone)
+	lg.Log(Sinfo, Caller(1), "no time")
+	lines := strings.Split(sb.String(), "\n")
+	if len(lines) != 4 || !strings.Contains(lines[0], " mypkg info: ") ||
+		strings.Contains(lines[0], "mypkg:") || !strings.Contains(lines[1], " <mypkg> info: ") ||
+		!strings.HasPrefix(lines[2], "mypkg> info: ") {
+		t.Fatal("unexpected name delimiters:", sb.String())
+	}
+}
+
+func TestSetDefault(t *testing.T) {
+	var sb strings.Builder
+	lg := New(": def:", &sb, Sdebug)
+	lg.SetFormat(Fjson)
+	SetDefault(&lg)
+	defer SetDefault(nil)
+
+	if GetDefault() != &lg {
+		t.Fatal("Logger must be installed")
+	}
+	Debug("d")
+	Info("i")
+	InfoIf(true, "c")
+	if s := sb.String(); strings.Count(s, `"name":"def"`) != 3 ||
+		!strings.Contains(s, `"caller":"yell_test.go:`) {
+		t.Fatal("package-level functions must use installed Logger:", s)
+	}
+
+	SetDefault(nil)
+	if GetDefault() != &Default {
+		t.Fatal("nil must restore Default")
+	}
+}
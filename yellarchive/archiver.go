@@ -0,0 +1,260 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+// Package yellarchive accumulates yell records into gzip compressed chunks and uploads
+// them to object storage (S3, GCS etc.) on size & time thresholds, for cheap long-term
+// archival straight from the process. Storage clients are plugged in as Uploaders, see
+// the yells3 module for S3 & S3 compatible storage like GCS.
+package yellarchive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Uploader stores an object with name & body
+type Uploader interface {
+	Upload(ctx context.Context, name string, body []byte) error
+}
+
+// UploaderFunc is an Uploader function
+type UploaderFunc func(ctx context.Context, name string, body []byte) error
+
+// Upload calls f
+func (f UploaderFunc) Upload(ctx context.Context, name string, body []byte) error {
+	return f(ctx, name, body)
+}
+
+// defaults of Archiver
+const (
+	DefaultMaxBytes = 64 << 20 // uncompressed chunk size threshold
+	DefaultTimeout  = time.Minute
+	maxPending      = 16 // failed chunks kept for retry
+)
+
+// ErrClosed is returned by Write after Close
+var ErrClosed = errors.New("yellarchive: archiver is closed")
+
+// chunk is a compressed object waiting for upload
+type chunk struct {
+	name string
+	body []byte
+}
+
+// Archiver is an io.Writer that compresses records into chunks & uploads each chunk as an
+// object named like
+//
+//	prefix2021/03/28/184853-1.log.gz
+//
+// from UTC start time of chunk & a per Archiver counter. A chunk is uploaded when it has
+// MaxBytes of uncompressed records, when it gets older than max age and on Flush or Close.
+// Chunks that fail to upload are retried with the next one (the oldest are dropped if
+// too many fail). Errors of background uploads are returned by the next Write (which
+// keeps its record) or Flush.
+// Each Write should be a complete record, as Logger does. It is safe for concurrent use.
+type Archiver struct {
+	mu      sync.Mutex
+	upMu    sync.Mutex // serializes uploads, which do not hold mu
+	up      Uploader
+	prefix  string
+	maxAge  time.Duration
+	timer   *time.Timer
+	buf     bytes.Buffer
+	zw      *gzip.Writer
+	start   time.Time // start of current chunk, zero if empty
+	size    int       // uncompressed size of current chunk
+	count   uint64    // number of chunks
+	pending []chunk   // chunks that failed to upload
+	err     error     // error of last background upload
+	closed  bool
+
+	// MaxBytes is uncompressed chunk size threshold, zero means DefaultMaxBytes
+	MaxBytes int
+	// Timeout limits each upload, zero means DefaultTimeout
+	Timeout time.Duration
+}
+
+// New creates an Archiver that uploads chunks via up with names starting with prefix,
+// like "logs/myapp/". Chunks are uploaded when they get older than maxAge if positive,
+// otherwise only when MaxBytes is reached or on Flush & Close.
+func New(up Uploader, prefix string, maxAge time.Duration) *Archiver {
+	if up == nil {
+		panic("yellarchive: nil Uploader")
+	}
+	return &Archiver{up: up, prefix: prefix, maxAge: maxAge}
+}
+
+// Write compresses p into current chunk, uploading the chunk if it reaches MaxBytes.
+// p is kept even if an error of a background upload is returned.
+func (a *Archiver) Write(p []byte) (int, error) {
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return 0, ErrClosed
+	}
+
+	if a.start.IsZero() {
+		a.start = time.Now()
+		a.buf.Reset()
+		if a.zw == nil {
+			a.zw = gzip.NewWriter(&a.buf)
+		} else {
+			a.zw.Reset(&a.buf)
+		}
+		if a.maxAge > 0 {
+			a.timer = time.AfterFunc(a.maxAge, a.tick)
+		}
+	}
+	if _, err := a.zw.Write(p); err != nil {
+		a.mu.Unlock()
+		return 0, err
+	}
+	a.size += len(p)
+
+	max := a.MaxBytes
+	if max <= 0 {
+		max = DefaultMaxBytes
+	}
+	var err error
+	full := a.size >= max
+	if full {
+		err = a.seal()
+	}
+	a.mu.Unlock()
+
+	if full && err == nil {
+		err = a.upload()
+	}
+	return len(p), a.report(err)
+}
+
+// tick uploads current chunk in the background, unless it is a newer one
+func (a *Archiver) tick() {
+	a.mu.Lock()
+	if a.start.IsZero() || time.Since(a.start) < a.maxAge {
+		a.mu.Unlock()
+		return
+	}
+	a.timer = nil
+	err := a.seal()
+	a.mu.Unlock()
+
+	if err == nil {
+		err = a.upload()
+	}
+	if err != nil {
+		a.mu.Lock()
+		a.err = err
+		a.mu.Unlock()
+	}
+}
+
+// seal closes current chunk and appends it to pending chunks. Must be called with a.mu
+// held.
+func (a *Archiver) seal() error {
+	if a.start.IsZero() {
+		return nil
+	}
+	if a.timer != nil {
+		a.timer.Stop()
+		a.timer = nil
+	}
+	if err := a.zw.Close(); err != nil {
+		return err
+	}
+	a.count++
+	name := a.prefix + a.start.UTC().Format("2006/01/02/150405-") +
+		strconv.FormatUint(a.count, 10) + ".log.gz"
+	a.pending = append(a.pending, chunk{name, append([]byte(nil), a.buf.Bytes()...)})
+	a.start, a.size = time.Time{}, 0
+	return nil
+}
+
+// upload uploads pending chunks in order. a.mu is not held during uploads, so Writes do
+// not wait for them.
+func (a *Archiver) upload() error {
+	a.upMu.Lock()
+	defer a.upMu.Unlock()
+
+	timeout := a.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	for {
+		a.mu.Lock()
+		if len(a.pending) == 0 {
+			a.pending = nil
+			a.mu.Unlock()
+			return nil
+		}
+		c := a.pending[0]
+		a.mu.Unlock()
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		err := a.up.Upload(ctx, c.name, c.body)
+		cancel()
+
+		a.mu.Lock()
+		if err != nil {
+			if n := len(a.pending) - maxPending; n > 0 {
+				a.pending = a.pending[n:] // drop oldest
+			}
+			a.mu.Unlock()
+			return err
+		}
+		a.pending[0] = chunk{}
+		a.pending = a.pending[1:]
+		a.mu.Unlock()
+	}
+}
+
+// report returns err, or else the error of the last background upload. The latter is
+// cleared in both cases.
+func (a *Archiver) report(err error) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err == nil {
+		err = a.err
+	}
+	a.err = nil
+	return err
+}
+
+// Flush uploads current & pending chunks, and returns their error or else the error of
+// the last background upload
+func (a *Archiver) Flush() error {
+	a.mu.Lock()
+	err := a.seal()
+	a.mu.Unlock()
+
+	if err == nil {
+		err = a.upload()
+	}
+	return a.report(err)
+}
+
+// Close uploads current & pending chunks. Later Writes return ErrClosed.
+func (a *Archiver) Close() error {
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return nil
+	}
+	a.closed = true
+	err := a.seal()
+	a.mu.Unlock()
+
+	if err == nil {
+		err = a.upload()
+	}
+	return a.report(err)
+}
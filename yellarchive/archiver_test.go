@@ -0,0 +1,132 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yellarchive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jfcg/yell/v2"
+)
+
+// store is an in-memory Uploader
+type store struct {
+	mu      sync.Mutex
+	names   []string
+	objects map[string]string // decompressed
+	fail    bool
+}
+
+func (s *store) Upload(_ context.Context, name string, body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fail {
+		return errors.New("unavailable")
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	data, err := ioutil.ReadAll(zr)
+	if err != nil {
+		return err
+	}
+	if s.objects == nil {
+		s.objects = map[string]string{}
+	}
+	s.names = append(s.names, name)
+	s.objects[name] = string(data)
+	return nil
+}
+
+func (s *store) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.names)
+}
+
+func (s *store) setFail(fail bool) {
+	s.mu.Lock()
+	s.fail = fail
+	s.mu.Unlock()
+}
+
+var nameRe = regexp.MustCompile(`^logs/\d{4}/\d\d/\d\d/\d{6}-\d+\.log\.gz$`)
+
+func TestArchiver(t *testing.T) {
+	st := &store{}
+	a := New(st, "logs/", 0)
+	a.MaxBytes = 200
+	lg := yell.New(": arc:", a, yell.Sinfo)
+
+	for i := 0; i < 10; i++ {
+		lg.Log(yell.Sinfo, "record", i, strings.Repeat("x", 20))
+	}
+	if n := st.count(); n < 2 || n > 5 {
+		t.Fatal("bad number of chunks", n)
+	}
+	if err := lg.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	all := ""
+	for _, name := range st.names {
+		if !nameRe.MatchString(name) {
+			t.Fatal("bad object name", name)
+		}
+		all += st.objects[name]
+	}
+	if strings.Count(all, "\n") != 10 || !strings.Contains(all, "arc:info:") {
+		t.Fatal("records lost", all)
+	}
+	if _, err := a.Write([]byte("x\n")); err != ErrClosed {
+		t.Fatal("write after close", err)
+	}
+}
+
+func TestArchiverRetry(t *testing.T) {
+	st := &store{}
+	a := New(st, "logs/", 10*time.Millisecond)
+	st.setFail(true)
+	a.Write([]byte("one\n"))
+	time.Sleep(50 * time.Millisecond)
+
+	if _, err := a.Write([]byte("two\n")); err == nil || err.Error() != "unavailable" {
+		t.Fatal("background error not reported", err)
+	}
+	a.Write([]byte("three\n"))
+	st.setFail(false)
+
+	if err := a.Close(); err != nil || st.count() != 2 {
+		t.Fatal("pending chunk not uploaded", err, st.count())
+	}
+	if st.objects[st.names[0]] != "one\n" || st.objects[st.names[1]] != "two\nthree\n" {
+		t.Fatal("bad chunks", st.objects)
+	}
+
+	// Flush uploads & reports
+	st = &store{}
+	a = New(st, "logs/", 10*time.Millisecond)
+	st.setFail(true)
+	a.Write([]byte("four\n"))
+	time.Sleep(50 * time.Millisecond)
+	st.setFail(false)
+	if err := a.Flush(); err == nil || st.count() != 1 {
+		t.Fatal("Flush must upload & report background error", err, st.count())
+	}
+	if err := a.Flush(); err != nil {
+		t.Fatal("error must be reported once", err)
+	}
+}
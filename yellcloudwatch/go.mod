@@ -0,0 +1,18 @@
+module github.com/jfcg/yell/yellcloudwatch
+
+go 1.24
+
+require (
+	github.com/aws/aws-sdk-go-v2 v1.43.7
+	github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs v1.82.3
+	github.com/jfcg/yell/v2 v2.0.0
+)
+
+require (
+	github.com/aws/aws-sdk-go-v2/aws/protocol/eventstream v1.7.18 // indirect
+	github.com/aws/aws-sdk-go-v2/internal/configsources v1.4.38 // indirect
+	github.com/aws/aws-sdk-go-v2/internal/endpoints/v2 v2.7.38 // indirect
+	github.com/aws/smithy-go v1.27.8 // indirect
+)
+
+replace github.com/jfcg/yell/v2 => ../
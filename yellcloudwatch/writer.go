@@ -0,0 +1,302 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+// Package yellcloudwatch provides an io.Writer that ships yell records to AWS CloudWatch
+// Logs in batched PutLogEvents calls, so Lambda or ECS services can log directly to
+// CloudWatch. It is a separate module, so yell itself does not depend on the AWS SDK.
+package yellcloudwatch
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+// API is the subset of *cloudwatchlogs.Client used by Writer
+type API interface {
+	PutLogEvents(ctx context.Context, in *cloudwatchlogs.PutLogEventsInput,
+		opts ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.PutLogEventsOutput, error)
+	CreateLogStream(ctx context.Context, in *cloudwatchlogs.CreateLogStreamInput,
+		opts ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.CreateLogStreamOutput, error)
+	CreateLogGroup(ctx context.Context, in *cloudwatchlogs.CreateLogGroupInput,
+		opts ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.CreateLogGroupOutput, error)
+}
+
+// PutLogEvents limits
+const (
+	MaxBatchBytes  = 1048576    // maximum batch size in bytes
+	MaxBatchEvents = 10000      // maximum number of events in a batch
+	MaxEventBytes  = 1<<18 - 26 // maximum event message size in bytes
+	eventOverhead  = 26         // bytes counted per event in addition to its message
+)
+
+// maxPending is the number of batches kept while sends fail, the oldest are dropped
+const maxPending = 16
+
+// ErrClosed is returned by Write after Close
+var ErrClosed = errors.New("yellcloudwatch: writer is closed")
+
+// Writer batches records into PutLogEvents calls to a log stream. A batch is sent when it
+// reaches the size limits, every flush interval and on Flush or Close. Missing log group &
+// stream are created on first use, and sequence tokens are tracked for older APIs that
+// still require them. Batches that fail to send are retried with the next one (the oldest
+// are dropped if too many fail). Errors of sends are returned by the next Flush or Close,
+// Write returns nil once its record is buffered. Each Write must be a complete record, as
+// Logger does. It is safe for concurrent use.
+type Writer struct {
+	mu       sync.Mutex
+	sendMu   sync.Mutex // serializes sends, which do not hold mu
+	api      API
+	group    string
+	stream   string
+	interval time.Duration
+	timer    *time.Timer
+	events   []types.InputLogEvent   // current batch
+	size     int                     // batch size in bytes
+	pending  [][]types.InputLogEvent // batches waiting to be sent
+	token    *string                 // next sequence token, guarded by sendMu
+	err      error                   // error of last send
+	closed   bool
+
+	// Timeout limits each API call, zero means no timeout
+	Timeout time.Duration
+}
+
+// New creates a Writer that sends records to stream of log group via api (for example a
+// *cloudwatchlogs.Client). Buffered records are sent every interval if positive, otherwise
+// only when the batch is full or on Flush & Close.
+func New(api API, group, stream string, interval time.Duration) *Writer {
+	if api == nil || group == "" || stream == "" {
+		panic("yellcloudwatch: invalid arguments")
+	}
+	return &Writer{api: api, group: group, stream: stream, interval: interval}
+}
+
+// Write buffers record p (without its trailing newline) as a log event, truncated to
+// MaxEventBytes if necessary. It sends the current batch first if p does not fit in it.
+func (w *Writer) Write(p []byte) (int, error) {
+	msg := p
+	if n := len(msg); n > 0 && msg[n-1] == '\n' {
+		msg = msg[:n-1]
+	}
+	if len(msg) > MaxEventBytes {
+		msg = msg[:MaxEventBytes]
+	}
+
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return 0, ErrClosed
+	}
+
+	size := len(msg) + eventOverhead
+	full := len(w.events) >= MaxBatchEvents || w.size+size > MaxBatchBytes
+	if full {
+		w.seal()
+	}
+
+	w.events = append(w.events, types.InputLogEvent{
+		Message:   aws.String(string(msg)),
+		Timestamp: aws.Int64(time.Now().UnixNano() / 1e6),
+	})
+	w.size += size
+
+	if w.timer == nil && w.interval > 0 {
+		w.timer = time.AfterFunc(w.interval, w.tick)
+	}
+	w.mu.Unlock()
+
+	if full {
+		w.keep(w.send())
+	}
+	return len(p), nil
+}
+
+// tick sends buffered events in the background
+func (w *Writer) tick() {
+	w.mu.Lock()
+	w.timer = nil
+	w.seal()
+	w.mu.Unlock()
+
+	w.keep(w.send())
+}
+
+// seal appends current batch to pending batches. Must be called with w.mu held.
+func (w *Writer) seal() {
+	if len(w.events) > 0 {
+		w.pending = append(w.pending, w.events)
+		w.events, w.size = nil, 0
+	}
+}
+
+// keep stores err of a send for the next Flush or Close
+func (w *Writer) keep(err error) {
+	if err != nil {
+		w.mu.Lock()
+		w.err = err
+		w.mu.Unlock()
+	}
+}
+
+// report returns err, or else the error of the last send. The latter is cleared in both
+// cases.
+func (w *Writer) report(err error) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err == nil {
+		err = w.err
+	}
+	w.err = nil
+	return err
+}
+
+// context returns context for an API call and its cancel function
+func (w *Writer) context() (context.Context, context.CancelFunc) {
+	if w.Timeout > 0 {
+		return context.WithTimeout(context.Background(), w.Timeout)
+	}
+	return context.WithCancel(context.Background())
+}
+
+// send puts pending batches to the log stream in order. w.mu is not held during API
+// calls, so Writes do not wait for them.
+func (w *Writer) send() error {
+	w.sendMu.Lock()
+	defer w.sendMu.Unlock()
+
+	for {
+		w.mu.Lock()
+		if len(w.pending) == 0 {
+			w.pending = nil
+			w.mu.Unlock()
+			return nil
+		}
+		events := w.pending[0]
+		w.mu.Unlock()
+
+		err := w.put(events)
+
+		w.mu.Lock()
+		if err != nil {
+			if n := len(w.pending) - maxPending; n > 0 {
+				w.pending = w.pending[n:] // drop oldest
+			}
+			w.mu.Unlock()
+			return err
+		}
+		w.pending[0] = nil
+		w.pending = w.pending[1:]
+		w.mu.Unlock()
+	}
+}
+
+// put sends events to the log stream. Must be called with w.sendMu held.
+func (w *Writer) put(events []types.InputLogEvent) error {
+	created := false
+
+	for try := 0; ; try++ {
+		ctx, cancel := w.context()
+		out, err := w.api.PutLogEvents(ctx, &cloudwatchlogs.PutLogEventsInput{
+			LogGroupName:  aws.String(w.group),
+			LogStreamName: aws.String(w.stream),
+			LogEvents:     events,
+			SequenceToken: w.token,
+		})
+		cancel()
+
+		if err == nil {
+			w.token = out.NextSequenceToken
+			return nil
+		}
+
+		var invalid *types.InvalidSequenceTokenException
+		var accepted *types.DataAlreadyAcceptedException
+		var missing *types.ResourceNotFoundException
+
+		switch {
+		case errors.As(err, &accepted):
+			w.token = accepted.ExpectedSequenceToken
+			return nil
+		case errors.As(err, &invalid) && try < 2:
+			w.token = invalid.ExpectedSequenceToken
+		case errors.As(err, &missing) && !created:
+			if err = w.create(); err != nil {
+				return err
+			}
+			created = true
+		default:
+			return err
+		}
+	}
+}
+
+// create creates the log stream, and the log group if it does not exist. Must be called
+// with w.sendMu held.
+func (w *Writer) create() error {
+	ctx, cancel := w.context()
+	defer cancel()
+
+	stream := &cloudwatchlogs.CreateLogStreamInput{
+		LogGroupName:  aws.String(w.group),
+		LogStreamName: aws.String(w.stream),
+	}
+	_, err := w.api.CreateLogStream(ctx, stream)
+
+	var missing *types.ResourceNotFoundException
+	if errors.As(err, &missing) {
+		_, err = w.api.CreateLogGroup(ctx, &cloudwatchlogs.CreateLogGroupInput{
+			LogGroupName: aws.String(w.group),
+		})
+		if err == nil || isExists(err) {
+			_, err = w.api.CreateLogStream(ctx, stream)
+		}
+	}
+	if isExists(err) {
+		err = nil
+	}
+	w.token = nil
+	return err
+}
+
+// isExists reports whether err means the resource already exists
+func isExists(err error) bool {
+	var exists *types.ResourceAlreadyExistsException
+	return errors.As(err, &exists)
+}
+
+// Flush sends buffered events, and returns their error or else the error of the last
+// earlier send
+func (w *Writer) Flush() error {
+	w.mu.Lock()
+	w.seal()
+	w.mu.Unlock()
+	return w.report(w.send())
+}
+
+// Close sends buffered events and stops the flush timer, and returns errors like Flush.
+// Later Writes return ErrClosed.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+	w.seal()
+	w.mu.Unlock()
+	return w.report(w.send())
+}
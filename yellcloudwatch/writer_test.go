@@ -0,0 +1,191 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yellcloudwatch
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/jfcg/yell/v2"
+)
+
+// fakeAPI is an in-memory log service that requires sequence tokens
+type fakeAPI struct {
+	mu      sync.Mutex
+	groups  map[string]bool
+	streams map[string]bool
+	batches [][]string
+	token   int
+	fail    error
+}
+
+func newFake() *fakeAPI {
+	return &fakeAPI{groups: map[string]bool{}, streams: map[string]bool{}}
+}
+
+func (f *fakeAPI) PutLogEvents(_ context.Context, in *cloudwatchlogs.PutLogEventsInput,
+	_ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.PutLogEventsOutput, error) {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.fail != nil {
+		return nil, f.fail
+	}
+	if !f.streams[*in.LogGroupName+"/"+*in.LogStreamName] {
+		return nil, &types.ResourceNotFoundException{Message: aws.String("no stream")}
+	}
+	expected := aws.String(strconv.Itoa(f.token))
+	if f.token == 0 {
+		expected = nil
+	}
+	if aws.ToString(in.SequenceToken) != aws.ToString(expected) {
+		return nil, &types.InvalidSequenceTokenException{ExpectedSequenceToken: expected}
+	}
+
+	var size int
+	batch := make([]string, len(in.LogEvents))
+	for i, e := range in.LogEvents {
+		batch[i] = *e.Message
+		size += len(batch[i]) + eventOverhead
+	}
+	if len(batch) > MaxBatchEvents || size > MaxBatchBytes {
+		return nil, errors.New("batch too large")
+	}
+	f.batches = append(f.batches, batch)
+	f.token++
+	return &cloudwatchlogs.PutLogEventsOutput{
+		NextSequenceToken: aws.String(strconv.Itoa(f.token))}, nil
+}
+
+func (f *fakeAPI) CreateLogStream(_ context.Context, in *cloudwatchlogs.CreateLogStreamInput,
+	_ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.CreateLogStreamOutput, error) {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !f.groups[*in.LogGroupName] {
+		return nil, &types.ResourceNotFoundException{Message: aws.String("no group")}
+	}
+	f.streams[*in.LogGroupName+"/"+*in.LogStreamName] = true
+	return &cloudwatchlogs.CreateLogStreamOutput{}, nil
+}
+
+func (f *fakeAPI) CreateLogGroup(_ context.Context, in *cloudwatchlogs.CreateLogGroupInput,
+	_ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.CreateLogGroupOutput, error) {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.groups[*in.LogGroupName] = true
+	return &cloudwatchlogs.CreateLogGroupOutput{}, nil
+}
+
+func (f *fakeAPI) sent() (n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, b := range f.batches {
+		n += len(b)
+	}
+	return
+}
+
+func TestWriter(t *testing.T) {
+	api := newFake()
+	w := New(api, "grp", "str", 0)
+	lg := yell.New(": cw:", w, yell.Sinfo)
+	lg.Log(yell.Sinfo, "hello", 1)
+	lg.Log(yell.Swarn, "world")
+
+	if api.sent() != 0 {
+		t.Fatal("sent before flush")
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if len(api.batches) != 1 || len(api.batches[0]) != 2 ||
+		!strings.Contains(api.batches[0][0], "cw:info:") ||
+		!strings.HasSuffix(api.batches[0][0], ": hello 1") ||
+		!api.groups["grp"] || !api.streams["grp/str"] {
+		t.Fatal("bad batch", api.batches)
+	}
+
+	// stale sequence token is corrected
+	w.token = aws.String("stale")
+	lg.Log(yell.Serror, "again")
+	if err := w.Close(); err != nil || len(api.batches) != 2 || api.token != 2 {
+		t.Fatal("close failed", err, api.batches)
+	}
+	if _, err := w.Write([]byte("x\n")); err != ErrClosed {
+		t.Fatal("write after close", err)
+	}
+}
+
+func TestWriterLimits(t *testing.T) {
+	api := newFake()
+	api.groups["grp"] = true
+	w := New(api, "grp", "str", 0)
+
+	big := strings.Repeat("a", MaxEventBytes+100) + "\n"
+	for i := 0; i < 5; i++ {
+		if n, err := w.Write([]byte(big)); err != nil || n != len(big) {
+			t.Fatal("write failed", n, err)
+		}
+	}
+	small := []byte("b\n")
+	for i := 0; i < MaxBatchEvents+1; i++ {
+		if _, err := w.Write(small); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if api.sent() != MaxBatchEvents+6 || len(api.batches) < 3 ||
+		len(api.batches[0][0]) != MaxEventBytes {
+		t.Fatal("bad batching", len(api.batches), api.sent())
+	}
+}
+
+func TestWriterInterval(t *testing.T) {
+	api := newFake()
+	w := New(api, "grp", "str", 10*time.Millisecond)
+	w.Write([]byte("tick\n"))
+
+	for i := 0; api.sent() == 0; i++ {
+		if i > 200 {
+			t.Fatal("interval flush did not happen")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	api.mu.Lock()
+	api.fail = errors.New("throttled")
+	api.mu.Unlock()
+	w.Write([]byte("tock\n"))
+	time.Sleep(50 * time.Millisecond)
+
+	api.mu.Lock()
+	api.fail = nil
+	api.mu.Unlock()
+	if _, err := w.Write([]byte("x\n")); err != nil {
+		t.Fatal("buffered record must not fail", err)
+	}
+	if err := w.Flush(); err == nil || err.Error() != "throttled" {
+		t.Fatal("background error not reported", err)
+	}
+	if err := w.Close(); err != nil || api.sent() != 3 {
+		t.Fatal("records must be kept after errors", err, api.batches)
+	}
+}
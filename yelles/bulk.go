@@ -0,0 +1,406 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+// Package yelles ships yell records to Elasticsearch (or OpenSearch) with the bulk API.
+// Encoder turns records into bulk actions with documents, and Writer buffers them for
+// bulk requests:
+//
+//	w := yelles.NewWriter("http://localhost:9200", 5*time.Second)
+//	lg := yell.New(": mypkg:", w, yell.Sinfo)
+//	lg.SetEncoder(yelles.Encoder{Index: yelles.Daily("logs-mypkg")})
+//	defer lg.Close()
+package yelles
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jfcg/yell/v2"
+)
+
+// DefaultIndex is the index of records when Encoder.Index is nil
+const DefaultIndex = "yell"
+
+// Daily returns an index naming function for Encoder that appends UTC date of record to
+// prefix, like prefix-2021.03.28, so each day goes to a separate index.
+func Daily(prefix string) func(*yell.Record) string {
+	return func(r *yell.Record) string {
+		return prefix + r.Time.UTC().Format("-2006.01.02")
+	}
+}
+
+// Encoder is a yell.Encoder that writes each record as a bulk "create" action followed by
+// its document:
+//
+//	{"create":{"_index":"logs-2021.03.28"}}
+//	{"@timestamp":"2021-03-28T18:48:53.591948Z","logger":"mypkg","level":"warn",
+//	 "severity":2,"caller":{"file":"myApp.go","line":15},"message":"some warning",
+//	 "key":"value"}
+//
+// Severity name & number (see yell.Severity) and caller become separate fields, so they
+// can be filtered & aggregated. Errors of message list go to error.message, error.type
+// (arrays with an entry per error if there are multiple errors) & error.causes, error
+// details to error.stack_trace. Fields are top-level keys encoded with encoding/json.
+type Encoder struct {
+	// Index returns index name of a record, nil means DefaultIndex, see Daily
+	Index func(*yell.Record) string
+}
+
+// Encode appends bulk action & document of r to buf
+func (e Encoder) Encode(buf []byte, r *yell.Record) ([]byte, error) {
+	index := DefaultIndex
+	if e.Index != nil {
+		index = e.Index(r)
+	}
+	start := len(buf)
+	buf = append(buf, `{"create":{"_index":`...)
+	buf = appendString(buf, index)
+	buf = append(buf, `}}`...)
+	buf = append(buf, "\n{\"@timestamp\":\""...)
+	buf = r.Time.UTC().AppendFormat(buf, time.RFC3339Nano)
+	buf = append(buf, `","logger":`...)
+	buf = appendString(buf, r.Name)
+	buf = append(buf, `,"level":`...)
+	buf = appendString(buf, r.Level.String())
+	buf = append(buf, `,"severity":`...)
+	buf = append(buf, byte('0'+r.Level))
+
+	if r.File != "" {
+		buf = append(buf, `,"caller":{"file":`...)
+		buf = appendString(buf, r.File)
+		buf = append(buf, fmt.Sprintf(`,"line":%d}`, r.Line)...)
+	}
+	if r.Seq != 0 {
+		buf = append(buf, fmt.Sprintf(`,"seq":%d`, r.Seq)...)
+	}
+	if r.ID != "" {
+		buf = append(buf, `,"id":`...)
+		buf = appendString(buf, r.ID)
+	}
+
+	buf = append(buf, `,"message":`...)
+	buf = appendString(buf, r.Msg)
+
+	if len(r.Errs) > 0 || r.Detail != "" {
+		buf = append(buf, `,"error":{`...)
+		sep := ""
+		if len(r.Errs) > 0 {
+			var msgs, types, causes []string
+			for _, err := range r.Errs {
+				msgs = append(msgs, err.Error())
+				types = append(types, fmt.Sprintf("%T", err))
+				for c := errors.Unwrap(err); c != nil; c = errors.Unwrap(c) {
+					causes = append(causes, c.Error())
+				}
+			}
+			buf = append(buf, `"message":`...)
+			buf = appendList(buf, msgs)
+			buf = append(buf, `,"type":`...)
+			buf = appendList(buf, types)
+			if len(causes) > 0 {
+				b, _ := json.Marshal(causes)
+				buf = append(append(buf, `,"causes":`...), b...)
+			}
+			sep = ","
+		}
+		if r.Detail != "" {
+			buf = append(buf, sep+`"stack_trace":`...)
+			buf = appendString(buf, r.Detail)
+		}
+		buf = append(buf, '}')
+	}
+
+	for _, f := range r.Fields {
+		v := f.Interface()
+		if err, ok := v.(error); ok {
+			v = err.Error()
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return buf[:start], err
+		}
+		buf = append(buf, ',')
+		buf = appendString(buf, f.Key)
+		buf = append(append(buf, ':'), b...)
+	}
+	return append(buf, "}\n"...), nil
+}
+
+// appendList appends single entry of list as a string, or list as an array
+func appendList(buf []byte, list []string) []byte {
+	if len(list) == 1 {
+		return appendString(buf, list[0])
+	}
+	b, _ := json.Marshal(list)
+	return append(buf, b...)
+}
+
+// appendString appends s to buf as a quoted JSON string
+func appendString(buf []byte, s string) []byte {
+	b, _ := json.Marshal(s)
+	return append(buf, b...)
+}
+
+// default limits of Writer
+const (
+	DefaultMaxBytes = 5 << 20 // flush threshold of buffered actions
+	DefaultTimeout  = 30 * time.Second
+	maxPending      = 16 // failed requests kept for retry
+)
+
+// ErrClosed is returned by Write after Close
+var ErrClosed = errors.New("yelles: writer is closed")
+
+// BulkError describes a failed bulk request, or the items it failed to index
+type BulkError struct {
+	Status int    // HTTP status code
+	Failed int    // number of failed items, zero if the whole request failed
+	Reason string // response body, or type & reason of the first failed item
+}
+
+func (e *BulkError) Error() string {
+	if e.Failed > 0 {
+		return fmt.Sprintf("yelles: %d items failed to index: %s", e.Failed, e.Reason)
+	}
+	return fmt.Sprintf("yelles: bulk request failed with status %d: %s", e.Status, e.Reason)
+}
+
+// Writer buffers bulk actions written by Encoder and sends them to the _bulk endpoint of
+// an Elasticsearch cluster when MaxBytes is reached, every flush interval and on Flush or
+// Close. Requests that fail with 429, 5xx status or a network error are retried with the
+// next one (the oldest are dropped if too many fail), other failed requests are dropped.
+// Errors of sends are returned by the next Flush or Close, Write returns nil once its
+// action is buffered. Each Write must be a complete action, as Logger does. It is safe
+// for concurrent use.
+type Writer struct {
+	mu       sync.Mutex
+	sendMu   sync.Mutex // serializes sends, which do not hold mu
+	url      string
+	interval time.Duration
+	timer    *time.Timer
+	buf      []byte
+	pending  [][]byte // request bodies waiting to be sent
+	err      error    // error of last send
+	closed   bool
+
+	// Client sends bulk requests, nil means a client with DefaultTimeout
+	Client *http.Client
+	// Header is added to bulk requests, for example for Authorization
+	Header http.Header
+	// MaxBytes is flush threshold of buffered actions, zero means DefaultMaxBytes
+	MaxBytes int
+}
+
+// NewWriter creates a Writer for cluster at url like http://localhost:9200. Buffered
+// actions are sent every interval if positive, otherwise only when MaxBytes is reached
+// or on Flush & Close.
+func NewWriter(url string, interval time.Duration) *Writer {
+	if url == "" {
+		panic("yelles: empty url")
+	}
+	return &Writer{url: strings.TrimSuffix(url, "/") + "/_bulk", interval: interval}
+}
+
+// Write buffers action p, sending buffered actions first if p does not fit in MaxBytes
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return 0, ErrClosed
+	}
+
+	max := w.MaxBytes
+	if max <= 0 {
+		max = DefaultMaxBytes
+	}
+	full := len(w.buf) > 0 && len(w.buf)+len(p) > max
+	if full {
+		w.seal()
+	}
+	w.buf = append(w.buf, p...)
+
+	if len(w.buf) >= max {
+		w.seal()
+		full = true
+	} else if w.timer == nil && w.interval > 0 {
+		w.timer = time.AfterFunc(w.interval, w.tick)
+	}
+	w.mu.Unlock()
+
+	if full {
+		w.keep(w.send())
+	}
+	return len(p), nil
+}
+
+// tick sends buffered actions in the background
+func (w *Writer) tick() {
+	w.mu.Lock()
+	w.timer = nil
+	w.seal()
+	w.mu.Unlock()
+
+	w.keep(w.send())
+}
+
+// seal appends buffered actions to pending requests. Must be called with w.mu held.
+func (w *Writer) seal() {
+	if len(w.buf) > 0 {
+		w.pending = append(w.pending, w.buf)
+		w.buf = nil
+	}
+}
+
+// keep stores err of a send for the next Flush or Close
+func (w *Writer) keep(err error) {
+	if err != nil {
+		w.mu.Lock()
+		w.err = err
+		w.mu.Unlock()
+	}
+}
+
+// report returns err, or else the error of the last send. The latter is cleared in both
+// cases.
+func (w *Writer) report(err error) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err == nil {
+		err = w.err
+	}
+	w.err = nil
+	return err
+}
+
+// send posts pending requests in order. w.mu is not held during requests, so Writes do
+// not wait for them.
+func (w *Writer) send() error {
+	w.sendMu.Lock()
+	defer w.sendMu.Unlock()
+
+	for {
+		w.mu.Lock()
+		if len(w.pending) == 0 {
+			w.pending = nil
+			w.mu.Unlock()
+			return nil
+		}
+		body := w.pending[0]
+		w.mu.Unlock()
+
+		received, err := w.post(body)
+
+		w.mu.Lock()
+		if received {
+			w.pending[0] = nil
+			w.pending = w.pending[1:]
+		} else if n := len(w.pending) - maxPending; n > 0 {
+			w.pending = w.pending[n:] // drop oldest
+		}
+		w.mu.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// bulk response of Elasticsearch
+type response struct {
+	Errors bool
+	Items  []map[string]struct {
+		Status int
+		Error  struct {
+			Type, Reason string
+		}
+	}
+}
+
+// post sends body to bulk endpoint, and reports whether the cluster received it (even if
+// some items failed) or rejected it permanently, so it must not be retried. Must be called
+// with w.sendMu held.
+func (w *Writer) post(body []byte) (received bool, err error) {
+	req, err := http.NewRequest(http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	for k, v := range w.Header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+
+	client := w.Client
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	body, err = ioutil.ReadAll(io.LimitReader(resp.Body, 1<<20))
+
+	if resp.StatusCode >= 300 {
+		retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return !retry, &BulkError{Status: resp.StatusCode,
+			Reason: strings.TrimSpace(string(body))}
+	}
+	if err != nil {
+		return true, err
+	}
+
+	var res response
+	if err = json.Unmarshal(body, &res); err != nil || !res.Errors {
+		return true, err
+	}
+	bulkErr := &BulkError{Status: resp.StatusCode}
+	for _, item := range res.Items {
+		for _, r := range item {
+			if r.Status >= 300 {
+				if bulkErr.Failed == 0 {
+					bulkErr.Reason = r.Error.Type + ": " + r.Error.Reason
+				}
+				bulkErr.Failed++
+			}
+		}
+	}
+	return true, bulkErr
+}
+
+// Flush sends buffered actions, and returns their error or else the error of the last
+// earlier send
+func (w *Writer) Flush() error {
+	w.mu.Lock()
+	w.seal()
+	w.mu.Unlock()
+	return w.report(w.send())
+}
+
+// Close sends buffered actions and stops the flush timer, and returns errors like Flush.
+// Later Writes return ErrClosed.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+	w.seal()
+	w.mu.Unlock()
+	return w.report(w.send())
+}
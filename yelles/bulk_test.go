@@ -0,0 +1,188 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yelles
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jfcg/yell/v2"
+)
+
+func TestEncoder(t *testing.T) {
+	tm := time.Date(2021, 3, 28, 21, 48, 53, 0, time.FixedZone("", 3*3600))
+	r := yell.Record{Time: tm, Level: yell.Swarn, Name: "mypkg", File: "myApp.go",
+		Line: 15, Msg: "some warning", Errs: []error{fmt.Errorf("open: %w", errors.New("eof"))},
+		Fields: []yell.Field{yell.Any("key", "value"), yell.Any("n", 3)}, Detail: "stack"}
+
+	buf, err := Encoder{Index: Daily("logs")}.Encode(nil, &r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(string(buf), "\n")
+	if len(lines) != 3 || lines[0] != `{"create":{"_index":"logs-2021.03.28"}}` {
+		t.Fatal("bad action", string(buf))
+	}
+
+	var doc map[string]interface{}
+	if err = json.Unmarshal([]byte(lines[1]), &doc); err != nil {
+		t.Fatal(err)
+	}
+	caller, _ := doc["caller"].(map[string]interface{})
+	errObj, _ := doc["error"].(map[string]interface{})
+	if doc["@timestamp"] != "2021-03-28T18:48:53Z" || doc["level"] != "warn" ||
+		doc["severity"] != 2.0 || doc["logger"] != "mypkg" || doc["message"] != "some warning" ||
+		caller["file"] != "myApp.go" || caller["line"] != 15.0 || doc["key"] != "value" ||
+		doc["n"] != 3.0 || errObj["message"] != "open: eof" || errObj["stack_trace"] != "stack" {
+		t.Fatal("bad document", lines[1])
+	}
+
+	r.Errs = append(r.Errs, errors.New("eof"))
+	buf, _ = Encoder{}.Encode(nil, &r)
+	want := `"error":{"message":["open: eof","eof"],` +
+		`"type":["*fmt.wrapError","*errors.errorString"],"causes":["eof"]`
+	if !strings.Contains(string(buf), want) {
+		t.Fatal("bad document", string(buf))
+	}
+
+	r.Fields = []yell.Field{yell.Any("bad", func() {})}
+	if buf, err = (Encoder{}).Encode([]byte("x"), &r); err == nil || string(buf) != "x" {
+		t.Fatal("expected encoding error")
+	}
+}
+
+// bulkServer records bulk requests, failing items with index "bad"
+type bulkServer struct {
+	mu       sync.Mutex
+	requests []string
+	status   int
+}
+
+func (s *bulkServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.URL.Path != "/_bulk" || r.Header.Get("Content-Type") != "application/x-ndjson" ||
+		r.Header.Get("Authorization") != "ApiKey k" {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	if s.status != 0 {
+		http.Error(w, "unavailable", s.status)
+		return
+	}
+	s.requests = append(s.requests, string(body))
+
+	if strings.Contains(string(body), `"_index":"bad"`) {
+		fmt.Fprint(w, `{"errors":true,"items":[{"create":{"status":201}},`+
+			`{"create":{"status":400,"error":{"type":"mapper_parsing_exception","reason":"x"}}}]}`)
+		return
+	}
+	fmt.Fprint(w, `{"errors":false,"items":[]}`)
+}
+
+func (s *bulkServer) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.requests)
+}
+
+func TestWriter(t *testing.T) {
+	srv := &bulkServer{}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	w := NewWriter(ts.URL+"/", 0)
+	w.Header = http.Header{"Authorization": {"ApiKey k"}}
+	lg := yell.New(": es:", w, yell.Sinfo)
+	lg.SetEncoder(Encoder{})
+	lg.Log(yell.Sinfo, "hello")
+	lg.Log(yell.Serror, "world")
+
+	if srv.count() != 0 {
+		t.Fatal("sent before flush")
+	}
+	if err := w.Flush(); err != nil || srv.count() != 1 ||
+		strings.Count(srv.requests[0], `{"create":{"_index":"yell"}}`) != 2 {
+		t.Fatal("flush failed", err, srv.requests)
+	}
+
+	// item failures
+	lg.SetEncoder(Encoder{Index: func(*yell.Record) string { return "bad" }})
+	lg.Log(yell.Sinfo, "x")
+	err := w.Flush()
+	if e, ok := err.(*BulkError); !ok || e.Failed != 1 ||
+		e.Reason != "mapper_parsing_exception: x" {
+		t.Fatal("expected item failure", err)
+	}
+
+	// request failure keeps actions
+	srv.status = http.StatusServiceUnavailable
+	lg.SetEncoder(Encoder{})
+	lg.Log(yell.Sinfo, "y")
+	if e, ok := w.Flush().(*BulkError); !ok || e.Status != 503 || len(w.pending) != 1 {
+		t.Fatal("expected request failure", e)
+	}
+	// permanent failure drops actions
+	srv.status = http.StatusBadRequest
+	lg.Log(yell.Sinfo, "rejected")
+	if e, ok := w.Flush().(*BulkError); !ok || e.Status != 400 || len(w.pending) != 1 {
+		t.Fatal("expected permanent failure", e, len(w.pending))
+	}
+
+	// the error of a background send is reported by Close, not Write
+	srv.status = 0
+	bg := errors.New("background")
+	w.err = bg
+	if _, err = w.Write([]byte("z\n")); err != nil {
+		t.Fatal("buffered action must not fail", err)
+	}
+	if err = w.Close(); err != bg || srv.count() != 4 || srv.requests[3] != "z\n" {
+		t.Fatal("close failed", err)
+	}
+	if _, err = w.Write([]byte("x\n")); err != ErrClosed {
+		t.Fatal("write after close", err)
+	}
+}
+
+func TestWriterLimits(t *testing.T) {
+	srv := &bulkServer{}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	w := NewWriter(ts.URL, 10*time.Millisecond)
+	w.Header = http.Header{"Authorization": {"ApiKey k"}}
+	w.MaxBytes = 100
+	action := []byte(`{"create":{}}` + "\n" + strings.Repeat("a", 30) + "\n")
+
+	for i := 0; i < 4; i++ {
+		w.Write(action)
+	}
+	w.mu.Lock()
+	n := len(w.buf)
+	w.mu.Unlock()
+	if srv.count() != 1 || n != 2*len(action) {
+		t.Fatal("MaxBytes not respected", srv.count(), n)
+	}
+
+	for i := 0; srv.count() < 2; i++ {
+		if i > 200 {
+			t.Fatal("interval flush did not happen")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	w.Close()
+}
@@ -0,0 +1,97 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+// Package yellgrpc provides gRPC server interceptors that write RPC access logs through
+// a yell Logger. It is a separate module, so yell itself does not depend on gRPC.
+package yellgrpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/jfcg/yell/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// RequestIDKeys are incoming metadata keys searched (in order) for request IDs
+var RequestIDKeys = []string{"x-request-id", "x-correlation-id"}
+
+// UnaryServerInterceptor logs each unary RPC to lg with method, peer, duration, status
+// code and request ID (if any) fields, see Severity for record severities.
+func UnaryServerInterceptor(lg *yell.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logRPC(lg, ctx, "unary", info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor logs each streaming RPC to lg like UnaryServerInterceptor
+func StreamServerInterceptor(lg *yell.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+
+		start := time.Now()
+		err := handler(srv, ss)
+		logRPC(lg, ss.Context(), "stream", info.FullMethod, start, err)
+		return err
+	}
+}
+
+// Severity returns record severity for a status code: info for OK, error for server
+// side failures (Unknown, DeadlineExceeded, Unimplemented, Internal, Unavailable,
+// DataLoss), warn for others.
+func Severity(code codes.Code) yell.Severity {
+	switch code {
+	case codes.OK:
+		return yell.Sinfo
+	case codes.Unknown, codes.DeadlineExceeded, codes.Unimplemented, codes.Internal,
+		codes.Unavailable, codes.DataLoss:
+		return yell.Serror
+	}
+	return yell.Swarn
+}
+
+// logRPC writes an RPC access record
+func logRPC(lg *yell.Logger, ctx context.Context, kind, method string, start time.Time,
+	err error) {
+
+	code := status.Code(err)
+	msg := []interface{}{"grpc", kind, yell.Any("method", method),
+		yell.Any("duration", time.Since(start)), yell.Any("code", code.String())}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		msg = append(msg, yell.Any("peer", p.Addr.String()))
+	}
+	if id := requestID(ctx); id != "" {
+		msg = append(msg, yell.Any("request_id", id))
+	}
+	if err != nil {
+		msg = append(msg, err)
+	}
+	lg.Log(Severity(code), msg...)
+}
+
+// requestID returns first request ID found in incoming metadata
+func requestID(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	for _, k := range RequestIDKeys {
+		if v := md.Get(k); len(v) > 0 {
+			return v[0]
+		}
+	}
+	return ""
+}
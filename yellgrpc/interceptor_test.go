@@ -0,0 +1,74 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yellgrpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/jfcg/yell/v2"
+	"github.com/jfcg/yell/v2/yelltest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// stream is a minimal grpc.ServerStream
+type stream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s stream) Context() context.Context {
+	return s.ctx
+}
+
+func TestInterceptors(t *testing.T) {
+	var rec yelltest.Recorder
+	lg := yell.New(": rpc:", &rec, yell.Sinfo)
+
+	ctx := metadata.NewIncomingContext(context.Background(),
+		metadata.Pairs("x-request-id", "req-7"))
+	ctx = peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 99}})
+
+	unary := UnaryServerInterceptor(&lg)
+	resp, err := unary(ctx, "req", &grpc.UnaryServerInfo{FullMethod: "/svc/Get"},
+		func(context.Context, interface{}) (interface{}, error) {
+			return "resp", nil
+		})
+	if resp != "resp" || err != nil {
+		t.Fatal("unexpected result:", resp, err)
+	}
+
+	streamer := StreamServerInterceptor(&lg)
+	err = streamer(nil, stream{ctx: context.Background()},
+		&grpc.StreamServerInfo{FullMethod: "/svc/Watch"},
+		func(interface{}, grpc.ServerStream) error {
+			return status.Error(codes.Internal, "boom")
+		})
+	if status.Code(err) != codes.Internal {
+		t.Fatal("unexpected error:", err)
+	}
+
+	recs := rec.Records()
+	if len(recs) != 2 || recs[0].Level != yell.Sinfo || recs[1].Level != yell.Serror {
+		t.Fatal("unexpected records:", recs)
+	}
+	want := "grpc unary method=/svc/Get duration="
+	if m := recs[0].Message; m[:len(want)] != want ||
+		!rec.ContainsMessage("code=OK peer=10.0.0.1:99 request_id=req-7") ||
+		!rec.ContainsMessage("code=Internal") {
+		t.Fatal("unexpected records:", recs)
+	}
+
+	if Severity(codes.NotFound) != yell.Swarn {
+		t.Fatal("must be warn")
+	}
+}
@@ -0,0 +1,96 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yellhttp
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jfcg/yell/v2"
+)
+
+// ErrNotAccess is returned by CLFEncoder for records not written by Middleware
+var ErrNotAccess = errors.New("yellhttp: not an access record")
+
+// CLFEncoder is a yell.Encoder that writes Middleware records in Common Log Format, or
+// Combined Log Format if Combined is true, so access log analyzers (goaccess, awstats)
+// can consume them:
+//
+//	127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 200 2326
+//	127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 200 2326 "http://www.example.com/start.html" "Mozilla/4.08"
+//
+// Usage:
+//
+//	access := yell.New(": access:", file, yell.Sinfo)
+//	access.SetEncoder(yellhttp.CLFEncoder{Combined: true})
+//	handler := yellhttp.Handler(&access, mux)
+type CLFEncoder struct {
+	Combined bool
+}
+
+// CLF time layout
+const clfTime = "02/Jan/2006:15:04:05 -0700"
+
+// Encode appends r in Common/Combined Log Format to buf
+func (e CLFEncoder) Encode(buf []byte, r *yell.Record) ([]byte, error) {
+	f := make(map[string]interface{}, len(r.Fields))
+	for _, fd := range r.Fields {
+		f[fd.Key] = fd.Interface()
+	}
+	if f["method"] == nil || f["status"] == nil {
+		return buf, ErrNotAccess
+	}
+
+	buf = append(buf, field(f, "remote")...)
+	buf = append(buf, " - "...)
+	buf = append(buf, field(f, "user")...)
+	buf = append(buf, " ["...)
+	buf = r.Time.AppendFormat(buf, clfTime)
+	buf = append(buf, "] "...)
+
+	req := field(f, "method") + " " + field(f, "path")
+	if q, ok := f["query"]; ok {
+		req += "?" + fmt.Sprint(q)
+	}
+	buf = strconv.AppendQuote(buf, req+" "+field(f, "proto"))
+
+	buf = append(buf, ' ')
+	buf = append(buf, field(f, "status")...)
+	buf = append(buf, ' ')
+	if s := field(f, "size"); s != "0" {
+		buf = append(buf, s...)
+	} else {
+		buf = append(buf, '-')
+	}
+
+	if e.Combined {
+		buf = append(buf, ' ')
+		buf = strconv.AppendQuote(buf, quoted(f, "referer"))
+		buf = append(buf, ' ')
+		buf = strconv.AppendQuote(buf, quoted(f, "user_agent"))
+	}
+	return append(buf, '\n'), nil
+}
+
+// quoted returns value of field key as text, or "-" if it is missing
+func quoted(f map[string]interface{}, key string) string {
+	v, ok := f[key]
+	if !ok {
+		return "-"
+	}
+	if s := fmt.Sprint(v); s != "" {
+		return s
+	}
+	return "-"
+}
+
+// field returns value of field key as text without spaces, or "-" if it is missing
+func field(f map[string]interface{}, key string) string {
+	return strings.Replace(quoted(f, key), " ", "%20", -1)
+}
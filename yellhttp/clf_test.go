@@ -0,0 +1,52 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yellhttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/jfcg/yell/v2"
+)
+
+func TestCLFEncoder(t *testing.T) {
+	var sb strings.Builder
+	lg := yell.New(": access:", &sb, yell.Sinfo)
+	lg.SetEncoder(CLFEncoder{Combined: true})
+
+	h := Handler(&lg, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello"))
+	}))
+	r := httptest.NewRequest("GET", "/a%20b?x=1", nil)
+	r.SetBasicAuth("frank", "pw")
+	r.Header.Set("Referer", "http://example.com/")
+	r.Header.Set("User-Agent", `Mozilla "4"`)
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	re := regexp.MustCompile(`^192\.0\.2\.1 - frank \[\d\d/\w{3}/\d{4}:\d\d:\d\d:\d\d [+-]\d{4}\] ` +
+		`"GET /a%20b\?x=1 HTTP/1\.1" 200 5 "http://example\.com/" "Mozilla \\"4\\""` + "\n$")
+	if !re.MatchString(sb.String()) {
+		t.Fatal("unexpected record:", sb.String())
+	}
+
+	sb.Reset()
+	lg.SetEncoder(CLFEncoder{})
+	h = Handler(&lg, http.NotFoundHandler())
+	r = httptest.NewRequest("HEAD", "/", nil)
+	h.ServeHTTP(httptest.NewRecorder(), r)
+	if !strings.HasSuffix(sb.String(), `] "HEAD / HTTP/1.1" 404 19`+"\n") ||
+		!strings.HasPrefix(sb.String(), "192.0.2.1 - - [") {
+		t.Fatal("unexpected record:", sb.String())
+	}
+
+	if err := lg.Log(yell.Sinfo, "not access"); err == nil {
+		t.Fatal("must fail to encode")
+	}
+}
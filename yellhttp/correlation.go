@@ -0,0 +1,118 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yellhttp
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/jfcg/yell/v2"
+)
+
+// CorrelationHeader is the header of outgoing requests & responses for correlation IDs
+var CorrelationHeader = "X-Correlation-ID"
+
+// CorrelationHeaders are incoming request headers searched (in order) for correlation IDs
+var CorrelationHeaders = []string{"X-Correlation-ID", "X-Request-ID"}
+
+// CorrelationField is the record field of correlation IDs
+const CorrelationField = "correlation_id"
+
+// maximum accepted length of incoming correlation IDs
+const maxCorrelationLen = 128
+
+// correlationKey is the context key of correlation IDs
+type correlationKey struct{}
+
+// CorrelationID returns the first valid correlation ID in CorrelationHeaders of r, or
+// empty string. IDs must be printable ASCII of at most 128 characters, so clients cannot
+// inject arbitrary text into logs.
+func CorrelationID(r *http.Request) string {
+	for _, h := range CorrelationHeaders {
+		if id := r.Header.Get(h); validCorrelationID(id) {
+			return id
+		}
+	}
+	return ""
+}
+
+func validCorrelationID(id string) bool {
+	if id == "" || len(id) > maxCorrelationLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] >= 0x7f {
+			return false
+		}
+	}
+	return true
+}
+
+// WithCorrelationID returns a copy of ctx carrying id, with id pushed to its Scope as
+// CorrelationField, so records with the context have it (see yell.Scope).
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	ctx = context.WithValue(ctx, correlationKey{}, id)
+	return yell.WithScope(ctx, yell.Any(CorrelationField, id))
+}
+
+// CorrelationIDFrom returns correlation ID of ctx, or empty string
+func CorrelationIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(correlationKey{}).(string)
+	return id
+}
+
+// InjectCorrelationID sets CorrelationHeader of h to correlation ID of ctx, if any
+func InjectCorrelationID(ctx context.Context, h http.Header) {
+	if id := CorrelationIDFrom(ctx); id != "" {
+		h.Set(CorrelationHeader, id)
+	}
+}
+
+// Correlate returns an http.Handler that serves requests with next, with the correlation
+// ID of the request (see CorrelationID), or a new one (see yell.NewID), added to request
+// context (see WithCorrelationID) and CorrelationHeader of the response. Records logged
+// with the request context carry the ID. It should wrap Middleware to include the ID in
+// access logs:
+//
+//	handler := yellhttp.Correlate(yellhttp.Handler(&lg, mux))
+//
+//	func serve(w http.ResponseWriter, r *http.Request) {
+//		lg.Log(yell.Sinfo, r.Context(), "serving") // has correlation_id field
+//	}
+func Correlate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := CorrelationID(r)
+		if id == "" {
+			id = yell.NewID()
+		}
+		w.Header().Set(CorrelationHeader, id)
+		next.ServeHTTP(w, r.WithContext(WithCorrelationID(r.Context(), id)))
+	})
+}
+
+// Transport is an http.RoundTripper that propagates correlation IDs of request contexts
+// to outgoing requests, see InjectCorrelationID:
+//
+//	client := &http.Client{Transport: &yellhttp.Transport{}}
+//	req, _ := http.NewRequestWithContext(r.Context(), "GET", url, nil)
+//	resp, err := client.Do(req)
+type Transport struct {
+	Base http.RoundTripper // nil means http.DefaultTransport
+}
+
+// RoundTrip sends a copy of req with CorrelationHeader via Base
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if id := CorrelationIDFrom(req.Context()); id != "" && req.Header.Get(CorrelationHeader) == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set(CorrelationHeader, id)
+	}
+	return base.RoundTrip(req)
+}
@@ -0,0 +1,77 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yellhttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jfcg/yell/v2"
+	"github.com/jfcg/yell/v2/yelltest"
+)
+
+func TestCorrelate(t *testing.T) {
+	var rec yelltest.Recorder
+	lg := yell.New(": co:", &rec, yell.Sinfo)
+
+	// downstream service echoes received correlation ID
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Header.Get(CorrelationHeader)))
+	}))
+	defer down.Close()
+	client := &http.Client{Transport: &Transport{}}
+
+	var echoed string
+	mux := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lg.Log(yell.Sinfo, r.Context(), "serving")
+		req, _ := http.NewRequest("GET", down.URL, nil)
+		resp, err := client.Do(req.WithContext(r.Context()))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer resp.Body.Close()
+		b := make([]byte, 64)
+		n, _ := resp.Body.Read(b)
+		echoed = string(b[:n])
+	})
+	h := Correlate(Handler(&lg, mux))
+
+	// incoming ID is kept
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/x", nil)
+	r.Header.Set("X-Request-ID", "req-42")
+	h.ServeHTTP(w, r)
+	if w.Header().Get(CorrelationHeader) != "req-42" || echoed != "req-42" ||
+		!rec.ContainsMessage("serving correlation_id=req-42") ||
+		!rec.ContainsMessage("http correlation_id=req-42 method=GET") {
+		t.Fatal("ID not propagated", echoed, rec.Records())
+	}
+
+	// invalid IDs are replaced with new ones
+	rec.Reset()
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest("GET", "/x", nil)
+	r.Header.Set(CorrelationHeader, "bad id\n")
+	h.ServeHTTP(w, r)
+	id := w.Header().Get(CorrelationHeader)
+	if len(id) != 26 || echoed != id || !rec.ContainsMessage("correlation_id="+id) {
+		t.Fatal("new ID not generated", id, echoed)
+	}
+
+	if CorrelationIDFrom(r.Context()) != "" || CorrelationID(r) != "" ||
+		validCorrelationID(strings.Repeat("a", 129)) {
+		t.Fatal("unexpected ID")
+	}
+	hdr := http.Header{}
+	InjectCorrelationID(WithCorrelationID(r.Context(), "abc"), hdr)
+	if hdr.Get(CorrelationHeader) != "abc" {
+		t.Fatal("inject failed")
+	}
+}
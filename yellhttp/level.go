@@ -0,0 +1,87 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+// Package yellhttp provides net/http helpers for yell loggers.
+package yellhttp
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/jfcg/yell/v2"
+)
+
+// LevelHandler returns an http.Handler for managing minimum severities of registered
+// loggers (see yell.Register) at runtime, for example to enable info records of a live
+// service temporarily. Like net/http/pprof, it should be served on an internal address:
+//
+//	http.Handle("/debug/yell", yellhttp.LevelHandler())
+//
+// GET responds with a JSON object of logger names & minimum severities:
+//
+//	curl localhost:6060/debug/yell             => {"mypkg":"warn","other":"error"}
+//	curl localhost:6060/debug/yell?name=mypkg  => {"mypkg":"warn"}
+//
+// PUT sets minimum severity of a logger from level parameter or request body, and
+// responds like GET for that logger:
+//
+//	curl -X PUT 'localhost:6060/debug/yell?name=mypkg&level=info'
+//	curl -X PUT -d info 'localhost:6060/debug/yell?name=mypkg'
+func LevelHandler() http.Handler {
+	return http.HandlerFunc(serveLevels)
+}
+
+func serveLevels(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	var lg *yell.Logger
+	if name != "" {
+		if lg = yell.Lookup(name); lg == nil {
+			http.Error(w, "unknown logger: "+name, http.StatusNotFound)
+			return
+		}
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+	case http.MethodPut:
+		if lg == nil {
+			http.Error(w, "name parameter is required", http.StatusBadRequest)
+			return
+		}
+		level := r.URL.Query().Get("level")
+		if level == "" {
+			b, _ := ioutil.ReadAll(io.LimitReader(r.Body, 64))
+			level = string(b)
+		}
+		s, err := yell.ParseSeverity(level)
+		if err != nil {
+			http.Error(w, err.Error()+": "+strings.TrimSpace(level), http.StatusBadRequest)
+			return
+		}
+		lg.SetLevel(s)
+	default:
+		w.Header().Set("Allow", "GET, HEAD, PUT")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	levels := make(map[string]string)
+	if lg != nil {
+		levels[name] = lg.GetLevel().String()
+	} else {
+		for _, name := range yell.Names() {
+			if lg := yell.Lookup(name); lg != nil {
+				levels[name] = lg.GetLevel().String()
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(levels)
+}
@@ -0,0 +1,47 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yellhttp
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jfcg/yell/v2"
+)
+
+func TestLevelHandler(t *testing.T) {
+	lg := yell.New(": lvl:", ioutil.Discard, yell.Swarn)
+	yell.Register(&lg)
+	h := LevelHandler()
+
+	tests := [...]struct {
+		method, url, body string
+		code              int
+		resp              string
+	}{
+		{"GET", "/", "", 200, `{"lvl":"warn"}`},
+		{"GET", "/?name=lvl", "", 200, `{"lvl":"warn"}`},
+		{"GET", "/?name=none", "", 404, "unknown logger: none"},
+		{"PUT", "/?name=lvl&level=info", "", 200, `{"lvl":"info"}`},
+		{"PUT", "/?name=lvl", "Error\n", 200, `{"lvl":"error"}`},
+		{"PUT", "/?name=lvl&level=loud", "", 400, "yell: unknown severity: loud"},
+		{"PUT", "/", "", 400, "name parameter is required"},
+		{"POST", "/", "", 405, "method not allowed"},
+	}
+	for _, tc := range tests {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(tc.method, tc.url, strings.NewReader(tc.body)))
+		if rec.Code != tc.code || strings.TrimSpace(rec.Body.String()) != tc.resp {
+			t.Fatal("unexpected response:", tc.method, tc.url, rec.Code, rec.Body.String())
+		}
+	}
+	if lg.GetLevel() != yell.Serror {
+		t.Fatal("must set level")
+	}
+}
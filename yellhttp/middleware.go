@@ -0,0 +1,196 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yellhttp
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/jfcg/yell/v2"
+)
+
+// Middleware is an http.Handler that logs requests served by Next to Logger with method,
+// path, status, size, latency, remote (client host) & proto fields, and query, user
+// (of basic authentication), referer & user_agent fields if they are not empty. Scope
+// fields of the request context (see yell.WithScope & Correlate) are included. Usage:
+//
+//	http.ListenAndServe(":8080", &yellhttp.Middleware{Logger: &mypkg.Logger, Next: mux})
+type Middleware struct {
+	Logger *yell.Logger
+	Next   http.Handler
+
+	// Severity maps response status to record severity, nil means StatusSeverity
+	Severity func(status int) yell.Severity
+
+	// DebugHeader is a request header (like "X-Debug-Log") that lowers minimum severity
+	// of records with the request context to debug (see yell.WithMinLevel) if its value
+	// is in DebugValues, for targeted debugging in production. Empty means disabled. It
+	// should be removed from requests of untrusted clients by a proxy.
+	DebugHeader string
+
+	// DebugValues are allowed values of DebugHeader, nil means "1"
+	DebugValues []string
+}
+
+// Handler returns a Middleware with default settings
+func Handler(lg *yell.Logger, next http.Handler) http.Handler {
+	return &Middleware{Logger: lg, Next: next}
+}
+
+// StatusSeverity returns error for 5xx, warn for 4xx and info for other statuses
+func StatusSeverity(status int) yell.Severity {
+	switch {
+	case status >= 500:
+		return yell.Serror
+	case status >= 400:
+		return yell.Swarn
+	}
+	return yell.Sinfo
+}
+
+// ServeHTTP serves r with Next and logs it
+func (m *Middleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	rw := &responseWriter{ResponseWriter: w}
+	if m.debug(r) {
+		r = r.WithContext(yell.WithMinLevel(r.Context(), yell.Sdebug))
+	}
+	m.Next.ServeHTTP(rw, r)
+	latency := time.Since(start)
+
+	status := rw.status
+	if status == 0 {
+		status = http.StatusOK // nothing written
+	}
+	severity := m.Severity
+	if severity == nil {
+		severity = StatusSeverity
+	}
+
+	msg := []interface{}{r.Context(), "http", yell.Any("method", r.Method),
+		yell.Any("path", r.URL.Path), yell.Any("status", status), yell.Any("size", rw.size),
+		yell.Any("latency", latency), yell.Any("remote", remoteHost(r)),
+		yell.Any("proto", r.Proto)}
+
+	// optional fields
+	if r.URL.RawQuery != "" {
+		msg = append(msg, yell.Any("query", r.URL.RawQuery))
+	}
+	if u, _, ok := r.BasicAuth(); ok && u != "" {
+		msg = append(msg, yell.Any("user", u))
+	}
+	if s := r.Referer(); s != "" {
+		msg = append(msg, yell.Any("referer", s))
+	}
+	if s := r.UserAgent(); s != "" {
+		msg = append(msg, yell.Any("user_agent", s))
+	}
+	m.Logger.LogFrom(nil, severity(status), msg...) // location is this line, not net/http
+}
+
+// debug returns true if r has DebugHeader with an allowed value
+func (m *Middleware) debug(r *http.Request) bool {
+	if m.DebugHeader == "" {
+		return false
+	}
+	v := r.Header.Get(m.DebugHeader)
+	if m.DebugValues == nil {
+		return v == "1"
+	}
+	for _, a := range m.DebugValues {
+		if v == a && v != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteHost returns client host of r without port
+func remoteHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// responseWriter records status & size of a response
+type responseWriter struct {
+	http.ResponseWriter
+	status int
+	size   int64
+}
+
+func (rw *responseWriter) WriteHeader(status int) {
+	if rw.status == 0 {
+		rw.status = status
+	}
+	rw.ResponseWriter.WriteHeader(status)
+}
+
+func (rw *responseWriter) Write(p []byte) (int, error) {
+	if rw.status == 0 {
+		rw.status = http.StatusOK
+	}
+	n, err := rw.ResponseWriter.Write(p)
+	rw.size += int64(n)
+	return n, err
+}
+
+// Flush implements http.Flusher if underlying ResponseWriter does
+func (rw *responseWriter) Flush() {
+	if f, ok := rw.ResponseWriter.(http.Flusher); ok {
+		if rw.status == 0 {
+			rw.status = http.StatusOK
+		}
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker (for WebSocket upgrades) if underlying ResponseWriter
+// does, otherwise returns http.ErrNotSupported
+func (rw *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := rw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+	if rw.status == 0 {
+		rw.status = http.StatusSwitchingProtocols
+	}
+	return h.Hijack()
+}
+
+// Push implements http.Pusher if underlying ResponseWriter does, otherwise returns
+// http.ErrNotSupported
+func (rw *responseWriter) Push(target string, opts *http.PushOptions) error {
+	if p, ok := rw.ResponseWriter.(http.Pusher); ok {
+		return p.Push(target, opts)
+	}
+	return http.ErrNotSupported
+}
+
+// ReadFrom implements io.ReaderFrom, so underlying ResponseWriter can use sendfile
+func (rw *responseWriter) ReadFrom(r io.Reader) (n int64, err error) {
+	if rw.status == 0 {
+		rw.status = http.StatusOK
+	}
+	if rf, ok := rw.ResponseWriter.(io.ReaderFrom); ok {
+		n, err = rf.ReadFrom(r)
+	} else {
+		n, err = io.Copy(rw.ResponseWriter, r)
+	}
+	rw.size += n
+	return
+}
+
+// Unwrap returns underlying ResponseWriter for http.ResponseController
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
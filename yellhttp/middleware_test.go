@@ -0,0 +1,141 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yellhttp
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jfcg/yell/v2"
+	"github.com/jfcg/yell/v2/yelltest"
+)
+
+func TestMiddleware(t *testing.T) {
+	var rec yelltest.Recorder
+	lg := yell.New(": mw:", &rec, yell.Sinfo)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello"))
+		w.(http.Flusher).Flush()
+	})
+	mux.HandleFunc("/fail", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	})
+	mux.HandleFunc("/empty", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/copy", func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(w, strings.NewReader("copied"))
+	})
+	h := Handler(&lg, mux)
+
+	for _, p := range [...]string{"/ok", "/fail", "/missing", "/empty", "/copy"} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", p, nil))
+	}
+
+	recs := rec.Records()
+	if len(recs) != 5 || recs[0].Level != yell.Sinfo || recs[1].Level != yell.Serror ||
+		recs[2].Level != yell.Swarn || recs[3].Level != yell.Sinfo ||
+		!strings.HasPrefix(recs[0].Caller, "middleware.go:") ||
+		!rec.ContainsMessage("http method=GET path=/ok status=200 size=5 latency=") ||
+		!rec.ContainsMessage("path=/fail status=500 size=5 ") ||
+		!rec.ContainsMessage("path=/empty status=200 size=0 ") ||
+		!rec.ContainsMessage("path=/copy status=200 size=6 ") {
+		t.Fatal("unexpected records:", recs)
+	}
+
+	// custom severities
+	m := &Middleware{Logger: &lg, Next: mux, Severity: func(int) yell.Severity {
+		return yell.Sfatal
+	}}
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/ok", nil))
+	if rec.CountAtLevel(yell.Sfatal) != 1 {
+		t.Fatal("must use custom severity")
+	}
+}
+
+func TestHijack(t *testing.T) {
+	var rec yelltest.Recorder
+	lg := yell.New(": hj:", &rec, yell.Sinfo)
+
+	h := Handler(&lg, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := w.(http.Pusher).Push("/x", nil); err != http.ErrNotSupported {
+			t.Error("push must not be supported:", err)
+		}
+		conn, buf, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		buf.WriteString("HTTP/1.1 200 OK\r\nContent-Length: 2\r\nConnection: close\r\n\r\nhi")
+		buf.Flush()
+		conn.Close()
+	}))
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.ServeHTTP(w, r)
+		close(done)
+	}))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	<-done
+	if string(body) != "hi" || !rec.ContainsMessage("status=101 ") {
+		t.Fatal("must hijack:", string(body), rec.Records())
+	}
+}
+
+func TestDebugHeader(t *testing.T) {
+	var rec yelltest.Recorder
+	lg := yell.New(": dh:", &rec, yell.Serror)
+
+	h := func(w http.ResponseWriter, r *http.Request) {
+		lg.Log(yell.Sdebug, r.Context(), "details")
+		lg.Log(yell.Sdebug, "no context")
+	}
+	m := &Middleware{Logger: &lg, Next: http.HandlerFunc(h), DebugHeader: "X-Debug-Log"}
+
+	serve := func(value string) {
+		req := httptest.NewRequest("GET", "/", nil)
+		if value != "" {
+			req.Header.Set("X-Debug-Log", value)
+		}
+		m.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	serve("")
+	serve("yes")
+	if len(rec.Records()) != 0 {
+		t.Fatal("debug records must need an allowed header:", rec.Records())
+	}
+	serve("1")
+	recs := rec.Records()
+	if len(recs) != 2 || recs[0].Level != yell.Sdebug || !rec.ContainsMessage("details") ||
+		recs[1].Level != yell.Sinfo || rec.ContainsMessage("no context") {
+		t.Fatal("debug header must lower level of request records:", recs)
+	}
+
+	m.DebugValues = []string{"yes"}
+	serve("1")
+	serve("yes")
+	if len(rec.Records()) != 4 || lg.GetLevel() != yell.Serror {
+		t.Fatal("unexpected records:", rec.Records())
+	}
+
+	m.DebugHeader = ""
+	serve("yes")
+	if len(rec.Records()) != 4 {
+		t.Fatal("empty header must disable escalation")
+	}
+}
@@ -0,0 +1,104 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yellhttp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jfcg/yell/v2"
+)
+
+// LevelSource fetches level configuration of registered loggers, a yell.SetLevels spec
+// like "net.*=debug, db=info, *=warn". Key-value stores (like etcd) can be polled with
+// their clients by custom sources.
+type LevelSource func(ctx context.Context) (spec string, err error)
+
+// maximum accepted size of level configurations
+const maxSpecLen = 64 << 10
+
+// URLSource returns a LevelSource that fetches configuration from body of url responses
+// with client, nil means http.DefaultClient. For example a Consul key can be served raw:
+//
+//	src := yellhttp.URLSource("http://consul:8500/v1/kv/config/loglevels?raw", nil)
+func URLSource(url string, client *http.Client) LevelSource {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return func(ctx context.Context) (string, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return "", err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+
+		b, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxSpecLen))
+		if err == nil && resp.StatusCode != http.StatusOK {
+			err = fmt.Errorf("yellhttp: level source responded %s", resp.Status)
+		}
+		return strings.TrimSpace(string(b)), err
+	}
+}
+
+// PollLevels fetches configuration from src now and every interval, and applies it to
+// registered loggers with yell.SetLevels when it changes, for fleet-wide verbosity
+// control:
+//
+//	stop := yellhttp.PollLevels(src, time.Minute, func(err error) {
+//		mypkg.Logger.Log(yell.Swarn, "level polling:", err)
+//	})
+//	defer stop()
+//
+// Fetch & configuration errors are passed to onError if it is not nil, and levels stay
+// unchanged. Returns a function that stops polling. Panics if src is nil or interval is
+// not positive.
+func PollLevels(src LevelSource, interval time.Duration, onError func(error)) (stop func()) {
+	if src == nil || interval <= 0 {
+		panic("yellhttp: invalid arguments to PollLevels")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		var last string
+		applied := false
+		for {
+			spec, err := src(ctx)
+			if ctx.Err() != nil {
+				return // stopped while fetching
+			}
+			if err == nil && (!applied || spec != last) {
+				if err = yell.SetLevels(spec); err == nil {
+					last, applied = spec, true
+				}
+			}
+			if err != nil && onError != nil {
+				onError(err)
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() { once.Do(cancel) }
+}
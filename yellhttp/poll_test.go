@@ -0,0 +1,97 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yellhttp
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jfcg/yell/v2"
+)
+
+func TestPollLevels(t *testing.T) {
+	lg := yell.New(": poll:", ioutil.Discard, yell.Swarn)
+	yell.Register(&lg)
+	defer yell.SetLevels("")
+
+	var mu sync.Mutex
+	spec, status := "poll=debug", http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.WriteHeader(status)
+		w.Write([]byte(spec + "\n"))
+	}))
+	defer srv.Close()
+	set := func(s string, code int) {
+		mu.Lock()
+		spec, status = s, code
+		mu.Unlock()
+	}
+
+	errs := make(chan error, 100)
+	stop := PollLevels(URLSource(srv.URL, nil), 10*time.Millisecond, func(err error) {
+		errs <- err
+	})
+	defer stop()
+	wait := func(level yell.Severity) {
+		for i := 0; i < 100 && lg.GetLevel() != level; i++ {
+			time.Sleep(10 * time.Millisecond)
+		}
+		if lg.GetLevel() != level {
+			t.Fatal("level must be polled:", lg.GetLevel(), level)
+		}
+	}
+	wait(yell.Sdebug)
+
+	// unchanged spec is not reapplied
+	lg.SetLevel(yell.Serror)
+	time.Sleep(50 * time.Millisecond)
+	if lg.GetLevel() != yell.Serror {
+		t.Fatal("unchanged spec must not be reapplied")
+	}
+
+	set("poll=info", http.StatusOK)
+	wait(yell.Sinfo)
+
+	set("poll=loud", http.StatusOK)
+	if err := <-errs; err == nil || lg.GetLevel() != yell.Sinfo {
+		t.Fatal("invalid spec must be reported", err)
+	}
+	set("poll=warn", http.StatusNotFound)
+	for err := range errs {
+		if err.Error() == "yellhttp: level source responded 404 Not Found" {
+			break
+		}
+	}
+	if lg.GetLevel() != yell.Sinfo {
+		t.Fatal("failed fetch must not change levels")
+	}
+
+	stop()
+	stop()
+	set("poll=fatal", http.StatusOK)
+	time.Sleep(50 * time.Millisecond)
+	if lg.GetLevel() != yell.Sinfo {
+		t.Fatal("stopped polling changed level")
+	}
+
+	if _, err := URLSource(":bad url", nil)(context.Background()); err == nil {
+		t.Fatal("expected bad url error")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatal("invalid arguments must panic")
+		}
+	}()
+	PollLevels(nil, time.Second, nil)
+}
@@ -0,0 +1,117 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yellhttp
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/jfcg/yell/v2"
+)
+
+// record fields of trace & span IDs, yell.GCPEncoder maps them to Cloud Trace keys
+const (
+	TraceField = "trace"
+	SpanField  = "span"
+)
+
+// TraceContext is the trace & span IDs of a request, as lower case hex
+type TraceContext struct {
+	TraceID string // 32 digits, 16 digit B3 IDs are left padded with zeros
+	SpanID  string // 16 digits
+	Sampled bool
+}
+
+const hexDigits = "0123456789abcdef"
+
+// hexOnly checks if s is non-empty lower case hex
+func hexOnly(s string) bool {
+	return s != "" && strings.Trim(s, hexDigits) == ""
+}
+
+// isHex checks if s is lower case hex with n digits, not all zeros
+func isHex(s string, n int) bool {
+	return len(s) == n && hexOnly(s) && strings.Trim(s, "0") != ""
+}
+
+// ParseTraceparent parses a W3C traceparent header value like
+// 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+func ParseTraceparent(s string) (tc TraceContext, ok bool) {
+	p := strings.Split(strings.TrimSpace(s), "-")
+	if len(p) < 4 || len(p[0]) != 2 || !hexOnly(p[0]) || p[0] == "ff" ||
+		p[0] == "00" && len(p) != 4 || !isHex(p[1], 32) || !isHex(p[2], 16) ||
+		len(p[3]) != 2 || !hexOnly(p[3]) {
+		return
+	}
+	flags := strings.IndexByte(hexDigits, p[3][1])
+	return TraceContext{p[1], p[2], flags&1 != 0}, true
+}
+
+// b3Trace returns 16 or 32 digit B3 trace ID s as 32 digits
+func b3Trace(s string) (string, bool) {
+	s = strings.ToLower(s)
+	if len(s) == 16 {
+		s = "0000000000000000" + s
+	}
+	return s, isHex(s, 32)
+}
+
+// ParseB3 parses B3 propagation headers of h, either the single b3 header like
+// 80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1 or X-B3-TraceId, X-B3-SpanId &
+// X-B3-Sampled headers
+func ParseB3(h http.Header) (tc TraceContext, ok bool) {
+	var trace, span, sampled string
+	if b3 := h.Get("b3"); b3 != "" {
+		p := strings.Split(b3, "-")
+		if len(p) < 2 {
+			return // sampling decision only
+		}
+		trace, span = p[0], p[1]
+		if len(p) > 2 {
+			sampled = p[2]
+		}
+	} else {
+		trace, span = h.Get("X-B3-TraceId"), h.Get("X-B3-SpanId")
+		sampled = h.Get("X-B3-Sampled")
+		if h.Get("X-B3-Flags") == "1" {
+			sampled = "d"
+		}
+	}
+
+	if tc.TraceID, ok = b3Trace(trace); !ok {
+		return TraceContext{}, false
+	}
+	if tc.SpanID = strings.ToLower(span); !isHex(tc.SpanID, 16) {
+		return TraceContext{}, false
+	}
+	tc.Sampled = sampled == "1" || sampled == "d" || sampled == "true"
+	return tc, true
+}
+
+// TraceFromRequest returns trace context of r from its traceparent header, or B3 headers
+func TraceFromRequest(r *http.Request) (TraceContext, bool) {
+	if tc, ok := ParseTraceparent(r.Header.Get("traceparent")); ok {
+		return tc, true
+	}
+	return ParseB3(r.Header)
+}
+
+// Trace returns an http.Handler that serves requests with next, binding trace & span IDs
+// of the request (see TraceFromRequest), if any, to request context as TraceField &
+// SpanField, so records logged with the context can be correlated with traces even
+// without a tracing SDK (see yell.Scope). Like Correlate, it should wrap Middleware:
+//
+//	handler := yellhttp.Trace(yellhttp.Handler(&lg, mux))
+func Trace(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if tc, ok := TraceFromRequest(r); ok {
+			r = r.WithContext(yell.WithScope(r.Context(),
+				yell.Any(TraceField, tc.TraceID), yell.Any(SpanField, tc.SpanID)))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,85 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yellhttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jfcg/yell/v2"
+	"github.com/jfcg/yell/v2/yelltest"
+)
+
+const (
+	trace32 = "4bf92f3577b34da6a3ce929d0e0e4736"
+	span16  = "00f067aa0ba902b7"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	tc, ok := ParseTraceparent("00-" + trace32 + "-" + span16 + "-01")
+	if !ok || tc != (TraceContext{trace32, span16, true}) {
+		t.Fatal("valid traceparent rejected", tc)
+	}
+	if tc, ok = ParseTraceparent("01-" + trace32 + "-" + span16 + "-00-future"); !ok || tc.Sampled {
+		t.Fatal("future version rejected", tc)
+	}
+	for _, s := range []string{"", "00-" + trace32 + "-" + span16,
+		"ff-" + trace32 + "-" + span16 + "-01", "00-" + trace32 + "-" + span16 + "-01-x",
+		"00-00000000000000000000000000000000-" + span16 + "-01",
+		"00-" + trace32 + "-0000000000000000-01", "00-4BF92F3577B34DA6A3CE929D0E0E4736-" + span16 + "-01",
+		"00-" + trace32 + "-" + span16 + "-0g", "0g-" + trace32 + "-" + span16 + "-01"} {
+		if _, ok = ParseTraceparent(s); ok {
+			t.Fatal("invalid traceparent accepted", s)
+		}
+	}
+}
+
+func TestParseB3(t *testing.T) {
+	tc, ok := ParseB3(http.Header{"B3": {"80F198EE56343BA864FE8B2A57D3EFF7-e457b5a2e4d86bd1-1-05e3ac9a4f6e3b90"}})
+	if !ok || tc != (TraceContext{"80f198ee56343ba864fe8b2a57d3eff7", "e457b5a2e4d86bd1", true}) {
+		t.Fatal("single header rejected", tc)
+	}
+	h := http.Header{}
+	h.Set("X-B3-TraceId", "a3ce929d0e0e4736")
+	h.Set("X-B3-SpanId", span16)
+	if tc, ok = ParseB3(h); !ok || tc.TraceID != "0000000000000000a3ce929d0e0e4736" || tc.Sampled {
+		t.Fatal("multi headers rejected", tc)
+	}
+	h.Set("X-B3-Flags", "1")
+	if tc, _ = ParseB3(h); !tc.Sampled {
+		t.Fatal("debug flag ignored")
+	}
+
+	for _, b3 := range []string{"0", "d", trace32, trace32 + "-xyz", "abc-" + span16} {
+		if _, ok = ParseB3(http.Header{"B3": {b3}}); ok {
+			t.Fatal("invalid b3 accepted", b3)
+		}
+	}
+}
+
+func TestTrace(t *testing.T) {
+	var rec yelltest.Recorder
+	lg := yell.New(": tr:", &rec, yell.Sinfo)
+	h := Trace(Handler(&lg, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lg.Log(yell.Sinfo, r.Context(), "serving")
+	})))
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("traceparent", "00-"+trace32+"-"+span16+"-01")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+	if !rec.ContainsMessage("serving trace="+trace32+" span="+span16) ||
+		!rec.ContainsMessage("http trace="+trace32+" span="+span16+" method=GET") {
+		t.Fatal("trace fields missing", rec.Records())
+	}
+
+	rec.Reset()
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	if rec.Len() != 2 || rec.ContainsMessage("trace=") {
+		t.Fatal("unexpected trace fields", rec.Records())
+	}
+}
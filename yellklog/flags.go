@@ -0,0 +1,163 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+// Package yellklog registers glog/klog style command line flags that configure yell
+// Loggers, for binaries of the Kubernetes ecosystem whose users expect them:
+//
+//	var lg = yell.New(": myctl:", os.Stderr, yell.Sinfo)
+//
+//	func main() {
+//		kf := yellklog.Register(nil)
+//		flag.Parse()
+//		if err := kf.Apply(&lg); err != nil {
+//			log.Fatal(err)
+//		}
+//		defer lg.Close()
+//	}
+//
+// yell has no verbosity levels, any -v above zero enables debug records.
+package yellklog
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/jfcg/yell/v2"
+)
+
+// Flags holds values of glog/klog style flags
+type Flags struct {
+	V               int           // -v: verbosity, above zero means debug
+	VModule         string        // -vmodule: comma separated name=N rules for registered Loggers
+	LogToStderr     bool          // -logtostderr: log to stderr instead of files
+	AlsoLogToStderr bool          // -alsologtostderr: log to files and stderr
+	StderrThreshold yell.Severity // -stderrthreshold: records at or above go to stderr too
+	LogFile         string        // -log_file: log file, overrides LogDir
+	LogDir          string        // -log_dir: directory of log file named after the program
+}
+
+// Register defines glog/klog style flags in fs (flag.CommandLine if nil) and returns
+// their values, which should be applied with Apply after parsing. Defaults are like klog:
+// logging to stderr with verbosity zero & stderr threshold of error.
+func Register(fs *flag.FlagSet) *Flags {
+	if fs == nil {
+		fs = flag.CommandLine
+	}
+	f := &Flags{LogToStderr: true, StderrThreshold: yell.Serror}
+	fs.IntVar(&f.V, "v", f.V, "number for the log level verbosity")
+	fs.StringVar(&f.VModule, "vmodule", f.VModule,
+		"comma-separated list of logger=N settings for logger-filtered logging")
+	fs.BoolVar(&f.LogToStderr, "logtostderr", f.LogToStderr, "log to standard error instead of files")
+	fs.BoolVar(&f.AlsoLogToStderr, "alsologtostderr", f.AlsoLogToStderr,
+		"log to standard error as well as files")
+	fs.Var(&f.StderrThreshold, "stderrthreshold", "logs at or above this threshold go to stderr")
+	fs.StringVar(&f.LogFile, "log_file", f.LogFile, "if non-empty, use this log file")
+	fs.StringVar(&f.LogDir, "log_dir", f.LogDir, "if non-empty, write log files in this directory")
+	return f
+}
+
+// level returns yell severity of verbosity v
+func level(v int) yell.Severity {
+	if v > 0 {
+		return yell.Sdebug
+	}
+	return yell.Sinfo
+}
+
+// Apply configures lg with Flags: its level with V, its writer with the output flags.
+// VModule rules are applied to registered Loggers by name, see yell.SetLevels. Log files
+// are opened for appending, they are closed by lg.Close. Records are copied to stderr by
+// their level (see yell.LevelWriter) with any encoder.
+func (f *Flags) Apply(lg *yell.Logger) error {
+	spec, err := vmoduleLevels(f.VModule)
+	if err != nil {
+		return err
+	}
+	if spec != "" {
+		if err = yell.SetLevels(spec); err != nil {
+			return err
+		}
+	}
+	lg.SetLevel(level(f.V))
+
+	if f.LogToStderr {
+		lg.UpdateWriter(os.Stderr)
+		return nil
+	}
+	path := f.LogFile
+	if path == "" {
+		path = filepath.Join(f.LogDir, filepath.Base(os.Args[0])+".log")
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	lg.UpdateWriter(&fileWriter{file, os.Stderr, f.StderrThreshold, f.AlsoLogToStderr})
+	return nil
+}
+
+// vmoduleLevels converts glog style vmodule rules to yell.SetLevels spec
+func vmoduleLevels(vmodule string) (string, error) {
+	var rules []string
+	for _, rule := range strings.Split(vmodule, ",") {
+		if rule = strings.TrimSpace(rule); rule == "" {
+			continue
+		}
+		i := strings.LastIndexByte(rule, '=')
+		if i < 0 {
+			return "", fmt.Errorf("yellklog: invalid vmodule rule %q", rule)
+		}
+		v, err := strconv.Atoi(strings.TrimSpace(rule[i+1:]))
+		if err != nil {
+			return "", fmt.Errorf("yellklog: invalid vmodule rule %q: %w", rule, err)
+		}
+		rules = append(rules, rule[:i]+"="+level(v).String())
+	}
+	return strings.Join(rules, ","), nil
+}
+
+// fileWriter writes records to a log file and copies them to stderr if all is set, or
+// by their level
+type fileWriter struct {
+	file      *os.File
+	stderr    io.Writer
+	threshold yell.Severity
+	all       bool
+}
+
+// Write writes record p of unknown level to log file, and to stderr if all is set
+func (w *fileWriter) Write(p []byte) (int, error) {
+	n, err := w.file.Write(p)
+	if w.all {
+		w.stderr.Write(p)
+	}
+	return n, err
+}
+
+// WriteLevel writes record p to log file, and to stderr if all is set or level is at or
+// above threshold
+func (w *fileWriter) WriteLevel(level yell.Severity, p []byte) (int, error) {
+	n, err := w.file.Write(p)
+	if w.all || level >= w.threshold {
+		w.stderr.Write(p)
+	}
+	return n, err
+}
+
+// Sync commits log file to stable storage
+func (w *fileWriter) Sync() error {
+	return w.file.Sync()
+}
+
+// Close closes log file
+func (w *fileWriter) Close() error {
+	return w.file.Close()
+}
@@ -0,0 +1,87 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yellklog
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jfcg/yell/v2"
+)
+
+func TestApply(t *testing.T) {
+	dir, err := ioutil.TempDir("", "yellklog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	sub := yell.New(": klog.sub:", ioutil.Discard, yell.Swarn)
+	yell.Register(&sub)
+	defer yell.SetLevels("")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	kf := Register(fs)
+	path := filepath.Join(dir, "app.log")
+	if err = fs.Parse([]string{"-v=2", "-vmodule=klog.*=0", "-logtostderr=false",
+		"-log_file=" + path, "-stderrthreshold=warn"}); err != nil {
+		t.Fatal(err)
+	}
+
+	lg := yell.New(": klog:", os.Stderr, yell.Swarn)
+	if err = kf.Apply(&lg); err != nil {
+		t.Fatal(err)
+	}
+	if lg.GetLevel() != yell.Sdebug || sub.GetLevel() != yell.Sinfo {
+		t.Fatal("bad levels", lg.GetLevel(), sub.GetLevel())
+	}
+
+	lg.Log(yell.Sdebug, "verbose")
+	if err = lg.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := ioutil.ReadFile(path); !strings.HasSuffix(string(b), "verbose\n") {
+		t.Fatal("bad log file", string(b))
+	}
+
+	bad := &Flags{VModule: "x=high"}
+	if bad.Apply(&lg) == nil || (&Flags{LogToStderr: true}).Apply(&lg) != nil {
+		t.Fatal("unexpected apply results")
+	}
+}
+
+func TestFileWriter(t *testing.T) {
+	file, err := ioutil.TempFile("", "yellklog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+
+	var sb strings.Builder
+	lg := yell.New(": klog:", &fileWriter{file, &sb, yell.Swarn, false}, yell.Sdebug)
+	lg.Log(yell.Sinfo, "file only")
+	lg.Log(yell.Serror, "copied")
+
+	// levels do not depend on encoder
+	lg.SetFormat(yell.Fjson)
+	lg.Log(yell.Sinfo, "json file only")
+	lg.Log(yell.Swarn, "json copied")
+	if err = lg.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, _ := ioutil.ReadFile(file.Name())
+	if strings.Count(string(b), "\n") != 4 || strings.Contains(sb.String(), "file only") ||
+		strings.Count(sb.String(), "\n") != 2 || !strings.Contains(sb.String(), "copied\n") ||
+		!strings.Contains(sb.String(), `"msg":"json copied"`) {
+		t.Fatal("bad outputs", string(b), sb.String())
+	}
+}
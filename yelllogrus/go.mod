@@ -0,0 +1,17 @@
+module github.com/jfcg/yell/yelllogrus
+
+go 1.23
+
+require (
+	github.com/jfcg/yell/v2 v2.0.0
+	github.com/sirupsen/logrus v1.10.2
+)
+
+require (
+	github.com/stretchr/objx v0.5.3 // indirect
+	github.com/stretchr/testify v1.12.1 // indirect
+	go.yaml.in/yaml/v3 v3.0.5 // indirect
+	golang.org/x/sys v0.13.0 // indirect
+)
+
+replace github.com/jfcg/yell/v2 => ../
@@ -0,0 +1,88 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+// Package yelllogrus forwards logrus entries to a yell Logger, for incremental migration
+// of code bases that still use logrus. It is a separate module, so yell itself does not
+// depend on logrus.
+//
+//	logrus.SetOutput(ioutil.Discard) // yell does the output
+//	logrus.AddHook(yelllogrus.New(&lg))
+//
+//	logrus.WithField("user", id).Warn("login failed") // logged by lg
+package yelllogrus
+
+import (
+	"sort"
+
+	"github.com/jfcg/yell/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// Hook is a logrus.Hook that logs entries to a yell Logger
+type Hook struct {
+	lg     *yell.Logger
+	levels []logrus.Level
+}
+
+var _ logrus.Hook = (*Hook)(nil)
+
+// logrusPkgs are skipped to find request locations, see yell.Logger.LogFrom
+var logrusPkgs = []string{"github.com/sirupsen/logrus", "github.com/jfcg/yell/yelllogrus"}
+
+// New creates a Hook that logs entries of levels (all levels if none given) to lg, so
+// later changes to lg (like its level or writer) apply. Request locations are found by
+// skipping logrus frames, see yell.Logger.LogFrom.
+func New(lg *yell.Logger, levels ...logrus.Level) *Hook {
+	if len(levels) == 0 {
+		levels = logrus.AllLevels
+	}
+	return &Hook{lg: lg, levels: levels}
+}
+
+// Levels returns logrus levels of Hook
+func (h *Hook) Levels() []logrus.Level {
+	return h.levels
+}
+
+// Severity returns yell severity of logrus level: panic & fatal are yell.Sfatal, trace
+// is yell.Sdebug.
+func Severity(level logrus.Level) yell.Severity {
+	switch level {
+	case logrus.PanicLevel, logrus.FatalLevel:
+		return yell.Sfatal
+	case logrus.ErrorLevel:
+		return yell.Serror
+	case logrus.WarnLevel:
+		return yell.Swarn
+	case logrus.InfoLevel:
+		return yell.Sinfo
+	}
+	return yell.Sdebug
+}
+
+// Fire logs e with its message, error (logrus.ErrorKey field) and other fields sorted by
+// key. Scope fields of e.Context are included, see yell.Scope.
+func (h *Hook) Fire(e *logrus.Entry) error {
+	msg := make([]interface{}, 0, len(e.Data)+3)
+	if e.Context != nil {
+		msg = append(msg, e.Context)
+	}
+	msg = append(msg, e.Message)
+
+	keys := make([]string, 0, len(e.Data))
+	for k, v := range e.Data {
+		if err, ok := v.(error); ok && k == logrus.ErrorKey {
+			msg = append(msg, err) // as message member for error details
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		msg = append(msg, yell.Any(k, e.Data[k]))
+	}
+	return h.lg.LogFrom(logrusPkgs, Severity(e.Level), msg...)
+}
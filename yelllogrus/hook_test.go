@@ -0,0 +1,61 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yelllogrus
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/jfcg/yell/v2"
+	"github.com/sirupsen/logrus"
+)
+
+func TestHook(t *testing.T) {
+	var recs []yell.Record
+	lg := yell.New(": lr:", ioutil.Discard, yell.Sinfo)
+	lg.AddObserver(func(r yell.Record) { recs = append(recs, r) })
+
+	lr := logrus.New()
+	lr.SetOutput(ioutil.Discard)
+	lr.SetLevel(logrus.TraceLevel)
+	lr.AddHook(New(&lg))
+
+	ctx := yell.WithScope(context.Background(), yell.Any("req", 9))
+	lr.WithContext(ctx).WithFields(logrus.Fields{"user": "ann", "attempt": 2}).
+		WithError(errors.New("bad password")).Warn("login failed")
+	lr.Debug("filtered by lg")
+	lr.Info("plain")
+
+	if len(recs) != 2 {
+		t.Fatal("expected two records:", recs)
+	}
+	// test frames belong to yelllogrus, only hook & logrus frames must be skipped
+	skipped := map[string]bool{"hook.go": true, "entry.go": true, "logger.go": true}
+	r := recs[0]
+	if r.Level != yell.Swarn || r.Text() != "login failed bad password" || skipped[r.File] ||
+		len(r.Fields) != 3 || r.Fields[0].Key != "req" || r.Fields[1].Key != "attempt" ||
+		r.Fields[2].Value != "ann" || recs[1].Msg != "plain" || recs[1].Level != yell.Sinfo {
+		t.Fatal("unexpected records:", recs)
+	}
+
+	// later changes of lg apply
+	var b2 strings.Builder
+	lg.SetLevel(yell.Sdebug)
+	lg.UpdateWriter(&b2)
+	lr.Debug("later")
+	if len(recs) != 3 || !strings.HasSuffix(b2.String(), " later\n") {
+		t.Fatal("Hook must log through lg:", b2.String())
+	}
+
+	if Severity(logrus.PanicLevel) != yell.Sfatal || Severity(logrus.TraceLevel) != yell.Sdebug ||
+		len(New(&lg, logrus.ErrorLevel).Levels()) != 1 {
+		t.Fatal("bad levels")
+	}
+}
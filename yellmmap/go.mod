@@ -0,0 +1,10 @@
+module github.com/jfcg/yell/yellmmap
+
+go 1.26.0
+
+require (
+	github.com/jfcg/yell/v2 v2.0.0
+	golang.org/x/sys v0.48.0
+)
+
+replace github.com/jfcg/yell/v2 => ../
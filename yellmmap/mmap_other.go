@@ -0,0 +1,30 @@
+//go:build !aix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris
+// +build !aix,!darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris
+
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yellmmap
+
+import (
+	"errors"
+	"os"
+)
+
+// errUnsupported is returned by Open on systems without mmap support
+var errUnsupported = errors.New("yellmmap: unsupported system")
+
+func mmap(*os.File, int) ([]byte, error) {
+	return nil, errUnsupported
+}
+
+func munmap([]byte) error {
+	return errUnsupported
+}
+
+func msync([]byte) error {
+	return errUnsupported
+}
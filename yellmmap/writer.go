@@ -0,0 +1,282 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+// Package yellmmap provides a memory-mapped log file writer for latency-critical
+// services, where a write system call per record is too costly. Records are copied into
+// a preallocated shared mapping of the file and committed with periodic msync. It is a
+// separate module, so yell itself does not depend on golang.org/x/sys. Only unix-like
+// systems are supported.
+//
+//	w, err := yellmmap.Open("app.log", 64<<20, time.Second)
+//	lg := yell.New(": mypkg:", w, yell.Sinfo)
+//	defer lg.Close()
+package yellmmap
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"sync"
+	"time"
+)
+
+// ErrClosed is returned by Write after Close
+var ErrClosed = errors.New("yellmmap: writer is closed")
+
+// Writer appends records to a memory-mapped file. When the mapping is full, the file is
+// extended by its preallocation size and mapped again. While the file is open, it has
+// zero bytes after the last record, which are truncated by Close. If extending fails,
+// later Writes return its error, Close still truncates & closes the file. It is safe for
+// concurrent use.
+type Writer struct {
+	mapMu  sync.RWMutex // held exclusively to change mapping, shared to use it
+	mu     sync.Mutex   // guards fields below
+	f      *os.File
+	data   []byte // mapped region
+	off    int    // end of records
+	synced int    // end of committed records
+	grow   int    // preallocation size
+	stop   chan struct{}
+	done   chan struct{}
+	err    error // error of last background msync
+	fail   error // error of extending the mapping, returned by later Writes
+	closed bool
+}
+
+// Open opens (or creates) log file at path for appending with mapped regions of size
+// bytes, rounded up to page size. Records are committed every interval if positive,
+// otherwise only on Sync & Close.
+func Open(path string, size int, interval time.Duration) (*Writer, error) {
+	if size <= 0 {
+		return nil, errors.New("yellmmap: non-positive size")
+	}
+	page := os.Getpagesize()
+	size = (size + page - 1) / page * page
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	// continue after records of existing file, ignoring zeros of an unclosed mapping
+	w := &Writer{f: f, grow: size}
+	n := int(fi.Size())
+	if err = w.remap(n + size); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if n > 0 {
+		w.off = bytes.LastIndexByte(w.data[:n], '\n') + 1
+		w.synced = w.off
+	}
+
+	if interval > 0 {
+		w.stop, w.done = make(chan struct{}), make(chan struct{})
+		go w.syncLoop(interval, w.stop)
+	}
+	return w, nil
+}
+
+// remap maps file with size bytes, extending the file if needed
+func (w *Writer) remap(size int) error {
+	if w.data != nil {
+		if err := munmap(w.data); err != nil {
+			return err
+		}
+		w.data = nil
+	}
+	if err := w.f.Truncate(int64(size)); err != nil {
+		return err
+	}
+	data, err := mmap(w.f, size)
+	if err != nil {
+		return err
+	}
+	w.data = data
+	return nil
+}
+
+// Write copies p to the mapping, extending it if necessary. p is kept even if the error
+// of a background commit is returned.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mapMu.RLock()
+	w.mu.Lock()
+	if err := w.check(); err != nil || w.off+len(p) > len(w.data) {
+		w.mu.Unlock()
+		w.mapMu.RUnlock()
+		if err != nil {
+			return 0, err
+		}
+		return w.extend(p)
+	}
+	w.off += copy(w.data[w.off:], p)
+
+	err := w.err
+	w.err = nil
+	w.mu.Unlock()
+	w.mapMu.RUnlock()
+	return len(p), err
+}
+
+// check returns ErrClosed or the error of extending the mapping. Must be called with
+// w.mu held.
+func (w *Writer) check() error {
+	if w.closed {
+		return ErrClosed
+	}
+	return w.fail
+}
+
+// extend grows the mapping for p and copies it
+func (w *Writer) extend(p []byte) (int, error) {
+	w.mapMu.Lock()
+	defer w.mapMu.Unlock()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.check(); err != nil {
+		return 0, err
+	}
+	if need := w.off + len(p); need > len(w.data) {
+		// commit before munmap, then grow
+		if err := w.sync(); err != nil {
+			return 0, err
+		}
+		if err := w.remap((need + w.grow - 1) / w.grow * w.grow); err != nil {
+			w.fail = err
+			return 0, err
+		}
+	}
+	w.off += copy(w.data[w.off:], p)
+
+	err := w.err
+	w.err = nil
+	return len(p), err
+}
+
+// sync commits written records to the file. Must be called with w.mapMu held exclusively
+// and w.mu held.
+func (w *Writer) sync() error {
+	if w.synced == w.off {
+		return nil
+	}
+	start := w.synced / os.Getpagesize() * os.Getpagesize() // page aligned
+	if err := msync(w.data[start:w.off]); err != nil {
+		return err
+	}
+	w.synced = w.off
+	return nil
+}
+
+// commit is like sync but holds w.mu only to capture & update committed range, so
+// Writes do not wait for msync
+func (w *Writer) commit() error {
+	w.mapMu.RLock()
+	defer w.mapMu.RUnlock()
+
+	w.mu.Lock()
+	if w.data == nil || w.synced == w.off {
+		w.mu.Unlock()
+		return nil
+	}
+	start := w.synced / os.Getpagesize() * os.Getpagesize() // page aligned
+	end, data := w.off, w.data
+	w.mu.Unlock()
+
+	if err := msync(data[start:end]); err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	if end > w.synced {
+		w.synced = end
+	}
+	w.mu.Unlock()
+	return nil
+}
+
+// syncLoop commits records every interval until Close
+func (w *Writer) syncLoop(interval time.Duration, stop chan struct{}) {
+	defer close(w.done)
+	tk := time.NewTicker(interval)
+	defer tk.Stop()
+
+	for {
+		select {
+		case <-tk.C:
+			if err := w.commit(); err != nil {
+				w.mu.Lock()
+				w.err = err
+				w.mu.Unlock()
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Sync commits written records to the file, and returns its error or else the error of
+// the last background commit
+func (w *Writer) Sync() error {
+	w.mu.Lock()
+	closed := w.closed
+	w.mu.Unlock()
+	if closed {
+		return ErrClosed
+	}
+
+	err := w.commit()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err == nil {
+		err = w.err
+	}
+	w.err = nil
+	return err
+}
+
+// Close commits written records, unmaps & truncates the file to its records and closes
+// it. Later Writes return ErrClosed.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	stop := w.stop
+	w.stop = nil
+	w.mu.Unlock()
+	if stop != nil {
+		close(stop)
+		<-w.done
+	}
+
+	w.mapMu.Lock()
+	defer w.mapMu.Unlock()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	var err error
+	if w.data != nil {
+		err = w.sync()
+		if e := munmap(w.data); err == nil {
+			err = e
+		}
+		w.data = nil
+	}
+	if e := w.f.Truncate(int64(w.off)); err == nil {
+		err = e
+	}
+	if e := w.f.Close(); err == nil {
+		err = e
+	}
+	return err
+}
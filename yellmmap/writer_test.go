@@ -0,0 +1,101 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yellmmap
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jfcg/yell/v2"
+)
+
+func TestWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	w, err := Open(path, 1, 5*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lg := yell.New(": mm:", w, yell.Sinfo)
+
+	// exceed a page to grow the mapping
+	page := os.Getpagesize()
+	long := strings.Repeat("x", page/2)
+	for i := 0; i < 5; i++ {
+		lg.Log(yell.Sinfo, "record", i, long)
+	}
+	if fi, _ := os.Stat(path); fi.Size() < int64(2*page) || fi.Size()%int64(page) != 0 {
+		t.Fatal("mapping did not grow", fi.Size())
+	}
+	time.Sleep(20 * time.Millisecond) // background msync
+	if err = lg.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if err = lg.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = w.Write([]byte("x\n")); err != ErrClosed {
+		t.Fatal("write after close", err)
+	}
+
+	data, _ := ioutil.ReadFile(path)
+	if strings.Count(string(data), "\n") != 5 || !strings.HasSuffix(string(data), long+"\n") {
+		t.Fatal("bad file", len(data))
+	}
+
+	// append to existing file with zeros of an unclosed mapping
+	f, _ := os.OpenFile(path, os.O_RDWR, 0)
+	f.Truncate(int64(len(data) + 100))
+	f.Close()
+	if w, err = Open(path, 4096, 0); err != nil {
+		t.Fatal(err)
+	}
+	w.err = errors.New("background")
+	if n, err := w.Write([]byte("appended\n")); n != 9 || err == nil {
+		t.Fatal("background error must be reported after copying", n, err)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data2, _ := ioutil.ReadFile(path)
+	if string(data2) != string(data)+"appended\n" {
+		t.Fatal("bad append", len(data2))
+	}
+
+	// failing to extend makes writer fail, Close still truncates & closes file
+	if w, err = Open(path, 4096, 0); err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte("kept\n"))
+	rw := w.f
+	if w.f, err = os.Open(path); err != nil { // read-only, cannot be extended
+		t.Fatal(err)
+	}
+	if _, err = w.Write([]byte(strings.Repeat("y", 5000) + "\n")); err == nil || w.data != nil {
+		t.Fatal("extending read-only file must fail")
+	}
+	w.f.Close()
+	w.f = rw
+	if _, err = w.Write([]byte("lost\n")); err != w.fail {
+		t.Fatal("writer must keep failing", err)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data3, _ := ioutil.ReadFile(path)
+	if string(data3) != string(data2)+"kept\n" {
+		t.Fatal("file must be truncated to records", len(data3))
+	}
+
+	if _, err = Open(path, 0, 0); err == nil {
+		t.Fatal("zero size accepted")
+	}
+}
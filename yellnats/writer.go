@@ -0,0 +1,83 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+// Package yellnats publishes yell records to NATS subjects keyed by logger name, so
+// event-driven systems can fan logs out to multiple consumers. It is a separate module,
+// so yell itself does not depend on NATS.
+//
+//	nc, err := nats.Connect(nats.DefaultURL)
+//	lg := yell.New(": mypkg:", yellnats.New(nc, "logs."), yell.Sinfo)
+//
+// publishes records of lg to subject logs.mypkg, consumers can subscribe to logs.> for
+// all loggers.
+package yellnats
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/jfcg/yell/v2/yellparse"
+	"github.com/nats-io/nats.go"
+)
+
+// Publisher publishes messages to subjects, like *nats.Conn
+type Publisher interface {
+	Publish(subject string, data []byte) error
+}
+
+var _ Publisher = (*nats.Conn)(nil)
+
+// Writer publishes each record to subject prefix+name where name is logger name of the
+// record (see yellparse.Parse) with characters invalid in a subject token replaced by _.
+// Records that cannot be parsed go to prefix+"_". Each Write must be a complete record,
+// as Logger does. It is safe for concurrent use if its Publisher is.
+type Writer struct {
+	pub    Publisher
+	prefix string
+}
+
+// New creates a Writer that publishes via pub to subjects starting with prefix, which
+// normally ends with a dot like "logs."
+func New(pub Publisher, prefix string) *Writer {
+	if pub == nil {
+		panic("yellnats: nil Publisher")
+	}
+	return &Writer{pub, prefix}
+}
+
+// Subject returns subject of record p
+func (w *Writer) Subject(p []byte) string {
+	line := p
+	if i := bytes.IndexByte(line, '\n'); i >= 0 {
+		line = line[:i]
+	}
+	name := "_"
+	if r, ok := yellparse.Parse(string(line)); ok && r.Name != "" {
+		name = strings.Map(func(c rune) rune {
+			if c <= ' ' || c == '.' || c == '*' || c == '>' || c == 0x7f {
+				return '_'
+			}
+			return c
+		}, r.Name)
+	}
+	return w.prefix + name
+}
+
+// Write publishes record p to its subject
+func (w *Writer) Write(p []byte) (int, error) {
+	if err := w.pub.Publish(w.Subject(p), p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush flushes Publisher if it implements Flush() error, like *nats.Conn
+func (w *Writer) Flush() error {
+	if f, ok := w.pub.(interface{ Flush() error }); ok {
+		return f.Flush()
+	}
+	return nil
+}
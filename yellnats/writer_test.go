@@ -0,0 +1,63 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yellnats
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jfcg/yell/v2"
+)
+
+// fakeConn records published messages
+type fakeConn struct {
+	subjects []string
+	data     []string
+	flushed  bool
+	fail     bool
+}
+
+func (c *fakeConn) Publish(subject string, data []byte) error {
+	if c.fail {
+		return errors.New("disconnected")
+	}
+	c.subjects = append(c.subjects, subject)
+	c.data = append(c.data, string(data))
+	return nil
+}
+
+func (c *fakeConn) Flush() error {
+	c.flushed = true
+	return nil
+}
+
+func TestWriter(t *testing.T) {
+	nc := &fakeConn{}
+	w := New(nc, "logs.")
+	lg := yell.New(": my pkg.v2:", w, yell.Sinfo)
+	lg.Log(yell.Swarn, "published")
+
+	lg2 := yell.New(": other:", w, yell.Sinfo)
+	lg2.SetFormat(yell.Fjson)
+	lg2.Log(yell.Sinfo, "json")
+	w.Write([]byte("not a record\n"))
+
+	if len(nc.subjects) != 3 || nc.subjects[0] != "logs.my_pkg_v2" ||
+		nc.subjects[1] != "logs.other" || nc.subjects[2] != "logs._" ||
+		!strings.HasSuffix(nc.data[0], "published\n") {
+		t.Fatal("bad messages", nc.subjects, nc.data)
+	}
+
+	if lg.Flush() != nil || !nc.flushed {
+		t.Fatal("flush failed")
+	}
+	nc.fail = true
+	if _, err := w.Write([]byte("x\n")); err == nil {
+		t.Fatal("expected publish error")
+	}
+}
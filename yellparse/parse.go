@@ -0,0 +1,247 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+// Package yellparse turns yell records (text or JSON) back into structured records for
+// test assertions & downstream tooling.
+package yellparse
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jfcg/yell/v2"
+)
+
+// Record is a parsed log record
+type Record struct {
+	Time    string        // timestamp as written, empty if omitted
+	Name    string        // logger name
+	Level   yell.Severity // severity
+	Caller  string        // request location (file.go:line), empty if missing
+	Message string        // message list without trailing newline
+	Detail  string        // tab-indented continuation lines without tabs, like error details
+}
+
+// Timestamp parses Time of record as RFC 3339, yell.TimeFormat (local time), Unix seconds
+// or milliseconds. Returns false if Time cannot be parsed.
+func (r *Record) Timestamp() (time.Time, bool) {
+	return ParseTime(r.Time)
+}
+
+// ParseTime parses s as RFC 3339, yell.TimeFormat (local time), Unix seconds or
+// milliseconds (more than 11 digits). Returns false if s cannot be parsed.
+func ParseTime(s string) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, true
+	}
+	if t, err := time.ParseInLocation(yell.TimeFormat, s, time.Local); err == nil {
+		return t, true
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if len(s) > 11 {
+			return time.Unix(0, n*1e6), true // milliseconds
+		}
+		return time.Unix(n, 0), true
+	}
+	return time.Time{}, false
+}
+
+// Parse parses a single text or JSON record without trailing newline. ANSI colors in
+// text records are ignored. Parse uses current yell.Sname to identify severities.
+func Parse(line string) (rec Record, ok bool) {
+	if strings.HasPrefix(line, "{") {
+		return parseJSON(line)
+	}
+	if rec, ok = parseText(stripColors(line)); !ok {
+		rec = Record{}
+	}
+	return
+}
+
+func parseText(line string) (rec Record, ok bool) {
+
+	// earliest ":severity" after name
+	k, lv := -1, yell.Severity(0)
+	for l, sn := range yell.Sname {
+		if j := strings.Index(line, ":"+sn); j > 0 && (k < 0 || j < k) {
+			k, lv = j, yell.Severity(l)
+		}
+	}
+	if k < 0 {
+		return
+	}
+
+	// name starts after time & ": ", time is omitted with yell.Tnone
+	if i := strings.LastIndex(line[:k], ": "); i >= 0 {
+		rec.Time, line, k = line[:i], line[i+2:], k-i-2
+	}
+	rec.Name, rec.Level = line[:k], lv
+	rest := line[k+1+len(yell.Sname[lv]):]
+
+	if rest == "" {
+		return rec, true
+	}
+	if rest[0] != ' ' {
+		return
+	}
+	rest = rest[1:]
+
+	// optional caller
+	tok := rest
+	if j := strings.IndexByte(rest, ' '); j >= 0 {
+		tok = rest[:j]
+	}
+	if isCaller(tok) {
+		rec.Caller = tok[:len(tok)-1]
+		rest = rest[len(tok):]
+		if rest != "" {
+			rest = rest[1:]
+		}
+	}
+	rec.Message = rest
+	return rec, true
+}
+
+// isCaller checks if tok is of the form file:line:
+func isCaller(tok string) bool {
+	l := len(tok) - 1
+	if l < 2 || tok[l] != ':' {
+		return false
+	}
+	i := l - 1
+	for ; i > 0 && '0' <= tok[i] && tok[i] <= '9'; i-- {
+	}
+	return i > 0 && i < l-1 && tok[i] == ':'
+}
+
+// stripColors removes ANSI color sequences from s
+func stripColors(s string) string {
+	if strings.IndexByte(s, '\x1b') < 0 {
+		return s
+	}
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '[' {
+			j := i + 2
+			for j < len(s) && (s[j] == ';' || '0' <= s[j] && s[j] <= '9') {
+				j++
+			}
+			if j < len(s) && s[j] == 'm' {
+				i = j
+				continue
+			}
+		}
+		sb.WriteByte(s[i])
+	}
+	return sb.String()
+}
+
+func parseJSON(line string) (rec Record, ok bool) {
+	type jsonError struct{ Msg string }
+	var m struct {
+		Name, Level, Caller, Msg, Detail string
+
+		Time   json.RawMessage // string or epoch number
+		Error  *jsonError
+		Errors []jsonError
+	}
+	if json.Unmarshal([]byte(line), &m) != nil {
+		return
+	}
+
+	// errors follow message like in text records
+	if m.Error != nil {
+		m.Errors = append(m.Errors, *m.Error)
+	}
+	for _, e := range m.Errors {
+		if m.Msg != "" {
+			m.Msg += " "
+		}
+		m.Msg += e.Msg
+	}
+
+	for l, sn := range yell.Sname {
+		if strings.TrimSuffix(sn, ":") == m.Level {
+			time := strings.Trim(string(m.Time), `"`)
+			return Record{time, m.Name, yell.Severity(l), m.Caller, m.Msg, m.Detail}, true
+		}
+	}
+	return
+}
+
+// Scanner reads records from a log, attaching continuation lines to their records as
+// Detail. Lines that are not records are skipped.
+type Scanner struct {
+	sc   *bufio.Scanner
+	rec  Record
+	next *Record // record read ahead
+	line string  // raw line of rec
+	raw  string  // raw line of next
+}
+
+// NewScanner creates a Scanner that reads records from r
+func NewScanner(r io.Reader) *Scanner {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64<<10), 16<<20)
+	return &Scanner{sc: sc}
+}
+
+// Scan advances to the next record, which is then available with Record. Returns false
+// at the end of input or on a read error, see Err.
+func (s *Scanner) Scan() bool {
+	if s.next == nil && !s.readRecord() {
+		return false
+	}
+	s.rec, s.line, s.next = *s.next, s.raw, nil
+
+	// collect continuation lines
+	for s.sc.Scan() {
+		line := s.sc.Text()
+		if strings.HasPrefix(line, "\t") {
+			if s.rec.Detail != "" {
+				s.rec.Detail += "\n"
+			}
+			s.rec.Detail += line[1:]
+			continue
+		}
+		if rec, ok := Parse(line); ok {
+			s.next, s.raw = &rec, line
+			break
+		}
+	}
+	return true
+}
+
+// readRecord reads lines until a record
+func (s *Scanner) readRecord() bool {
+	for s.sc.Scan() {
+		line := s.sc.Text()
+		if rec, ok := Parse(line); ok {
+			s.next, s.raw = &rec, line
+			return true
+		}
+	}
+	return false
+}
+
+// Record returns the current record
+func (s *Scanner) Record() Record {
+	return s.rec
+}
+
+// Line returns the first line of the current record as written
+func (s *Scanner) Line() string {
+	return s.line
+}
+
+// Err returns the first read error
+func (s *Scanner) Err() error {
+	return s.sc.Err()
+}
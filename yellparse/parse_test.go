@@ -0,0 +1,74 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yellparse
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jfcg/yell/v2"
+)
+
+func TestParse(t *testing.T) {
+	tests := [...]struct {
+		line string
+		ok   bool
+		rec  Record
+	}{
+		{"", false, Record{}},
+		{"t: n:warn:", true, Record{"t", "n", yell.Swarn, "", "", ""}},
+		{"t: n:fatal: x", true, Record{"t", "n", yell.Sfatal, "", "x", ""}},
+		{"t: n:info: f.go:: x", true, Record{"t", "n", yell.Sinfo, "", "f.go:: x", ""}},
+		{"t: n:info: f.go:9:", true, Record{"t", "n", yell.Sinfo, "f.go:9", "", ""}},
+		{"t: n:infox", false, Record{}},
+		{"n:info: x", true, Record{"", "n", yell.Sinfo, "", "x", ""}},
+		{`{"time":12,"name":"n","level":"warn"}`, true, Record{"12", "n", yell.Swarn, "", "", ""}},
+		{`{"level":"nope"}`, false, Record{}},
+	}
+	for _, tc := range tests {
+		r, ok := Parse(tc.line)
+		if ok != tc.ok || r != tc.rec {
+			t.Fatalf("Parse(%q) = %v %v", tc.line, r, ok)
+		}
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	want := time.Date(2021, 3, 28, 21, 48, 53, 0, time.UTC)
+	for _, s := range [...]string{"2021-03-28T21:48:53Z", "1616968133", "1616968133000"} {
+		if tm, ok := ParseTime(s); !ok || !tm.Equal(want) {
+			t.Fatal("must parse:", s, tm)
+		}
+	}
+	r := Record{Time: "2021-03-28 21:48:53.000000"}
+	if tm, ok := r.Timestamp(); !ok || !tm.Equal(time.Date(2021, 3, 28, 21, 48, 53, 0,
+		time.Local)) {
+		t.Fatal("must parse TimeFormat:", tm)
+	}
+	if _, ok := ParseTime("yesterday"); ok {
+		t.Fatal("must not parse")
+	}
+}
+
+func TestScanner(t *testing.T) {
+	const log = "junk\nt: a:info: x\n\td1\n\td2\nnot a record\nt: b:warn: y\n" +
+		`{"name":"c","level":"error","msg":"z","detail":"d3"}` + "\n"
+	sc := NewScanner(strings.NewReader(log))
+
+	var recs []Record
+	var lines []string
+	for sc.Scan() {
+		recs = append(recs, sc.Record())
+		lines = append(lines, sc.Line())
+	}
+	if sc.Err() != nil || len(recs) != 3 || recs[0].Detail != "d1\nd2" ||
+		recs[1].Name != "b" || recs[1].Detail != "" || recs[2].Detail != "d3" ||
+		lines[0] != "t: a:info: x" || lines[1] != "t: b:warn: y" {
+		t.Fatal("unexpected records:", recs, lines)
+	}
+}
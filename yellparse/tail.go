@@ -0,0 +1,168 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yellparse
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Tailer follows a growing log file like tail -F and streams its records, for example
+// to live-debug dashboards. Rotation (file at Path replaced) and truncation are detected
+// by polling, records of a rotated file are read to its end before switching.
+type Tailer struct {
+	Path      string             // log file to follow, may not exist yet
+	Poll      time.Duration      // polling interval, default 250ms
+	FromStart bool               // read existing records too, otherwise only new ones
+	Filter    func(*Record) bool // optional, only records it accepts are streamed
+}
+
+// tail state
+type tail struct {
+	*Tailer
+	fn      func(Record)
+	file    *os.File
+	rd      *bufio.Reader
+	offset  int64  // read offset in file
+	partial string // incomplete last line
+	pending *Record
+}
+
+// Run follows the log file and calls fn with each record until ctx is done, then returns
+// ctx.Err(). fn is called from Run's goroutine. Returns early only if the log file
+// cannot be read.
+func (t *Tailer) Run(ctx context.Context, fn func(Record)) error {
+	poll := t.Poll
+	if poll <= 0 {
+		poll = 250 * time.Millisecond
+	}
+	tl := &tail{Tailer: t, fn: fn}
+	defer tl.close()
+	atStart := t.FromStart
+
+	for {
+		if tl.file == nil {
+			if err := tl.open(atStart); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			atStart = true // later files are read from start
+		}
+		if tl.file != nil {
+			if err := tl.read(); err != nil {
+				return err
+			}
+			if err := tl.check(); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(poll):
+		}
+	}
+}
+
+// open the log file, seeking to its end unless atStart
+func (tl *tail) open(atStart bool) (err error) {
+	if tl.file, err = os.Open(tl.Path); err != nil {
+		return
+	}
+	tl.offset = 0
+	if !atStart {
+		if tl.offset, err = tl.file.Seek(0, io.SeekEnd); err != nil {
+			tl.close()
+			return
+		}
+	}
+	tl.rd = bufio.NewReader(tl.file)
+	return
+}
+
+// close the log file
+func (tl *tail) close() {
+	if tl.file != nil {
+		tl.file.Close()
+		tl.file, tl.partial = nil, ""
+	}
+}
+
+// read lines until end of file, then emit pending record
+func (tl *tail) read() error {
+	for {
+		line, err := tl.rd.ReadString('\n')
+		tl.offset += int64(len(line))
+		if err == io.EOF {
+			tl.partial += line
+			break
+		}
+		if err != nil {
+			return err
+		}
+		line = tl.partial + line[:len(line)-1]
+		tl.partial = ""
+
+		if strings.HasPrefix(line, "\t") {
+			if p := tl.pending; p != nil {
+				if p.Detail != "" {
+					p.Detail += "\n"
+				}
+				p.Detail += line[1:]
+			}
+			continue
+		}
+		if rec, ok := Parse(line); ok {
+			tl.emit()
+			tl.pending = &rec
+		}
+	}
+	tl.emit() // records are written at once with their details
+	return nil
+}
+
+// emit pending record
+func (tl *tail) emit() {
+	if p := tl.pending; p != nil {
+		tl.pending = nil
+		if tl.Filter == nil || tl.Filter(p) {
+			tl.fn(*p)
+		}
+	}
+}
+
+// check for rotation & truncation
+func (tl *tail) check() error {
+	cur, err := tl.file.Stat()
+	if err != nil {
+		return err
+	}
+	fi, err := os.Stat(tl.Path)
+	switch {
+	case os.IsNotExist(err):
+		return nil // rotated, new file not yet created
+	case err != nil:
+		return err
+	case !os.SameFile(cur, fi):
+		// rotated, finish old file & reopen
+		err = tl.read()
+		tl.close()
+		return err
+	case fi.Size() < tl.offset:
+		// truncated, read from start
+		if _, err = tl.file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		tl.offset, tl.partial = 0, ""
+		tl.rd.Reset(tl.file)
+	}
+	return nil
+}
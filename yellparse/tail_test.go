@@ -0,0 +1,88 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yellparse
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jfcg/yell/v2"
+)
+
+func TestTailer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "yelltail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "app.log")
+
+	appendLog := func(s string) {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.WriteString(s)
+		f.Close()
+	}
+	appendLog("t: a:info: old\n")
+
+	recs := make(chan Record, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	tl := &Tailer{Path: path, Poll: time.Millisecond,
+		Filter: func(r *Record) bool { return r.Level >= yell.Sinfo }}
+	go func() { done <- tl.Run(ctx, func(r Record) { recs <- r }) }()
+
+	expect := func(msg, detail string) {
+		select {
+		case r := <-recs:
+			if r.Message != msg || r.Detail != detail {
+				t.Fatal("unexpected record:", r, msg)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("missing record:", msg)
+		}
+	}
+
+	time.Sleep(50 * time.Millisecond) // let tailer seek to end
+	appendLog("t: a:debug: filtered\nt: a:warn: one\n\td1\n")
+	expect("one", "d1")
+	appendLog("t: a:info: partial")
+	time.Sleep(20 * time.Millisecond)
+	appendLog(" line\n")
+	expect("partial line", "")
+
+	// rotation
+	if err = os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	appendLog("t: a:info: rotated\n")
+	expect("rotated", "")
+
+	// truncation
+	if err = os.Truncate(path, 0); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	appendLog("t: a:error: truncated\n")
+	expect("truncated", "")
+
+	cancel()
+	if err = <-done; err != context.Canceled {
+		t.Fatal("unexpected error:", err)
+	}
+	select {
+	case r := <-recs:
+		t.Fatal("unexpected record:", r)
+	default:
+	}
+}
@@ -0,0 +1,90 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+// Package yellredis appends yell records to Redis streams keyed by logger name, so
+// event-driven systems can fan logs out to multiple consumer groups. It is a separate
+// module, so yell itself does not depend on Redis.
+//
+//	rdb := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+//	lg := yell.New(": mypkg:", yellredis.New(rdb, "logs:", 10000), yell.Sinfo)
+//
+// appends records of lg to stream logs:mypkg, keeping about 10000 latest entries.
+package yellredis
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/jfcg/yell/v2/yellparse"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultTimeout limits each XADD command
+const DefaultTimeout = 5 * time.Second
+
+// Writer adds each record as an entry to stream prefix+name where name is logger name of
+// the record (see yellparse.Parse). Records that cannot be parsed go to prefix+"_".
+// Entries have name, level (severity name) & record (as written) fields. Each Write must
+// be a complete record, as Logger does. It is safe for concurrent use.
+type Writer struct {
+	rdb    redis.Cmdable
+	prefix string
+	maxLen int64
+
+	// Timeout limits each XADD command, zero means DefaultTimeout
+	Timeout time.Duration
+}
+
+// New creates a Writer that adds entries via rdb to streams starting with prefix, like
+// "logs:". Streams are trimmed to approximately maxLen entries if positive.
+func New(rdb redis.Cmdable, prefix string, maxLen int64) *Writer {
+	if rdb == nil {
+		panic("yellredis: nil client")
+	}
+	return &Writer{rdb: rdb, prefix: prefix, maxLen: maxLen}
+}
+
+// Stream returns stream key of record p
+func (w *Writer) Stream(p []byte) string {
+	key, _, _ := w.parse(p)
+	return key
+}
+
+// parse returns stream key, logger name & severity name of record p
+func (w *Writer) parse(p []byte) (key, name, level string) {
+	line := p
+	if i := bytes.IndexByte(line, '\n'); i >= 0 {
+		line = line[:i]
+	}
+	if r, ok := yellparse.Parse(string(line)); ok && r.Name != "" {
+		return w.prefix + r.Name, r.Name, r.Level.String()
+	}
+	return w.prefix + "_", "", ""
+}
+
+// Write adds record p to its stream
+func (w *Writer) Write(p []byte) (int, error) {
+	key, name, level := w.parse(p)
+
+	timeout := w.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	err := w.rdb.XAdd(ctx, &redis.XAddArgs{
+		Stream: key,
+		MaxLen: w.maxLen,
+		Approx: w.maxLen > 0,
+		Values: []interface{}{"name", name, "level", level, "record", string(p)},
+	}).Err()
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
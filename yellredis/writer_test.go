@@ -0,0 +1,48 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yellredis
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/jfcg/yell/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestWriter(t *testing.T) {
+	srv := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	defer rdb.Close()
+
+	w := New(rdb, "logs:", 0)
+	lg := yell.New(": mypkg:", w, yell.Sinfo)
+	lg.Log(yell.Swarn, "first")
+	lg.Log(yell.Sinfo, "second")
+	w.Write([]byte("not a record\n"))
+
+	ctx := context.Background()
+	entries, err := rdb.XRange(ctx, "logs:mypkg", "-", "+").Result()
+	if err != nil || len(entries) != 2 {
+		t.Fatal("bad stream", err, entries)
+	}
+	v := entries[0].Values
+	if v["name"] != "mypkg" || v["level"] != "warn" ||
+		!strings.HasSuffix(v["record"].(string), "first\n") {
+		t.Fatal("bad entry", v)
+	}
+	if n, err := rdb.XLen(ctx, "logs:_").Result(); err != nil || n != 1 {
+		t.Fatal("unparsed record lost", n, err)
+	}
+
+	srv.Close()
+	if _, err = w.Write([]byte("x\n")); err == nil {
+		t.Fatal("expected XADD error")
+	}
+}
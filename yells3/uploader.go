@@ -0,0 +1,50 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+// Package yells3 provides a yellarchive.Uploader for Amazon S3 and S3 compatible object
+// storage, like Google Cloud Storage with HMAC keys & https://storage.googleapis.com as
+// endpoint. It is a separate module, so yell itself does not depend on the AWS SDK.
+//
+//	up := &yells3.Uploader{Client: s3.NewFromConfig(cfg), Bucket: "my-logs"}
+//	arc := yellarchive.New(up, "myapp/", time.Hour)
+//	lg := yell.New(": mypkg:", arc, yell.Sinfo)
+//	defer lg.Close()
+package yells3
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// API is the subset of *s3.Client used by Uploader
+type API interface {
+	PutObject(ctx context.Context, in *s3.PutObjectInput,
+		opts ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+}
+
+// Uploader puts objects to Bucket via Client
+type Uploader struct {
+	Client       API
+	Bucket       string
+	StorageClass types.StorageClass // optional, like types.StorageClassGlacierIr
+}
+
+// Upload puts body as object with key name & gzip content type
+func (u *Uploader) Upload(ctx context.Context, name string, body []byte) error {
+	_, err := u.Client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(u.Bucket),
+		Key:           aws.String(name),
+		Body:          bytes.NewReader(body),
+		ContentLength: aws.Int64(int64(len(body))),
+		ContentType:   aws.String("application/gzip"),
+		StorageClass:  u.StorageClass,
+	})
+	return err
+}
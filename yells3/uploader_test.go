@@ -0,0 +1,54 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yells3
+
+import (
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/jfcg/yell/v2"
+	"github.com/jfcg/yell/v2/yellarchive"
+)
+
+// fakeS3 records put objects
+type fakeS3 struct {
+	inputs []*s3.PutObjectInput
+	bodies [][]byte
+}
+
+func (f *fakeS3) PutObject(_ context.Context, in *s3.PutObjectInput,
+	_ ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+
+	body, err := ioutil.ReadAll(in.Body)
+	if err != nil {
+		return nil, err
+	}
+	f.inputs = append(f.inputs, in)
+	f.bodies = append(f.bodies, body)
+	return &s3.PutObjectOutput{}, nil
+}
+
+func TestUploader(t *testing.T) {
+	api := &fakeS3{}
+	arc := yellarchive.New(&Uploader{Client: api, Bucket: "logs",
+		StorageClass: "STANDARD_IA"}, "app/", 0)
+	lg := yell.New(": s3:", arc, yell.Sinfo)
+	lg.Log(yell.Sinfo, "archived")
+
+	if err := lg.Close(); err != nil || len(api.inputs) != 1 {
+		t.Fatal("upload failed", err)
+	}
+	in := api.inputs[0]
+	if *in.Bucket != "logs" || !strings.HasPrefix(*in.Key, "app/") ||
+		*in.ContentType != "application/gzip" || in.StorageClass != "STANDARD_IA" ||
+		*in.ContentLength != int64(len(api.bodies[0])) || api.bodies[0][0] != 0x1f {
+		t.Fatal("bad object", *in.Key)
+	}
+}
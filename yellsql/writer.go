@@ -0,0 +1,350 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+// Package yellsql stores yell records in a database/sql table, so small deployments can
+// query logs with SQL instead of running a log stack:
+//
+//	db, _ := sql.Open("sqlite3", "logs.db") // any database/sql driver
+//	yellsql.CreateTable(db, "logs")
+//	w, _ := yellsql.NewWriter(db, "logs", time.Second)
+//	lg := yell.New(": mypkg:", w, yell.Sinfo)
+//	defer lg.Close()
+//
+// Table has time, level, name, caller, message & detail columns, see CreateTable.
+package yellsql
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jfcg/yell/v2/yellparse"
+)
+
+// ErrClosed is returned by Write after Close
+var ErrClosed = errors.New("yellsql: writer is closed")
+
+// ErrTable is returned for invalid table names
+var ErrTable = errors.New("yellsql: invalid table name")
+
+// defaults of Writer
+const (
+	DefaultBatch   = 100   // records inserted per transaction
+	DefaultMaxRows = 10000 // buffered records while inserts fail
+)
+
+// validTable checks if table is a possibly schema qualified identifier, so it is safe to
+// use in statements
+func validTable(table string) bool {
+	if table == "" {
+		return false
+	}
+	for _, part := range strings.Split(table, ".") {
+		if part == "" || '0' <= part[0] && part[0] <= '9' {
+			return false
+		}
+		for i := 0; i < len(part); i++ {
+			c := part[i] | 0x20 // lower case
+			if !('a' <= c && c <= 'z' || '0' <= part[i] && part[i] <= '9' || part[i] == '_') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// CreateTable creates table for records if it does not exist, with columns:
+//
+//	time TIMESTAMP, level INTEGER, name VARCHAR(255), caller VARCHAR(255),
+//	message TEXT, detail TEXT
+//
+// level is the number of yell.Severity. Databases with different dialects (like
+// ClickHouse, which needs a table engine) should create an equivalent table themselves.
+func CreateTable(db *sql.DB, table string) error {
+	if !validTable(table) {
+		return ErrTable
+	}
+	_, err := db.Exec("CREATE TABLE IF NOT EXISTS " + table + ` (time TIMESTAMP,
+		level INTEGER, name VARCHAR(255), caller VARCHAR(255), message TEXT, detail TEXT)`)
+	return err
+}
+
+// row is a parsed record
+type row struct {
+	time   time.Time
+	level  int
+	name   string
+	caller string
+	msg    string
+	detail string
+}
+
+// Writer parses text or JSON records (see yellparse.Parse) and inserts them into a table
+// in batched transactions of at most BatchSize records. A batch is inserted when it has
+// BatchSize records, every flush interval and on Flush or Close. Records without a
+// parsable time get the time of Write. Records are kept for the next try while inserts
+// fail, up to MaxRows (the oldest are dropped). Errors of inserts are returned by the next
+// Flush or Close. Each Write must be a complete record, as Logger does. It is safe for
+// concurrent use.
+type Writer struct {
+	mu       sync.Mutex
+	sendMu   sync.Mutex // serializes inserts, which run without mu
+	db       *sql.DB
+	table    string
+	interval time.Duration
+	timer    *time.Timer
+	rows     []row   // current batch
+	pending  [][]row // batches waiting to be inserted
+	sending  bool    // pending[0] is being inserted
+	err      error   // error of last insert
+	closed   bool
+
+	// BatchSize is number of records per transaction, zero means DefaultBatch
+	BatchSize int
+	// MaxRows limits buffered records while inserts fail, zero means DefaultMaxRows
+	MaxRows int
+	// Dollar selects $1, $2.. placeholders (as PostgreSQL needs) instead of ?
+	Dollar bool
+}
+
+// NewWriter creates a Writer for table of db. Buffered records are inserted every interval
+// if positive, otherwise only when BatchSize is reached or on Flush & Close.
+func NewWriter(db *sql.DB, table string, interval time.Duration) (*Writer, error) {
+	if db == nil {
+		return nil, errors.New("yellsql: nil db")
+	}
+	if !validTable(table) {
+		return nil, ErrTable
+	}
+	return &Writer{db: db, table: table, interval: interval}, nil
+}
+
+// Write parses records in p and buffers them, inserting full batches. Lines that are not
+// yell records are ignored.
+func (w *Writer) Write(p []byte) (int, error) {
+	now := time.Now()
+	var rows []row
+	for sc := yellparse.NewScanner(bytes.NewReader(p)); sc.Scan(); {
+		r := sc.Record()
+		t, ok := r.Timestamp()
+		if !ok {
+			t = now
+		}
+		rows = append(rows, row{t, int(r.Level), r.Name, r.Caller, r.Message, r.Detail})
+	}
+
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return 0, ErrClosed
+	}
+
+	w.rows = append(w.rows, rows...)
+	full := len(w.rows) >= w.batch()
+	if full {
+		w.seal()
+	} else if w.timer == nil && w.interval > 0 && len(w.rows) > 0 {
+		w.timer = time.AfterFunc(w.interval, w.tick)
+	}
+	w.trim()
+	w.mu.Unlock()
+
+	if full {
+		w.keep(w.send())
+	}
+	return len(p), nil
+}
+
+// tick inserts buffered records in the background
+func (w *Writer) tick() {
+	w.mu.Lock()
+	w.timer = nil
+	w.seal()
+	w.mu.Unlock()
+
+	w.keep(w.send())
+}
+
+// batch returns number of records per transaction
+func (w *Writer) batch() int {
+	if w.BatchSize > 0 {
+		return w.BatchSize
+	}
+	return DefaultBatch
+}
+
+// seal splits current batch into pending batches of at most BatchSize records. Must be
+// called with w.mu held.
+func (w *Writer) seal() {
+	batch := w.batch()
+	for len(w.rows) > 0 {
+		n := len(w.rows)
+		if n > batch {
+			n = batch
+		}
+		w.pending = append(w.pending, w.rows[:n:n])
+		w.rows = w.rows[n:]
+	}
+	w.rows = nil
+}
+
+// trim drops oldest records beyond MaxRows, except the batch being inserted. Must be
+// called with w.mu held.
+func (w *Writer) trim() {
+	max := w.MaxRows
+	if max <= 0 {
+		max = DefaultMaxRows
+	}
+	n := len(w.rows) - max
+	for _, rows := range w.pending {
+		n += len(rows)
+	}
+
+	i := 0
+	if w.sending {
+		i = 1
+	}
+	for n > 0 && i < len(w.pending) {
+		n -= len(w.pending[i])
+		w.pending = append(w.pending[:i], w.pending[i+1:]...)
+	}
+	if n > len(w.rows) {
+		n = len(w.rows)
+	}
+	if n > 0 {
+		w.rows = append(w.rows[:0], w.rows[n:]...)
+	}
+}
+
+// keep stores err of an insert for the next Flush or Close
+func (w *Writer) keep(err error) {
+	if err != nil {
+		w.mu.Lock()
+		w.err = err
+		w.mu.Unlock()
+	}
+}
+
+// report returns err, or else the error of the last insert. The latter is cleared in both
+// cases.
+func (w *Writer) report(err error) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err == nil {
+		err = w.err
+	}
+	w.err = nil
+	return err
+}
+
+// send inserts pending batches in order. w.mu is not held during transactions, so Writes
+// do not wait for them.
+func (w *Writer) send() error {
+	w.sendMu.Lock()
+	defer w.sendMu.Unlock()
+
+	for {
+		w.mu.Lock()
+		if len(w.pending) == 0 {
+			w.pending = nil
+			w.mu.Unlock()
+			return nil
+		}
+		rows := w.pending[0]
+		w.sending = true
+		w.mu.Unlock()
+
+		err := w.insert(rows)
+
+		w.mu.Lock()
+		w.sending = false
+		if err != nil {
+			w.trim()
+			w.mu.Unlock()
+			return err
+		}
+		w.pending[0] = nil
+		w.pending = w.pending[1:]
+		w.mu.Unlock()
+	}
+}
+
+// statement returns insert statement of w
+func (w *Writer) statement() string {
+	s := "INSERT INTO " + w.table +
+		" (time, level, name, caller, message, detail) VALUES ("
+	for i := 1; i <= 6; i++ {
+		if i > 1 {
+			s += ", "
+		}
+		if w.Dollar {
+			s += "$" + strconv.Itoa(i)
+		} else {
+			s += "?"
+		}
+	}
+	return s + ")"
+}
+
+// insert inserts rows in a transaction. Must be called with w.sendMu held.
+func (w *Writer) insert(rows []row) (err error) {
+	tx, err := w.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	st, err := tx.Prepare(w.statement())
+	if err != nil {
+		return err
+	}
+	defer st.Close()
+
+	for _, r := range rows {
+		if _, err = st.Exec(r.time, r.level, r.name, r.caller, r.msg, r.detail); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Flush inserts buffered records, and returns their error or else the error of the last
+// background insert
+func (w *Writer) Flush() error {
+	w.mu.Lock()
+	w.seal()
+	w.mu.Unlock()
+
+	return w.report(w.send())
+}
+
+// Close inserts buffered records and stops the flush timer. Later Writes return ErrClosed.
+// It does not close the database.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+	w.seal()
+	w.mu.Unlock()
+
+	return w.report(w.send())
+}
@@ -0,0 +1,200 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yellsql
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jfcg/yell/v2"
+)
+
+// fake database that records executed statements & committed insert arguments
+var fake struct {
+	sync.Mutex
+	stmts []string
+	rows  [][]driver.Value
+	fail  bool
+}
+
+type fakeDriver struct{}
+type fakeConn struct{ pending [][]driver.Value }
+type fakeStmt struct {
+	c     *fakeConn
+	query string
+}
+
+func (fakeDriver) Open(string) (driver.Conn, error) { return &fakeConn{}, nil }
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	fake.Lock()
+	defer fake.Unlock()
+	fake.stmts = append(fake.stmts, query)
+	return &fakeStmt{c, query}, nil
+}
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return c, nil }
+
+func (c *fakeConn) Commit() error {
+	fake.Lock()
+	defer fake.Unlock()
+	if fake.fail {
+		c.pending = nil
+		return errors.New("commit failed")
+	}
+	fake.rows = append(fake.rows, c.pending...)
+	c.pending = nil
+	return nil
+}
+func (c *fakeConn) Rollback() error { c.pending = nil; return nil }
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if strings.HasPrefix(s.query, "INSERT") {
+		s.c.pending = append(s.c.pending, args)
+	}
+	return driver.RowsAffected(1), nil
+}
+func (s *fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	return nil, errors.New("not supported")
+}
+
+func init() {
+	sql.Register("yellfake", fakeDriver{})
+}
+
+func count() int {
+	fake.Lock()
+	defer fake.Unlock()
+	return len(fake.rows)
+}
+
+func TestCreateTable(t *testing.T) {
+	db, _ := sql.Open("yellfake", "")
+	defer db.Close()
+
+	for _, tb := range []string{"", "1logs", "logs;drop", "a..b", "lo gs"} {
+		if CreateTable(db, tb) != ErrTable {
+			t.Fatal("accepted invalid table", tb)
+		}
+		if _, err := NewWriter(db, tb, 0); err != ErrTable {
+			t.Fatal("accepted invalid table", tb)
+		}
+	}
+	if err := CreateTable(db, "app.Logs_1"); err != nil ||
+		!strings.HasPrefix(fake.stmts[len(fake.stmts)-1], "CREATE TABLE IF NOT EXISTS app.Logs_1 (") {
+		t.Fatal("CreateTable failed", err)
+	}
+}
+
+func TestWriter(t *testing.T) {
+	db, _ := sql.Open("yellfake", "")
+	defer db.Close()
+	fake.rows = nil
+
+	w, err := NewWriter(db, "logs", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.BatchSize = 3
+	w.Dollar = true
+	lg := yell.New(": sq:", w, yell.Sinfo)
+	lg.SetErrorDetail(true)
+
+	lg.Log(yell.Sinfo, "one")
+	lg.Log(yell.Swarn, "two")
+	if count() != 0 {
+		t.Fatal("inserted before batch is full")
+	}
+	lg.Log(yell.Serror, "three", errors.New("bad"))
+	if count() != 3 {
+		t.Fatal("batch not inserted", count())
+	}
+	if fake.stmts[len(fake.stmts)-1] != "INSERT INTO logs (time, level, name, caller, "+
+		"message, detail) VALUES ($1, $2, $3, $4, $5, $6)" {
+		t.Fatal("bad statement", fake.stmts[len(fake.stmts)-1])
+	}
+
+	r := fake.rows[2]
+	if tm, ok := r[0].(time.Time); !ok || time.Since(tm) > time.Minute ||
+		r[1] != int64(yell.Serror) || r[2] != "sq" || !strings.HasPrefix(r[3].(string), "testing.go:") ||
+		r[4] != "three bad" {
+		t.Fatal("bad row", r)
+	}
+
+	// failed transactions keep records
+	fake.Lock()
+	fake.fail = true
+	fake.Unlock()
+	lg.Log(yell.Sinfo, "four")
+	if w.Flush() == nil || count() != 3 {
+		t.Fatal("expected commit failure")
+	}
+	w.MaxRows = 2
+	bg := errors.New("background")
+	w.err = bg
+	lg.Log(yell.Sinfo, "five")
+	if err = lg.Log(yell.Sinfo, "six"); err != nil {
+		t.Fatal("accepted record must not fail:", err)
+	}
+	if len(w.pending) != 0 || len(w.rows) != 2 || w.rows[0].msg != "five" {
+		t.Fatal("oldest records must be dropped", w.pending, w.rows)
+	}
+	fake.Lock()
+	fake.fail = false
+	fake.Unlock()
+	if err = w.Close(); err != bg || count() != 5 {
+		t.Fatal("close must insert & report background error", err, count())
+	}
+	if _, err = w.Write([]byte("x\n")); err != ErrClosed {
+		t.Fatal("write after close", err)
+	}
+}
+
+func TestWriterBatches(t *testing.T) {
+	db, _ := sql.Open("yellfake", "")
+	defer db.Close()
+	fake.rows = nil
+
+	w, _ := NewWriter(db, "logs", 0)
+	w.BatchSize = 2
+	inserts := len(fake.stmts)
+	w.Write([]byte(strings.Repeat("2021-03-28 21:48:53.591948: sq:info: batch\n", 5)))
+	if count() != 5 || len(fake.stmts)-inserts != 3 {
+		t.Fatal("records must be inserted in batches", count(), len(fake.stmts)-inserts)
+	}
+	w.Close()
+}
+
+func TestWriterInterval(t *testing.T) {
+	db, _ := sql.Open("yellfake", "")
+	defer db.Close()
+	fake.rows = nil
+
+	w, _ := NewWriter(db, "logs", 10*time.Millisecond)
+	w.Write([]byte("2021-03-28 21:48:53.591948: sq:info: tick\n"))
+
+	for i := 0; count() == 0; i++ {
+		if i > 200 {
+			t.Fatal("interval insert did not happen")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	fake.Lock()
+	tm := fake.rows[0][0].(time.Time)
+	fake.Unlock()
+	if tm.Year() != 2021 || tm.Nanosecond() != 591948000 {
+		t.Fatal("bad time", tm)
+	}
+	w.Close()
+}
@@ -0,0 +1,153 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+// Package yellsqlite persists yell records in a local SQLite file with a simple query
+// API, for desktop & embedded apps that would otherwise keep ad-hoc flat log files. It
+// uses a pure Go SQLite driver, so it needs no cgo. It is a separate module, so yell
+// itself does not depend on SQLite.
+//
+//	st, err := yellsqlite.Open("app-logs.db", time.Second)
+//	lg := yell.New(": mypkg:", st, yell.Sinfo)
+//	defer lg.Close()
+//	recent, err := st.Query(yellsqlite.Query{From: time.Now().Add(-time.Hour),
+//		MinLevel: yell.Swarn})
+package yellsqlite
+
+import (
+	"database/sql"
+	"net/url"
+	"time"
+
+	"github.com/jfcg/yell/v2"
+	"github.com/jfcg/yell/v2/yellsql"
+	_ "modernc.org/sqlite" // registers sqlite driver
+)
+
+// table of records
+const table = "logs"
+
+// Entry is a stored record
+type Entry struct {
+	Time    time.Time     // record time in UTC, or time of Write if record has none
+	Level   yell.Severity // severity
+	Name    string        // logger name
+	Caller  string        // request location (file.go:line), empty if missing
+	Message string        // message list
+	Detail  string        // error details
+}
+
+// Query selects entries of a Store. Zero values of fields match all entries.
+type Query struct {
+	From, To time.Time     // time range, From inclusive, To exclusive
+	MinLevel yell.Severity // minimum severity
+	Name     string        // logger name
+	Limit    int           // maximum number of entries, the latest ones
+}
+
+// Store is an io.Writer that persists records (text or JSON, see yellparse.Parse) in a
+// SQLite file. Records are inserted in batches every flush interval, when a batch is full
+// and on Flush or Close, see yellsql.Writer. It is safe for concurrent use.
+type Store struct {
+	*yellsql.Writer
+	db *sql.DB
+}
+
+// Open opens (or creates) SQLite file at path as a Store. Buffered records are inserted
+// every interval if positive, otherwise only when a batch is full or on Flush & Close.
+func Open(path string, interval time.Duration) (*Store, error) {
+	// times are written in UTC with a sortable layout, so time ranges compare as text
+	dsn := "file:" + (&url.URL{Path: path}).EscapedPath() +
+		"?_time_format=sqlite&_timezone=UTC&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err = yellsql.CreateTable(db, table); err == nil {
+		_, err = db.Exec("CREATE INDEX IF NOT EXISTS " + table + "_time ON " + table + " (time)")
+	}
+	var w *yellsql.Writer
+	if err == nil {
+		w, err = yellsql.NewWriter(db, table, interval)
+	}
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Store{w, db}, nil
+}
+
+// Query flushes buffered records and returns entries matching q in time order
+func (s *Store) Query(q Query) ([]Entry, error) {
+	if err := s.Flush(); err != nil {
+		return nil, err
+	}
+
+	where, args := " WHERE level >= ?", []interface{}{int(q.MinLevel)}
+	if !q.From.IsZero() {
+		where += " AND time >= ?"
+		args = append(args, q.From)
+	}
+	if !q.To.IsZero() {
+		where += " AND time < ?"
+		args = append(args, q.To)
+	}
+	if q.Name != "" {
+		where += " AND name = ?"
+		args = append(args, q.Name)
+	}
+	limit := -1
+	if q.Limit > 0 {
+		limit = q.Limit
+	}
+	args = append(args, limit)
+
+	// latest entries in ascending order
+	rows, err := s.db.Query("SELECT * FROM (SELECT rowid, time, level, name, caller, "+
+		"message, detail FROM "+table+where+" ORDER BY time DESC, rowid DESC LIMIT ?) "+
+		"ORDER BY time, rowid", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []Entry
+	for rows.Next() {
+		var (
+			e     Entry
+			id    int64
+			level int
+		)
+		if err = rows.Scan(&id, &e.Time, &level, &e.Name, &e.Caller, &e.Message,
+			&e.Detail); err != nil {
+			return list, err
+		}
+		e.Time, e.Level = e.Time.UTC(), yell.Severity(level)
+		list = append(list, e)
+	}
+	return list, rows.Err()
+}
+
+// Purge flushes buffered records and deletes entries older than before, for example to
+// limit size of the file. Returns number of deleted entries.
+func (s *Store) Purge(before time.Time) (int64, error) {
+	if err := s.Flush(); err != nil {
+		return 0, err
+	}
+	res, err := s.db.Exec("DELETE FROM "+table+" WHERE time < ?", before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// Close inserts buffered records and closes the file
+func (s *Store) Close() error {
+	err := s.Writer.Close()
+	if err2 := s.db.Close(); err == nil {
+		err = err2
+	}
+	return err
+}
@@ -0,0 +1,75 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yellsqlite
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jfcg/yell/v2"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs.db")
+	st, err := Open(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lg := yell.New(": lite:", st, yell.Sinfo)
+	lg.SetTimeMode(yell.Trfc3339nano)
+	lg.SetErrorDetail(true)
+
+	start := time.Now()
+	lg.Log(yell.Sinfo, "one")
+	lg.Log(yell.Swarn, "two")
+	lg.Log(yell.Serror, "three", errors.New("bad"))
+	mid := time.Now()
+	time.Sleep(2 * time.Millisecond)
+	lg.Log(yell.Sinfo, "four")
+
+	list, err := st.Query(Query{})
+	if err != nil || len(list) != 4 || list[0].Message != "one" || list[3].Message != "four" {
+		t.Fatal("query all failed", err, list)
+	}
+	e := list[2]
+	if e.Level != yell.Serror || e.Name != "lite" || e.Caller == "" ||
+		e.Message != "three bad" || e.Time.Before(start.Add(-time.Second)) {
+		t.Fatal("bad entry", e)
+	}
+
+	if list, err = st.Query(Query{MinLevel: yell.Swarn}); err != nil || len(list) != 2 {
+		t.Fatal("level query failed", err, list)
+	}
+	if list, err = st.Query(Query{From: mid}); err != nil || len(list) != 1 ||
+		list[0].Message != "four" {
+		t.Fatal("from query failed", err, list)
+	}
+	if list, err = st.Query(Query{To: mid, Limit: 2}); err != nil || len(list) != 2 ||
+		list[0].Message != "two" || list[1].Message != "three bad" {
+		t.Fatal("limit query failed", err, list)
+	}
+	if list, err = st.Query(Query{Name: "other"}); err != nil || len(list) != 0 {
+		t.Fatal("name query failed", err, list)
+	}
+	if n, err := st.Purge(mid); err != nil || n != 3 {
+		t.Fatal("purge failed", n, err)
+	}
+	if err = lg.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// entries persist
+	if st, err = Open(path, 0); err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+	if list, err = st.Query(Query{}); err != nil || len(list) != 1 {
+		t.Fatal("reopen failed", err, list)
+	}
+}
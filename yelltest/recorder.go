@@ -0,0 +1,123 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+// Package yelltest provides helpers for testing log output of packages using yell.
+package yelltest
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/jfcg/yell/v2"
+	"github.com/jfcg/yell/v2/yellparse"
+)
+
+// Record is a parsed log record
+type Record = yellparse.Record
+
+// ErrParse is returned by Recorder.Write for lines that are not yell records
+var ErrParse = errors.New("yelltest: cannot parse record")
+
+// Recorder is an io.Writer that captures parsed records written by yell loggers.
+// It is safe for concurrent use. Zero value is ready to use:
+//
+//	var rec yelltest.Recorder
+//	lg := yell.New(": mypkg:", &rec, yell.Sinfo)
+type Recorder struct {
+	mu      sync.Mutex
+	records []Record
+	partial []byte // incomplete last line
+}
+
+// Write parses complete lines in p as text or JSON records and stores them. Returns
+// ErrParse if a line is not a yell record.
+func (r *Recorder) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	buf := append(r.partial, p...)
+	r.partial = nil
+	var err error
+
+	for {
+		i := bytes.IndexByte(buf, '\n')
+		if i < 0 {
+			break
+		}
+		if n := len(r.records); buf[0] == '\t' && n > 0 {
+			// continuation line
+			last := &r.records[n-1]
+			if last.Detail != "" {
+				last.Detail += "\n"
+			}
+			last.Detail += string(buf[1:i])
+		} else if rec, ok := Parse(string(buf[:i])); ok {
+			r.records = append(r.records, rec)
+		} else {
+			err = ErrParse
+		}
+		buf = buf[i+1:]
+	}
+
+	if len(buf) > 0 {
+		r.partial = append([]byte(nil), buf...)
+	}
+	return len(p), err
+}
+
+// Records returns a copy of captured records
+func (r *Recorder) Records() []Record {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Record(nil), r.records...)
+}
+
+// Len returns number of captured records
+func (r *Recorder) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.records)
+}
+
+// Reset discards captured records
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	r.records, r.partial = nil, nil
+	r.mu.Unlock()
+}
+
+// ContainsMessage reports whether any captured record's message contains sub
+func (r *Recorder) ContainsMessage(sub string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.records {
+		if strings.Contains(r.records[i].Message, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// CountAtLevel returns number of captured records with severity level
+func (r *Recorder) CountAtLevel(level yell.Severity) (n int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.records {
+		if r.records[i].Level == level {
+			n++
+		}
+	}
+	return
+}
+
+// Parse parses a single text or JSON record without trailing newline, see yellparse.Parse
+func Parse(line string) (Record, bool) {
+	return yellparse.Parse(line)
+}
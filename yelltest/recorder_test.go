@@ -0,0 +1,77 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yelltest
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jfcg/yell/v2"
+)
+
+func TestRecorder(t *testing.T) {
+	var rec Recorder
+	lg := yell.New(": rec:", &rec, yell.Sinfo)
+
+	if err := lg.Log(yell.Sinfo, "hello", 1); err != nil {
+		t.Fatal(err)
+	}
+	lg.SetColor(yell.Calways, true)
+	if err := lg.Log(yell.Serror, "bad: thing", 2); err != nil {
+		t.Fatal(err)
+	}
+	lg.SetFormat(yell.Fjson)
+	if err := lg.Log(yell.Serror, "json", 3); err != nil {
+		t.Fatal(err)
+	}
+
+	if rec.Len() != 3 || rec.CountAtLevel(yell.Serror) != 2 ||
+		rec.CountAtLevel(yell.Swarn) != 0 || !rec.ContainsMessage("bad: thing 2") ||
+		rec.ContainsMessage("missing") {
+		t.Fatal("unexpected records:", rec.Records())
+	}
+	for _, r := range rec.Records() {
+		if r.Name != "rec" || r.Time == "" || r.Caller == "" {
+			t.Fatal("unexpected record:", r)
+		}
+	}
+
+	// partial writes & garbage
+	if _, err := rec.Write([]byte("2021: x:warn: a.go:3: ")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rec.Write([]byte("split\nnot a record\n")); err != ErrParse {
+		t.Fatal("must fail to parse")
+	}
+	r := rec.Records()[3]
+	if r.Name != "x" || r.Level != yell.Swarn || r.Caller != "a.go:3" || r.Message != "split" {
+		t.Fatal("unexpected record:", r)
+	}
+
+	// error details
+	lg.SetFormat(yell.Ftext)
+	lg.SetErrorDetail(true)
+	if err := lg.Log(yell.Serror, "wrapped", fmt.Errorf("a: %w", errors.New("b"))); err != nil {
+		t.Fatal(err)
+	}
+	if r := rec.Records()[4]; r.Detail != "a: b\ncaused by: b" {
+		t.Fatalf("unexpected detail: %q", r.Detail)
+	}
+	lg.SetFormat(yell.Fjson)
+	if err := lg.Log(yell.Serror, "json", fmt.Errorf("c: %w", errors.New("d"))); err != nil {
+		t.Fatal(err)
+	}
+	if r := rec.Records()[5]; r.Message != "json c: d" || r.Detail != "c: d\ncaused by: d" {
+		t.Fatalf("unexpected record: %v", r)
+	}
+
+	rec.Reset()
+	if rec.Len() != 0 {
+		t.Fatal("must be empty")
+	}
+}
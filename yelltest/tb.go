@@ -0,0 +1,68 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yelltest
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"github.com/jfcg/yell/v2"
+)
+
+// TB is an io.Writer that attaches records to a test case: error & fatal records are
+// reported with t.Error (failing the test), others with t.Log (shown on failure or with
+// go test -v). Loggers must not use TB after the test completes. Usage:
+//
+//	func TestMine(t *testing.T) {
+//		mypkg.Logger.UpdateWriter(yelltest.NewTB(t))
+//		...
+//	}
+type TB struct {
+	t       testing.TB
+	mu      sync.Mutex
+	partial []byte // incomplete last line
+	lastErr bool   // last record was reported with t.Error
+}
+
+// NewTB creates a TB writer for t
+func NewTB(t testing.TB) *TB {
+	return &TB{t: t}
+}
+
+// Write reports complete lines in p to the test, it is marked as a test helper
+func (w *TB) Write(p []byte) (int, error) {
+	w.t.Helper()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	buf := append(w.partial, p...)
+	w.partial = nil
+
+	for {
+		i := bytes.IndexByte(buf, '\n')
+		if i < 0 {
+			break
+		}
+		line := string(buf[:i])
+		if line == "" || line[0] != '\t' { // continuation lines follow their record
+			rec, ok := Parse(line)
+			w.lastErr = ok && rec.Level >= yell.Serror
+		}
+		if w.lastErr {
+			w.t.Error(line)
+		} else {
+			w.t.Log(line)
+		}
+		buf = buf[i+1:]
+	}
+
+	if len(buf) > 0 {
+		w.partial = append([]byte(nil), buf...)
+	}
+	return len(p), nil
+}
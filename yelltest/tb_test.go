@@ -0,0 +1,53 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yelltest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jfcg/yell/v2"
+)
+
+// fakeTB records Log, Error & Helper calls
+type fakeTB struct {
+	testing.TB
+	logs, errs []string
+	helpers    int
+}
+
+func (f *fakeTB) Helper() {
+	f.helpers++
+}
+
+func (f *fakeTB) Log(args ...interface{}) {
+	f.logs = append(f.logs, fmt.Sprint(args...))
+}
+
+func (f *fakeTB) Error(args ...interface{}) {
+	f.errs = append(f.errs, fmt.Sprint(args...))
+}
+
+func TestTB(t *testing.T) {
+	var f fakeTB
+	lg := yell.New(": tb:", NewTB(&f), yell.Sinfo)
+
+	for _, l := range [...]yell.Severity{yell.Sinfo, yell.Swarn, yell.Serror, yell.Sfatal} {
+		if err := lg.Log(l, "msg", l); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(f.logs) != 2 || len(f.errs) != 2 || f.helpers != 4 {
+		t.Fatal("unexpected routing:", f.logs, f.errs)
+	}
+
+	// real test, must pass
+	lg2 := yell.New(": tb:", NewTB(t), yell.Sinfo)
+	if err := lg2.Log(yell.Swarn, "shown only on failure or -v"); err != nil {
+		t.Fatal(err)
+	}
+}
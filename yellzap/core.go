@@ -0,0 +1,118 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+// Package yellzap provides a zapcore.Core backed by a yell Logger, so zap instrumented
+// dependencies log through yell's levels, encoders & writers. It is a separate module, so
+// yell itself does not depend on zap.
+//
+//	zl := zap.New(yellzap.NewCore(&lg))
+//	zl.Warn("slow query", zap.Duration("took", d)) // logged by lg
+package yellzap
+
+import (
+	"sort"
+
+	"github.com/jfcg/yell/v2"
+	"go.uber.org/zap/zapcore"
+)
+
+// Core is a zapcore.Core that logs entries to a yell Logger. Level checks are done by the
+// Logger, see yell.Logger.SetLevel.
+type Core struct {
+	lg     *yell.Logger
+	fields []zapcore.Field // from With
+}
+
+var _ zapcore.Core = (*Core)(nil)
+
+// zapPkgs are skipped to find request locations, see yell.Logger.LogFrom
+var zapPkgs = []string{"go.uber.org/zap", "go.uber.org/zap/zapcore",
+	"github.com/jfcg/yell/yellzap"}
+
+// NewCore creates a Core that logs to lg, so later changes to lg (like its level or
+// writer) apply. Request locations are found by skipping zap frames, see
+// yell.Logger.LogFrom.
+func NewCore(lg *yell.Logger) *Core {
+	return &Core{lg: lg}
+}
+
+// Severity returns yell severity of zap level: dpanic, panic & fatal are yell.Sfatal.
+func Severity(level zapcore.Level) yell.Severity {
+	switch {
+	case level < zapcore.InfoLevel:
+		return yell.Sdebug
+	case level == zapcore.InfoLevel:
+		return yell.Sinfo
+	case level == zapcore.WarnLevel:
+		return yell.Swarn
+	case level == zapcore.ErrorLevel:
+		return yell.Serror
+	}
+	return yell.Sfatal
+}
+
+// Enabled checks if records of level are logged by Logger
+func (c *Core) Enabled(level zapcore.Level) bool {
+	s := Severity(level)
+	return c.lg.GetLevel() <= s && s <= c.lg.GetMaxLevel()
+}
+
+// With returns a copy of Core with fields added to its entries
+func (c *Core) With(fields []zapcore.Field) zapcore.Core {
+	if len(fields) == 0 {
+		return c
+	}
+	f := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	return &Core{c.lg, append(append(f, c.fields...), fields...)}
+}
+
+// Check adds Core to ce if ent is enabled
+func (c *Core) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write logs ent with its message, error fields and other fields of Core & fields, in
+// order. Non-empty logger name of ent is a "logger" field. Stack & caller of ent are
+// ignored, Logger has its own, see yell.Logger.SetErrorDetail.
+func (c *Core) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	msg := make([]interface{}, 0, len(c.fields)+len(fields)+2)
+	msg = append(msg, ent.Message)
+	if ent.LoggerName != "" {
+		msg = append(msg, yell.Any("logger", ent.LoggerName))
+	}
+	msg = appendFields(appendFields(msg, c.fields), fields)
+	return c.lg.LogFrom(zapPkgs, Severity(ent.Level), msg...)
+}
+
+// appendFields appends zap fields to msg as yell fields, errors as message members
+func appendFields(msg []interface{}, fields []zapcore.Field) []interface{} {
+	for _, f := range fields {
+		if err, ok := f.Interface.(error); ok && f.Type == zapcore.ErrorType {
+			msg = append(msg, err) // as message member for error details
+			continue
+		}
+		enc := zapcore.NewMapObjectEncoder()
+		f.AddTo(enc)
+
+		keys := make([]string, 0, len(enc.Fields))
+		for k := range enc.Fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			msg = append(msg, yell.Any(k, enc.Fields[k]))
+		}
+	}
+	return msg
+}
+
+// Sync flushes Logger, see yell.Logger.Flush
+func (c *Core) Sync() error {
+	return c.lg.Flush()
+}
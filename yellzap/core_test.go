@@ -0,0 +1,60 @@
+/*	Copyright (c) 2021, Serhat Şevki Dinçer.
+	This Source Code Form is subject to the terms of the Mozilla Public
+	License, v. 2.0. If a copy of the MPL was not distributed with this
+	file, You can obtain one at http://mozilla.org/MPL/2.0/.
+*/
+
+package yellzap
+
+import (
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/jfcg/yell/v2"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestCore(t *testing.T) {
+	var recs []yell.Record
+	lg := yell.New(": zp:", ioutil.Discard, yell.Sinfo)
+	lg.AddObserver(func(r yell.Record) { recs = append(recs, r) })
+
+	zl := zap.New(NewCore(&lg)).Named("db").With(zap.String("user", "ann"))
+	zl.Warn("query failed", zap.Int("attempt", 2), zap.Error(errors.New("timeout")))
+	zl.Debug("filtered")
+	zl.Info("plain")
+	if zl.Sync() != nil {
+		t.Fatal("sync failed")
+	}
+
+	if len(recs) != 2 {
+		t.Fatal("expected two records:", recs)
+	}
+	// test frames belong to yellzap, only core & zap frames must be skipped
+	skipped := map[string]bool{"core.go": true, "logger.go": true, "entry.go": true}
+	r := recs[0]
+	if r.Level != yell.Swarn || r.Text() != "query failed timeout" || skipped[r.File] ||
+		len(r.Fields) != 3 || r.Fields[0].Value != "db" || r.Fields[1].Value != "ann" ||
+		r.Fields[2].Key != "attempt" || recs[1].Msg != "plain" || len(recs[1].Fields) != 2 {
+		t.Fatal("unexpected records:", recs)
+	}
+
+	// later changes of lg apply
+	var b2 strings.Builder
+	lg.SetLevel(yell.Sdebug)
+	lg.UpdateWriter(&b2)
+	zl.Info("later")
+	if len(recs) != 3 || !strings.Contains(b2.String(), " later logger=db user=ann\n") {
+		t.Fatal("Core must log through lg:", b2.String())
+	}
+
+	c := NewCore(&lg)
+	lg.SetLevel(yell.Sinfo)
+	if c.Enabled(zapcore.DebugLevel) || !c.Enabled(zapcore.ErrorLevel) || c.With(nil) != c ||
+		Severity(zapcore.DPanicLevel) != yell.Sfatal {
+		t.Fatal("bad levels")
+	}
+}
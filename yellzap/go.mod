@@ -0,0 +1,10 @@
+module github.com/jfcg/yell/yellzap
+
+go 1.15
+
+require (
+	github.com/jfcg/yell/v2 v2.0.0
+	go.uber.org/zap v1.28.0
+)
+
+replace github.com/jfcg/yell/v2 => ../